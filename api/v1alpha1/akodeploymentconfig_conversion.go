@@ -0,0 +1,418 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1beta1"
+)
+
+// ConvertTo converts this AKODeploymentConfig to the hub version (v1beta1).
+// The v1beta1 fields which can't be expressed in v1alpha1 are restored from
+// the conversion data annotation set by ConvertFrom.
+func (src *AKODeploymentConfig) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.AKODeploymentConfig)
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	convertSpecToHub(&in.Spec, &dst.Spec)
+	convertStatusToHub(&in.Status, &dst.Status)
+
+	restored := &v1beta1.AKODeploymentConfig{}
+	ok, err := utilconversion.UnmarshalData(dst, restored)
+	if err != nil || !ok {
+		return err
+	}
+	// the node port selector is restored unless it was changed through
+	// v1alpha1 since it was stored
+	if nodePortSelectorFromHub(restored.Spec.ExtraConfigs.NodePortSelector) == in.Spec.ExtraConfigs.NodePortSelector {
+		dst.Spec.ExtraConfigs.NodePortSelector = restored.Spec.ExtraConfigs.NodePortSelector
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version. The
+// hub is stored in the conversion data annotation when some of its fields
+// can't be expressed in v1alpha1.
+func (dst *AKODeploymentConfig) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.AKODeploymentConfig)
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	convertSpecFromHub(&in.Spec, &dst.Spec)
+	convertStatusFromHub(&in.Status, &dst.Status)
+
+	if isLossyConversion(src) {
+		return utilconversion.MarshalData(src, dst)
+	}
+	return nil
+}
+
+// ConvertTo converts this AKODeploymentConfigList to the hub version (v1beta1)
+func (src *AKODeploymentConfigList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.AKODeploymentConfigList)
+	dst.ListMeta = *src.ListMeta.DeepCopy()
+	dst.Items = make([]v1beta1.AKODeploymentConfig, len(src.Items))
+	for i := range src.Items {
+		if err := src.Items[i].ConvertTo(&dst.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version
+func (dst *AKODeploymentConfigList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.AKODeploymentConfigList)
+	dst.ListMeta = *src.ListMeta.DeepCopy()
+	dst.Items = make([]AKODeploymentConfig, len(src.Items))
+	for i := range src.Items {
+		if err := dst.Items[i].ConvertFrom(&src.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLossyConversion returns if converting src to v1alpha1 loses data
+func isLossyConversion(src *v1beta1.AKODeploymentConfig) bool {
+	selector := src.Spec.ExtraConfigs.NodePortSelector
+	return !equality.Semantic.DeepEqual(nodePortSelectorToHub(nodePortSelectorFromHub(selector)), selector)
+}
+
+// nodePortSelectorToHub converts the key/value node port selector to a label
+// selector, nil when no key or value is set
+func nodePortSelectorToHub(in NodePortSelector) *metav1.LabelSelector {
+	if in.Key == "" && in.Value == "" {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: map[string]string{in.Key: in.Value}}
+}
+
+// nodePortSelectorFromHub keeps the first label, by key order, of the label
+// selector. Match expressions can't be converted.
+func nodePortSelectorFromHub(in *metav1.LabelSelector) NodePortSelector {
+	if in == nil || len(in.MatchLabels) == 0 {
+		return NodePortSelector{}
+	}
+	keys := make([]string, 0, len(in.MatchLabels))
+	for k := range in.MatchLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return NodePortSelector{Key: keys[0], Value: in.MatchLabels[keys[0]]}
+}
+
+func convertSpecToHub(in *AKODeploymentConfigSpec, out *v1beta1.AKODeploymentConfigSpec) {
+	out.CloudName = in.CloudName
+	out.Controller = in.Controller
+	out.ControllerVersion = in.ControllerVersion
+	out.ServiceEngineGroup = in.ServiceEngineGroup
+	out.ServiceEngineGroupTemplate = (*v1beta1.ServiceEngineGroupTemplate)(in.ServiceEngineGroupTemplate)
+	out.ServiceEngineGroupPerCluster = in.ServiceEngineGroupPerCluster
+	out.ClusterSelector = in.ClusterSelector
+	out.WorkloadCredentialRef = (*v1beta1.SecretRef)((*SecretRef)(in.WorkloadCredentialRef))
+	out.AviUserPasswordRotationInterval = in.AviUserPasswordRotationInterval
+	out.AviUserPasswordPolicy = convertPasswordPolicyToHub(in.AviUserPasswordPolicy)
+	out.AdminCredentialRef = (*v1beta1.SecretRef)((*SecretRef)(in.AdminCredentialRef))
+	out.CertificateAuthorityRef = (*v1beta1.SecretRef)((*SecretRef)(in.CertificateAuthorityRef))
+	out.Tenant = convertTenantToHub(in.Tenant)
+	out.RoleProfile = convertRoleProfileToHub(in.RoleProfile)
+	out.DataNetwork = v1beta1.DataNetwork{
+		Name:              in.DataNetwork.Name,
+		CIDR:              in.DataNetwork.CIDR,
+		V6CIDR:            in.DataNetwork.V6CIDR,
+		IPPools:           convertSlice(in.DataNetwork.IPPools, func(p IPPool) v1beta1.IPPool { return v1beta1.IPPool(p) }),
+		ClusterIPPoolSize: in.DataNetwork.ClusterIPPoolSize,
+	}
+	out.ControlPlaneNetwork = v1beta1.ControlPlaneNetwork(in.ControlPlaneNetwork)
+	convertExtraConfigsToHub(&in.ExtraConfigs, &out.ExtraConfigs)
+	out.VIPPoolLowThreshold = in.VIPPoolLowThreshold
+	out.Delivery = v1beta1.AKODelivery(in.Delivery)
+}
+
+func convertSpecFromHub(in *v1beta1.AKODeploymentConfigSpec, out *AKODeploymentConfigSpec) {
+	out.CloudName = in.CloudName
+	out.Controller = in.Controller
+	out.ControllerVersion = in.ControllerVersion
+	out.ServiceEngineGroup = in.ServiceEngineGroup
+	out.ServiceEngineGroupTemplate = (*ServiceEngineGroupTemplate)(in.ServiceEngineGroupTemplate)
+	out.ServiceEngineGroupPerCluster = in.ServiceEngineGroupPerCluster
+	out.ClusterSelector = in.ClusterSelector
+	out.WorkloadCredentialRef = SecretReference((*SecretRef)(in.WorkloadCredentialRef))
+	out.AviUserPasswordRotationInterval = in.AviUserPasswordRotationInterval
+	out.AviUserPasswordPolicy = convertPasswordPolicyFromHub(in.AviUserPasswordPolicy)
+	out.AdminCredentialRef = SecretReference((*SecretRef)(in.AdminCredentialRef))
+	out.CertificateAuthorityRef = SecretReference((*SecretRef)(in.CertificateAuthorityRef))
+	out.Tenant = convertTenantFromHub(in.Tenant)
+	out.RoleProfile = convertRoleProfileFromHub(in.RoleProfile)
+	out.DataNetwork = DataNetwork{
+		Name:              in.DataNetwork.Name,
+		CIDR:              in.DataNetwork.CIDR,
+		V6CIDR:            in.DataNetwork.V6CIDR,
+		IPPools:           convertSlice(in.DataNetwork.IPPools, func(p v1beta1.IPPool) IPPool { return IPPool(p) }),
+		ClusterIPPoolSize: in.DataNetwork.ClusterIPPoolSize,
+	}
+	out.ControlPlaneNetwork = ControlPlaneNetwork(in.ControlPlaneNetwork)
+	convertExtraConfigsFromHub(&in.ExtraConfigs, &out.ExtraConfigs)
+	out.VIPPoolLowThreshold = in.VIPPoolLowThreshold
+	out.Delivery = AKODelivery(in.Delivery)
+}
+
+func convertExtraConfigsToHub(in *ExtraConfigs, out *v1beta1.ExtraConfigs) {
+	out.ReplicaCount = in.ReplicaCount
+	out.PrimaryInstance = in.PrimaryInstance
+	out.Log = v1beta1.AKOLogConfig(in.Log)
+	out.FullSyncFrequency = in.FullSyncFrequency
+	out.ApiServerPort = in.ApiServerPort
+	out.EnableEvents = in.EnableEvents
+	out.DisableStaticRouteSync = in.DisableStaticRouteSync
+	out.CniPlugin = in.CniPlugin
+	out.EnableEVH = in.EnableEVH
+	out.Layer7Only = in.Layer7Only
+	out.NamespaceSelector = v1beta1.NamespaceSelector(in.NamespaceSelector)
+	out.ServicesAPI = in.ServicesAPI
+	out.VIPPerNamespace = in.VIPPerNamespace
+	out.IstioEnabled = in.IstioEnabled
+	out.BlockedNamespaceList = in.BlockedNamespaceList
+	out.IpFamily = in.IpFamily
+	out.UseDefaultSecretsOnly = in.UseDefaultSecretsOnly
+	out.NetworksConfig = v1beta1.NetworksConfig{
+		EnableRHI:     in.NetworksConfig.EnableRHI,
+		BGPPeerLabels: in.NetworksConfig.BGPPeerLabels,
+		NsxtT1LR:      in.NetworksConfig.NsxtT1LR,
+		VipNetworkList: convertSlice(in.NetworksConfig.VipNetworkList, func(n VIPNetwork) v1beta1.VIPNetwork {
+			return v1beta1.VIPNetwork{
+				NetworkName: n.NetworkName,
+				CIDR:        n.CIDR,
+				V6CIDR:      n.V6CIDR,
+				IPPools:     convertSlice(n.IPPools, func(p IPPool) v1beta1.IPPool { return v1beta1.IPPool(p) }),
+			}
+		}),
+	}
+	out.IngressConfigs = v1beta1.AKOIngressConfig{
+		DisableIngressClass:      in.IngressConfigs.DisableIngressClass,
+		DefaultIngressController: in.IngressConfigs.DefaultIngressController,
+		ServiceType:              in.IngressConfigs.ServiceType,
+		ShardVSSize:              in.IngressConfigs.ShardVSSize,
+		PassthroughShardSize:     in.IngressConfigs.PassthroughShardSize,
+		NodeNetworkList: convertSlice(in.IngressConfigs.NodeNetworkList, func(n NodeNetwork) v1beta1.NodeNetwork {
+			return v1beta1.NodeNetwork(n)
+		}),
+		NoPGForSNI: in.IngressConfigs.NoPGForSNI,
+		EnableMCI:  in.IngressConfigs.EnableMCI,
+	}
+	out.L4Configs = v1beta1.AKOL4Config(in.L4Configs)
+	out.NodePortSelector = nodePortSelectorToHub(in.NodePortSelector)
+	out.Rbac = v1beta1.AKORbacConfig(in.Rbac)
+	out.FeatureGates = v1beta1.FeatureGates(in.FeatureGates)
+}
+
+func convertExtraConfigsFromHub(in *v1beta1.ExtraConfigs, out *ExtraConfigs) {
+	out.ReplicaCount = in.ReplicaCount
+	out.PrimaryInstance = in.PrimaryInstance
+	out.Log = AKOLogConfig(in.Log)
+	out.FullSyncFrequency = in.FullSyncFrequency
+	out.ApiServerPort = in.ApiServerPort
+	out.EnableEvents = in.EnableEvents
+	out.DisableStaticRouteSync = in.DisableStaticRouteSync
+	out.CniPlugin = in.CniPlugin
+	out.EnableEVH = in.EnableEVH
+	out.Layer7Only = in.Layer7Only
+	out.NamespaceSelector = NamespaceSelector(in.NamespaceSelector)
+	out.ServicesAPI = in.ServicesAPI
+	out.VIPPerNamespace = in.VIPPerNamespace
+	out.IstioEnabled = in.IstioEnabled
+	out.BlockedNamespaceList = in.BlockedNamespaceList
+	out.IpFamily = in.IpFamily
+	out.UseDefaultSecretsOnly = in.UseDefaultSecretsOnly
+	out.NetworksConfig = NetworksConfig{
+		EnableRHI:     in.NetworksConfig.EnableRHI,
+		BGPPeerLabels: in.NetworksConfig.BGPPeerLabels,
+		NsxtT1LR:      in.NetworksConfig.NsxtT1LR,
+		VipNetworkList: convertSlice(in.NetworksConfig.VipNetworkList, func(n v1beta1.VIPNetwork) VIPNetwork {
+			return VIPNetwork{
+				NetworkName: n.NetworkName,
+				CIDR:        n.CIDR,
+				V6CIDR:      n.V6CIDR,
+				IPPools:     convertSlice(n.IPPools, func(p v1beta1.IPPool) IPPool { return IPPool(p) }),
+			}
+		}),
+	}
+	out.IngressConfigs = AKOIngressConfig{
+		DisableIngressClass:      in.IngressConfigs.DisableIngressClass,
+		DefaultIngressController: in.IngressConfigs.DefaultIngressController,
+		ServiceType:              in.IngressConfigs.ServiceType,
+		ShardVSSize:              in.IngressConfigs.ShardVSSize,
+		PassthroughShardSize:     in.IngressConfigs.PassthroughShardSize,
+		NodeNetworkList: convertSlice(in.IngressConfigs.NodeNetworkList, func(n v1beta1.NodeNetwork) NodeNetwork {
+			return NodeNetwork(n)
+		}),
+		NoPGForSNI: in.IngressConfigs.NoPGForSNI,
+		EnableMCI:  in.IngressConfigs.EnableMCI,
+	}
+	out.L4Configs = AKOL4Config(in.L4Configs)
+	out.NodePortSelector = nodePortSelectorFromHub(in.NodePortSelector)
+	out.Rbac = AKORbacConfig(in.Rbac)
+	out.FeatureGates = FeatureGates(in.FeatureGates)
+}
+
+func convertTenantToHub(in AVITenant) v1beta1.AVITenant {
+	out := v1beta1.AVITenant{Context: in.Context, Name: in.Name}
+	if in.PerCluster != nil {
+		out.PerCluster = &v1beta1.AVIClusterTenant{
+			NameTemplate:   in.PerCluster.NameTemplate,
+			ConfigSettings: v1beta1.AVITenantConfigSettings(in.PerCluster.ConfigSettings),
+		}
+	}
+	return out
+}
+
+func convertTenantFromHub(in v1beta1.AVITenant) AVITenant {
+	out := AVITenant{Context: in.Context, Name: in.Name}
+	if in.PerCluster != nil {
+		out.PerCluster = &AVIClusterTenant{
+			NameTemplate:   in.PerCluster.NameTemplate,
+			ConfigSettings: AVITenantConfigSettings(in.PerCluster.ConfigSettings),
+		}
+	}
+	return out
+}
+
+func convertPasswordPolicyToHub(in *AviPasswordPolicy) *v1beta1.AviPasswordPolicy {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.AviPasswordPolicy{
+		Length: in.Length,
+		CharacterClasses: convertSlice(in.CharacterClasses, func(c PasswordCharacterClass) v1beta1.PasswordCharacterClass {
+			return v1beta1.PasswordCharacterClass(c)
+		}),
+		ExcludedCharacters: in.ExcludedCharacters,
+	}
+}
+
+func convertPasswordPolicyFromHub(in *v1beta1.AviPasswordPolicy) *AviPasswordPolicy {
+	if in == nil {
+		return nil
+	}
+	return &AviPasswordPolicy{
+		Length: in.Length,
+		CharacterClasses: convertSlice(in.CharacterClasses, func(c v1beta1.PasswordCharacterClass) PasswordCharacterClass {
+			return PasswordCharacterClass(c)
+		}),
+		ExcludedCharacters: in.ExcludedCharacters,
+	}
+}
+
+func convertRoleProfileToHub(in *AviRoleProfile) *v1beta1.AviRoleProfile {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.AviRoleProfile{
+		Name:               in.Name,
+		Permissions:        convertSlice(in.Permissions, func(p AviPermission) v1beta1.AviPermission { return v1beta1.AviPermission(p) }),
+		RemovedPermissions: in.RemovedPermissions,
+	}
+}
+
+func convertRoleProfileFromHub(in *v1beta1.AviRoleProfile) *AviRoleProfile {
+	if in == nil {
+		return nil
+	}
+	return &AviRoleProfile{
+		Name:               in.Name,
+		Permissions:        convertSlice(in.Permissions, func(p v1beta1.AviPermission) AviPermission { return AviPermission(p) }),
+		RemovedPermissions: in.RemovedPermissions,
+	}
+}
+
+func convertStatusToHub(in *AKODeploymentConfigStatus, out *v1beta1.AKODeploymentConfigStatus) {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Conditions = in.Conditions
+	out.Clusters = convertSlice(in.Clusters, func(c ClusterStatus) v1beta1.ClusterStatus {
+		return v1beta1.ClusterStatus{
+			Name:                     c.Name,
+			Namespace:                c.Namespace,
+			Phase:                    v1beta1.ClusterPhase(c.Phase),
+			ValuesHash:               c.ValuesHash,
+			LastError:                c.LastError,
+			LastTransitionTime:       c.LastTransitionTime,
+			LastPasswordRotationTime: c.LastPasswordRotationTime,
+			ValuesHashHistory:        convertSlice(c.ValuesHashHistory, func(h ValuesHashRecord) v1beta1.ValuesHashRecord { return v1beta1.ValuesHashRecord(h) }),
+			Delivery:                 v1beta1.AKODelivery(c.Delivery),
+		}
+	})
+	out.SelectedClusters = in.SelectedClusters
+	out.ReadyClusters = in.ReadyClusters
+	out.FailedClusters = in.FailedClusters
+	out.StaticIPRanges = convertSlice(in.StaticIPRanges, func(p NetworkIPPool) v1beta1.NetworkIPPool {
+		return v1beta1.NetworkIPPool{NetworkName: p.NetworkName, IPPool: v1beta1.IPPool(p.IPPool)}
+	})
+	out.Subnets = convertSlice(in.Subnets, func(n NetworkSubnet) v1beta1.NetworkSubnet { return v1beta1.NetworkSubnet(n) })
+	out.UsableNetworks = in.UsableNetworks
+	out.VIPPools = convertSlice(in.VIPPools, func(p VIPPoolStatus) v1beta1.VIPPoolStatus {
+		return v1beta1.VIPPoolStatus{NetworkName: p.NetworkName, IPPool: v1beta1.IPPool(p.IPPool), Total: p.Total, Used: p.Used, Free: p.Free}
+	})
+	out.ClusterIPPools = convertSlice(in.ClusterIPPools, func(p ClusterIPPool) v1beta1.ClusterIPPool {
+		return v1beta1.ClusterIPPool{Name: p.Name, Namespace: p.Namespace, IPPool: v1beta1.IPPool(p.IPPool)}
+	})
+	out.ClusterServiceEngineGroups = convertSlice(in.ClusterServiceEngineGroups, func(g ClusterServiceEngineGroup) v1beta1.ClusterServiceEngineGroup {
+		return v1beta1.ClusterServiceEngineGroup(g)
+	})
+	out.ClusterTenants = convertSlice(in.ClusterTenants, func(t ClusterTenant) v1beta1.ClusterTenant { return v1beta1.ClusterTenant(t) })
+}
+
+func convertStatusFromHub(in *v1beta1.AKODeploymentConfigStatus, out *AKODeploymentConfigStatus) {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.Conditions = in.Conditions
+	out.Clusters = convertSlice(in.Clusters, func(c v1beta1.ClusterStatus) ClusterStatus {
+		return ClusterStatus{
+			Name:                     c.Name,
+			Namespace:                c.Namespace,
+			Phase:                    ClusterPhase(c.Phase),
+			ValuesHash:               c.ValuesHash,
+			LastError:                c.LastError,
+			LastTransitionTime:       c.LastTransitionTime,
+			LastPasswordRotationTime: c.LastPasswordRotationTime,
+			ValuesHashHistory:        convertSlice(c.ValuesHashHistory, func(h v1beta1.ValuesHashRecord) ValuesHashRecord { return ValuesHashRecord(h) }),
+			Delivery:                 AKODelivery(c.Delivery),
+		}
+	})
+	out.SelectedClusters = in.SelectedClusters
+	out.ReadyClusters = in.ReadyClusters
+	out.FailedClusters = in.FailedClusters
+	out.StaticIPRanges = convertSlice(in.StaticIPRanges, func(p v1beta1.NetworkIPPool) NetworkIPPool {
+		return NetworkIPPool{NetworkName: p.NetworkName, IPPool: IPPool(p.IPPool)}
+	})
+	out.Subnets = convertSlice(in.Subnets, func(n v1beta1.NetworkSubnet) NetworkSubnet { return NetworkSubnet(n) })
+	out.UsableNetworks = in.UsableNetworks
+	out.VIPPools = convertSlice(in.VIPPools, func(p v1beta1.VIPPoolStatus) VIPPoolStatus {
+		return VIPPoolStatus{NetworkName: p.NetworkName, IPPool: IPPool(p.IPPool), Total: p.Total, Used: p.Used, Free: p.Free}
+	})
+	out.ClusterIPPools = convertSlice(in.ClusterIPPools, func(p v1beta1.ClusterIPPool) ClusterIPPool {
+		return ClusterIPPool{Name: p.Name, Namespace: p.Namespace, IPPool: IPPool(p.IPPool)}
+	})
+	out.ClusterServiceEngineGroups = convertSlice(in.ClusterServiceEngineGroups, func(g v1beta1.ClusterServiceEngineGroup) ClusterServiceEngineGroup {
+		return ClusterServiceEngineGroup(g)
+	})
+	out.ClusterTenants = convertSlice(in.ClusterTenants, func(t v1beta1.ClusterTenant) ClusterTenant { return ClusterTenant(t) })
+}
+
+func convertSlice[S, D any](in []S, convert func(S) D) []D {
+	if in == nil {
+		return nil
+	}
+	out := make([]D, len(in))
+	for i := range in {
+		out[i] = convert(in[i])
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1beta1"
+)
+
+func TestFuzzyConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("for AKODeploymentConfig", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme: scheme,
+		Hub:    &v1beta1.AKODeploymentConfig{},
+		Spoke:  &AKODeploymentConfig{},
+	}))
+}
+
+func TestNodePortSelectorConversion(t *testing.T) {
+	g := NewWithT(t)
+
+	src := &AKODeploymentConfig{}
+	src.Spec.ExtraConfigs.NodePortSelector = NodePortSelector{Key: "node-role", Value: "lb"}
+	hub := &v1beta1.AKODeploymentConfig{}
+	g.Expect(src.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub.Spec.ExtraConfigs.NodePortSelector).To(Equal(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"node-role": "lb"},
+	}))
+
+	// a selector without a v1alpha1 representation is kept in the conversion
+	// data annotation and restored unless it's changed through v1alpha1
+	hub.Spec.ExtraConfigs.NodePortSelector.MatchLabels["zone"] = "a"
+	spoke := &AKODeploymentConfig{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	g.Expect(spoke.Spec.ExtraConfigs.NodePortSelector).To(Equal(NodePortSelector{Key: "node-role", Value: "lb"}))
+	g.Expect(spoke.Annotations).To(HaveKey(utilconversion.DataAnnotation))
+
+	restored := &v1beta1.AKODeploymentConfig{}
+	g.Expect(spoke.DeepCopy().ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec).To(Equal(hub.Spec))
+	g.Expect(restored.Annotations).NotTo(HaveKey(utilconversion.DataAnnotation))
+
+	spoke.Spec.ExtraConfigs.NodePortSelector.Value = "ingress"
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec.ExtraConfigs.NodePortSelector).To(Equal(&metav1.LabelSelector{
+		MatchLabels: map[string]string{"node-role": "ingress"},
+	}))
+}
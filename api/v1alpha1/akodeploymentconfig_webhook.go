@@ -8,7 +8,11 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"reflect"
 	"regexp"
+	"strings"
+	"text/template"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -22,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 )
 
 // log is for logging in this package.
@@ -150,12 +155,19 @@ func (r *AKODeploymentConfig) validateAVI(old *AKODeploymentConfig) field.ErrorL
 		allErrs = append(allErrs, err)
 	}
 
+	avi := aviClient
 	if !runTest {
 		username := string(adminCredential.Data["username"][:])
 		password := string(adminCredential.Data["password"][:])
 		certificate := string(aviControllerCA.Data["certificateAuthorityData"][:])
 
-		client, fieldErr := r.validateAviAccount(username, password, certificate, "")
+		config := &aviclient.AviClientConfig{
+			ServerIP: r.Spec.Controller,
+			Username: username,
+			Password: password,
+			CA:       certificate,
+		}
+		client, fieldErr := r.validateAviAccount(config, "")
 		if fieldErr != nil {
 			allErrs = append(allErrs, fieldErr)
 			return allErrs
@@ -170,42 +182,61 @@ func (r *AKODeploymentConfig) validateAVI(old *AKODeploymentConfig) field.ErrorL
 		// update controller version
 		r.Spec.ControllerVersion = version
 		// reinit client with the real controller version
-		client, fieldErr = r.validateAviAccount(username, password, certificate, version)
+		client, fieldErr = r.validateAviAccount(config, version)
 		if fieldErr != nil {
 			allErrs = append(allErrs, fieldErr)
 			return allErrs
 		}
-		aviClient = client
+		avi = client
 	}
 
 	if err := r.validateReplicaCount(); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	if err := r.validateClusterIPPoolSize(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := r.validateAviUserPasswordRotationInterval(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := r.validateAviTenantNameTemplate(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := r.validateAviUserPasswordPolicy(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := r.validateAviRoleProfile(); err != nil {
+		allErrs = append(allErrs, err)
+	}
 
 	if old == nil {
 		// when old is nil, it is creating a new AKODeploymentConfig object, check following fields
-		if err := r.validateAviCloud(); err != nil {
+		if err := r.validateAviCloud(avi); err != nil {
 			allErrs = append(allErrs, err)
 		}
-		if err := r.validateAviServiceEngineGroup(); err != nil {
+		if err := r.validateAviServiceEngineGroup(avi); err != nil {
 			allErrs = append(allErrs, err)
 		}
-		if err := r.validateAviControlPlaneNetworks(); err != nil {
+		if err := r.validateAviControlPlaneNetworks(avi); err != nil {
+			allErrs = append(allErrs, err...)
+		}
+		if err := r.validateAviDataNetworks(avi); err != nil {
 			allErrs = append(allErrs, err...)
 		}
-		if err := r.validateAviDataNetworks(); err != nil {
+		if err := r.validateAviVIPNetworks(avi); err != nil {
 			allErrs = append(allErrs, err...)
 		}
 	} else {
 		// when old is not nil, it is updating an existing AKODeploymentConfig object,
 		// only check changed fields
 		if old.Spec.CloudName != r.Spec.CloudName {
-			if err := r.validateAviCloud(); err != nil {
+			if err := r.validateAviCloud(avi); err != nil {
 				allErrs = append(allErrs, err)
 			}
 		}
-		if old.Spec.ServiceEngineGroup != r.Spec.ServiceEngineGroup {
-			if err := r.validateAviServiceEngineGroup(); err != nil {
+		if old.Spec.ServiceEngineGroup != r.Spec.ServiceEngineGroup ||
+			!reflect.DeepEqual(old.Spec.ServiceEngineGroupTemplate, r.Spec.ServiceEngineGroupTemplate) {
+			if err := r.validateAviServiceEngineGroup(avi); err != nil {
 				allErrs = append(allErrs, err)
 			}
 		}
@@ -218,8 +249,27 @@ func (r *AKODeploymentConfig) validateAVI(old *AKODeploymentConfig) field.ErrorL
 				"field should not be changed"))
 		}
 		if (old.Spec.DataNetwork.Name != r.Spec.DataNetwork.Name) ||
-			(old.Spec.DataNetwork.CIDR != r.Spec.DataNetwork.CIDR) {
-			if err := r.validateAviDataNetworks(); err != nil {
+			(old.Spec.DataNetwork.CIDR != r.Spec.DataNetwork.CIDR) ||
+			(old.Spec.DataNetwork.V6CIDR != r.Spec.DataNetwork.V6CIDR) {
+			if err := r.validateAviDataNetworks(avi); err != nil {
+				allErrs = append(allErrs, err...)
+			}
+		}
+		// the delivery backends don't hand AKO over to each other
+		if old.Spec.Delivery != r.Spec.Delivery {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "delivery"),
+				r.Spec.Delivery,
+				"field should not be changed"))
+		}
+		// AKO and the Avi users of the clusters can't move between the
+		// tenants
+		if (old.Spec.Tenant.PerCluster == nil) != (r.Spec.Tenant.PerCluster == nil) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "tenant", "perCluster"),
+				r.Spec.Tenant.PerCluster,
+				"field should not be set or unset"))
+		}
+		if !reflect.DeepEqual(old.Spec.ExtraConfigs.NetworksConfig.VipNetworkList, r.Spec.ExtraConfigs.NetworksConfig.VipNetworkList) {
+			if err := r.validateAviVIPNetworks(avi); err != nil {
 				allErrs = append(allErrs, err...)
 			}
 		}
@@ -240,6 +290,114 @@ func (r *AKODeploymentConfig) validateReplicaCount() *field.Error {
 	return nil
 }
 
+// validateClusterIPPoolSize checks the size of the dedicated sub-pools of the
+// clusters is a power of two or is unset
+func (r *AKODeploymentConfig) validateClusterIPPoolSize() *field.Error {
+	size := r.Spec.DataNetwork.ClusterIPPoolSize
+	if size == 0 {
+		return nil
+	}
+	if size < 0 || size&(size-1) != 0 {
+		return field.Invalid(field.NewPath("spec", "dataNetwork", "clusterIPPoolSize"),
+			size,
+			"cluster ip pool size must be a power of two")
+	}
+	if len(r.Spec.DataNetwork.IPPools) == 0 {
+		return field.Required(field.NewPath("spec", "dataNetwork", "ipPools"),
+			"ip pools are required to carve the cluster ip pools from")
+	}
+	return nil
+}
+
+// validateAviUserPasswordRotationInterval checks the passwords are not rotated
+// more often than hourly, every rotation redeploys the credentials of AKO
+func (r *AKODeploymentConfig) validateAviUserPasswordRotationInterval() *field.Error {
+	interval := r.Spec.AviUserPasswordRotationInterval
+	if interval == nil {
+		return nil
+	}
+	if interval.Duration < time.Hour {
+		return field.Invalid(field.NewPath("spec", "aviUserPasswordRotationInterval"),
+			interval.Duration.String(),
+			"password rotation interval must be at least 1h")
+	}
+	return nil
+}
+
+// validateAviUserPasswordPolicy checks passwords can be generated with the
+// policy, whether they meet the complexity settings of the Avi Controller is
+// only known once they're generated
+func (r *AKODeploymentConfig) validateAviUserPasswordPolicy() *field.Error {
+	policy := r.Spec.AviUserPasswordPolicy
+	if policy == nil {
+		return nil
+	}
+	if err := policy.PasswordPolicy().Validate(); err != nil {
+		return field.Invalid(field.NewPath("spec", "aviUserPasswordPolicy"), policy, err.Error())
+	}
+	return nil
+}
+
+// PasswordPolicy returns the policy the passwords are generated with
+func (p *AviPasswordPolicy) PasswordPolicy() utils.PasswordPolicy {
+	if p == nil {
+		return utils.PasswordPolicy{}
+	}
+	policy := utils.PasswordPolicy{
+		Length:             int(p.Length),
+		ExcludedCharacters: p.ExcludedCharacters,
+	}
+	for _, class := range p.CharacterClasses {
+		policy.CharacterClasses = append(policy.CharacterClasses, string(class))
+	}
+	return policy
+}
+
+// validateAviRoleProfile checks the role profile neither takes a permission
+// AKO requires away nor grants more than AKO is granted by default
+func (r *AKODeploymentConfig) validateAviRoleProfile() *field.Error {
+	profile := r.Spec.RoleProfile
+	if profile == nil {
+		return nil
+	}
+	if err := profile.RoleProfile().Validate(); err != nil {
+		return field.Invalid(field.NewPath("spec", "roleProfile"), profile, err.Error())
+	}
+	return nil
+}
+
+// RoleProfile returns the profile the Avi role is made of
+func (p *AviRoleProfile) RoleProfile() utils.RoleProfile {
+	if p == nil {
+		return utils.RoleProfile{}
+	}
+	profile := utils.RoleProfile{
+		Name:               p.Name,
+		RemovedPermissions: p.RemovedPermissions,
+	}
+	for _, permission := range p.Permissions {
+		profile.Permissions = append(profile.Permissions, utils.RolePermission{
+			Resource: permission.Resource,
+			Type:     permission.Type,
+		})
+	}
+	return profile
+}
+
+// validateAviTenantNameTemplate checks the name template of the per-cluster
+// tenants renders a name
+func (r *AKODeploymentConfig) validateAviTenantNameTemplate() *field.Error {
+	perCluster := r.Spec.Tenant.PerCluster
+	if perCluster == nil {
+		return nil
+	}
+	if _, err := perCluster.TenantName(metav1.ObjectMeta{Name: "cluster", Namespace: "namespace"}); err != nil {
+		return field.Invalid(field.NewPath("spec", "tenant", "perCluster", "nameTemplate"),
+			perCluster.NameTemplate, err.Error())
+	}
+	return nil
+}
+
 // validateAviSecret checks NSX Advanced Load Balancer related credentials or certificate secret is valid or not
 func (r *AKODeploymentConfig) validateAviSecret(secret *corev1.Secret, secretRef SecretReference) *field.Error {
 	if err := kclient.Get(context.Background(), client.ObjectKey{
@@ -274,22 +432,55 @@ func (r *AKODeploymentConfig) validateAviControllerVersion() (string, *field.Err
 	return controllerVersion, nil
 }
 
-// validateAviAccount checks if using inputs can connect to avi controller or not
-func (r *AKODeploymentConfig) validateAviAccount(username, password, certificate, version string) (aviclient.Client, *field.Error) {
-	aviClient, err := aviclient.NewAviClient(&aviclient.AviClientConfig{
-		ServerIP: r.Spec.Controller,
-		Username: username,
-		Password: password,
-		CA:       certificate,
-	}, version)
+// validateAviAccount checks if using inputs can connect to avi controller or not.
+// The session cached for the controller is reused when the inputs match it.
+func (r *AKODeploymentConfig) validateAviAccount(config *aviclient.AviClientConfig, version string) (aviclient.Client, *field.Error) {
+	aviClient, err := aviclient.DefaultRegistry.Get(r.AviClientKey(), config, version)
 	if err != nil {
 		return nil, field.Invalid(field.NewPath("spec", "Controller"), r.Spec.Controller, "failed to init avi client for controller:"+err.Error())
 	}
 	return aviClient, nil
 }
 
+// AviClientKey returns the key of the Avi Controller session used for the
+// AKODeploymentConfig
+func (r *AKODeploymentConfig) AviClientKey() aviclient.ClientKey {
+	key := aviclient.ClientKey{
+		Controller: r.Spec.Controller,
+		Tenant:     r.Spec.Tenant.Name,
+	}
+	if r.Spec.AdminCredentialRef != nil {
+		key.AdminCredentialRef = r.Spec.AdminCredentialRef.Namespace + "/" + r.Spec.AdminCredentialRef.Name
+	}
+	if r.Spec.CertificateAuthorityRef != nil {
+		key.CARef = r.Spec.CertificateAuthorityRef.Namespace + "/" + r.Spec.CertificateAuthorityRef.Name
+	}
+	return key
+}
+
+// TenantName renders the name of the tenant of the Cluster from the name
+// template
+func (t *AVIClusterTenant) TenantName(cluster metav1.ObjectMeta) (string, error) {
+	text := t.NameTemplate
+	if text == "" {
+		text = DefaultAviTenantNameTemplate
+	}
+	tmpl, err := template.New("tenant").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var name strings.Builder
+	if err := tmpl.Execute(&name, cluster); err != nil {
+		return "", err
+	}
+	if name.Len() == 0 {
+		return "", fmt.Errorf("the tenant name template renders an empty name")
+	}
+	return name.String(), nil
+}
+
 // validateAviCloud checks input Cloud Name field valid or not
-func (r *AKODeploymentConfig) validateAviCloud() *field.Error {
+func (r *AKODeploymentConfig) validateAviCloud(aviClient aviclient.Client) *field.Error {
 	if cloud, err := aviClient.CloudGetByName(r.Spec.CloudName); err != nil {
 		return field.Invalid(field.NewPath("spec", "cloudName"), r.Spec.CloudName,
 			"failed to get cloud from avi controller:"+err.Error())
@@ -300,8 +491,20 @@ func (r *AKODeploymentConfig) validateAviCloud() *field.Error {
 	return nil
 }
 
-// validateAviServiceEngineGroup checks input Servcie Engine Group valid or not
-func (r *AKODeploymentConfig) validateAviServiceEngineGroup() *field.Error {
+// validateAviServiceEngineGroup checks input Servcie Engine Group valid or not,
+// a Service Engine Group provisioned from a template only needs the Service
+// Engine Group it's cloned from to exist
+func (r *AKODeploymentConfig) validateAviServiceEngineGroup(aviClient aviclient.Client) *field.Error {
+	if template := r.Spec.ServiceEngineGroupTemplate; template != nil {
+		if template.CloneFrom == "" {
+			return nil
+		}
+		if _, err := aviClient.ServiceEngineGroupGetByName(template.CloneFrom, r.Spec.CloudName); err != nil {
+			return field.Invalid(field.NewPath("spec", "serviceEngineGroupTemplate", "cloneFrom"), template.CloneFrom,
+				"failed to get service engine group from avi controller:"+err.Error())
+		}
+		return nil
+	}
 	if _, err := aviClient.ServiceEngineGroupGetByName(r.Spec.ServiceEngineGroup, r.Spec.CloudName); err != nil {
 		return field.Invalid(field.NewPath("spec", "serviceEngineGroup"), r.Spec.ServiceEngineGroup,
 			"failed to get service engine group from avi controller:"+err.Error())
@@ -310,7 +513,7 @@ func (r *AKODeploymentConfig) validateAviServiceEngineGroup() *field.Error {
 }
 
 // validateAviControlPlaneNetworks checks input Control Plane Network name existing or not, CIDR format valid or not
-func (r *AKODeploymentConfig) validateAviControlPlaneNetworks() field.ErrorList {
+func (r *AKODeploymentConfig) validateAviControlPlaneNetworks(aviClient aviclient.Client) field.ErrorList {
 	var allErrs field.ErrorList
 	if r.Spec.ControlPlaneNetwork.Name == "" || r.Spec.ControlPlaneNetwork.CIDR == "" {
 		return allErrs
@@ -333,9 +536,9 @@ func (r *AKODeploymentConfig) validateAviControlPlaneNetworks() field.ErrorList
 
 // validateAviDataNetworks checks input
 // Data Plane Network name existing or not
-// CIDR format valid or not
-// IPPools format valid or not
-func (r *AKODeploymentConfig) validateAviDataNetworks() field.ErrorList {
+// CIDR and V6CIDR format valid or not
+// IPPools format valid or not, and in the CIDR of their type
+func (r *AKODeploymentConfig) validateAviDataNetworks(aviClient aviclient.Client) field.ErrorList {
 	var allErrs field.ErrorList
 	// check data network name
 	if _, err := aviClient.NetworkGetByName(r.Spec.DataNetwork.Name, r.Spec.CloudName); err != nil {
@@ -343,22 +546,41 @@ func (r *AKODeploymentConfig) validateAviDataNetworks() field.ErrorList {
 			r.Spec.DataNetwork.Name,
 			"failed to get data plane network "+r.Spec.DataNetwork.Name+" from avi controller:"+err.Error()))
 	}
-	// check network cidr
+	// check network cidrs, indexed by address type
+	cidrs := map[string]*net.IPNet{}
 	addr, cidr, err := net.ParseCIDR(r.Spec.DataNetwork.CIDR)
 	if err != nil {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "dataNetwork", "cidr"),
 			r.Spec.DataNetwork.CIDR,
 			"data plane network cidr "+r.Spec.DataNetwork.CIDR+" is not valid:"+err.Error()))
+	} else if addr.To4() != nil {
+		cidrs["V4"] = cidr
+	} else {
+		cidrs["V6"] = cidr
 	}
-	addrType := "INVALID"
-	if addr.To4() != nil {
-		addrType = "V4"
-	} else if addr.To16() != nil {
-		addrType = "V6"
+	if r.Spec.DataNetwork.V6CIDR != "" {
+		addr, cidr, err := net.ParseCIDR(r.Spec.DataNetwork.V6CIDR)
+		if err != nil || addr.To4() != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "dataNetwork", "v6cidr"),
+				r.Spec.DataNetwork.V6CIDR,
+				"data plane network v6cidr "+r.Spec.DataNetwork.V6CIDR+" is not a valid V6 cidr"))
+		} else if _, ok := cidrs["V6"]; ok {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "dataNetwork", "v6cidr"),
+				r.Spec.DataNetwork.V6CIDR,
+				"data plane network cidr must be a V4 cidr when v6cidr is set"))
+		} else {
+			cidrs["V6"] = cidr
+		}
 	}
 
 	// check data network ip pools
 	for _, ipPool := range r.Spec.DataNetwork.IPPools {
+		cidr, ok := cidrs[ipPool.Type]
+		if !ok {
+			return append(allErrs, field.Invalid(field.NewPath("spec", "dataNetwork", "ipPools"),
+				r.Spec.DataNetwork.IPPools,
+				"data plane network ip pools type is not aligned with cidr"))
+		}
 		ipStart := net.ParseIP(ipPool.Start)
 		ipEnd := net.ParseIP(ipPool.End)
 		if ipStart == nil {
@@ -381,10 +603,77 @@ func (r *AKODeploymentConfig) validateAviDataNetworks() field.ErrorList {
 				r.Spec.DataNetwork.IPPools,
 				ipPool.Start+" is greater than "+ipPool.End))
 		}
-		if ipPool.Type != addrType {
-			return append(allErrs, field.Invalid(field.NewPath("spec", "dataNetwork", "ipPools"),
-				r.Spec.DataNetwork.IPPools,
-				"data plane network ip pools type is not aligned with cidr"))
+	}
+	return allErrs
+}
+
+// validateAviVIPNetworks checks input
+// VIP Network names existing or not and unique or not
+// CIDR and V6CIDR format valid or not
+// IPPools format valid or not, and in the CIDR of their type
+func (r *AKODeploymentConfig) validateAviVIPNetworks(aviClient aviclient.Client) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for i, vipNetwork := range r.Spec.ExtraConfigs.NetworksConfig.VipNetworkList {
+		path := field.NewPath("spec", "extraConfigs", "networksConfig", "vipNetworkList").Index(i)
+		// check vip network name
+		if names[vipNetwork.NetworkName] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("networkName"), vipNetwork.NetworkName))
+		}
+		// the data network is configured in spec.dataNetwork only
+		if vipNetwork.NetworkName == r.Spec.DataNetwork.Name {
+			allErrs = append(allErrs, field.Invalid(path.Child("networkName"), vipNetwork.NetworkName,
+				"vip network should not be the data network "+r.Spec.DataNetwork.Name))
+		}
+		names[vipNetwork.NetworkName] = true
+		if _, err := aviClient.NetworkGetByName(vipNetwork.NetworkName, r.Spec.CloudName); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("networkName"),
+				vipNetwork.NetworkName,
+				"failed to get vip network "+vipNetwork.NetworkName+" from avi controller:"+err.Error()))
+		}
+
+		// check network cidrs, indexed by address type
+		if vipNetwork.CIDR == "" && vipNetwork.V6CIDR == "" {
+			allErrs = append(allErrs, field.Required(path.Child("cidr"), "cidr or v6cidr must be set"))
+			continue
+		}
+		cidrs := map[string]*net.IPNet{}
+		for _, c := range []struct{ name, cidr, addrType string }{
+			{"cidr", vipNetwork.CIDR, "V4"},
+			{"v6cidr", vipNetwork.V6CIDR, "V6"},
+		} {
+			if c.cidr == "" {
+				continue
+			}
+			addr, cidr, err := net.ParseCIDR(c.cidr)
+			if err != nil || (addr.To4() != nil) != (c.addrType == "V4") {
+				allErrs = append(allErrs, field.Invalid(path.Child(c.name), c.cidr,
+					"vip network "+c.name+" "+c.cidr+" is not a valid "+c.addrType+" cidr"))
+				continue
+			}
+			cidrs[c.addrType] = cidr
+		}
+
+		// check vip network ip pools
+		for j, ipPool := range vipNetwork.IPPools {
+			poolPath := path.Child("ipPools").Index(j)
+			cidr, ok := cidrs[ipPool.Type]
+			if !ok {
+				allErrs = append(allErrs, field.Invalid(poolPath.Child("type"), ipPool.Type,
+					"vip network has no valid cidr of the ip pool type"))
+				continue
+			}
+			ipStart := net.ParseIP(ipPool.Start)
+			ipEnd := net.ParseIP(ipPool.End)
+			if ipStart == nil || ipEnd == nil || !cidr.Contains(ipStart) || !cidr.Contains(ipEnd) {
+				allErrs = append(allErrs, field.Invalid(poolPath, ipPool,
+					"Range ["+ipPool.Start+","+ipPool.End+"] is not in cidr "+cidr.String()))
+				continue
+			}
+			if bytes.Compare(ipStart, ipEnd) > 0 {
+				allErrs = append(allErrs, field.Invalid(poolPath, ipPool,
+					ipPool.Start+" is greater than "+ipPool.End))
+			}
 		}
 	}
 	return allErrs
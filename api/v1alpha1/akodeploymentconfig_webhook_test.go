@@ -6,9 +6,11 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/test/aviserver"
 	"github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,13 +18,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+// aviServer is the Avi Controller the webhook validates against
+var aviServer *aviserver.Server
+
 type ModifyTestCaseInputFunc func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig)
 
 func beforeAll(t *testing.T) (staticAdminSecret, staticCASecret *corev1.Secret, staticADC AKODeploymentConfig, g *WithT) {
 	runTest = true
 	kclient = fake.NewClientBuilder().Build()
-	aviClient = aviclient.NewFakeAviClient()
+	if aviServer == nil {
+		aviServer = aviserver.NewServer()
+	}
 	configureAVIController()
+	var err error
+	aviClient, err = aviclient.NewAviClient(aviServer.ClientConfig(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	staticAdminSecret = &corev1.Secret{
 		ObjectMeta: v1.ObjectMeta{
@@ -101,20 +113,45 @@ func afterEach(adminSecret, certificateSecret *corev1.Secret, g *WithT) {
 	configureAVIController()
 }
 
+// configureAVIController resets the Avi Controller to the cloud, service
+// engine group and networks used by the test AKODeploymentConfigs
 func configureAVIController() {
-	aviClient.ServiceEngineGroupCreate(&models.ServiceEngineGroup{
-		Name: ptr.To("fake-seg"),
-	})
-	aviClient.CloudCreate(&models.Cloud{
-		Name:            ptr.To("fake-cloud"),
-		IPAMProviderRef: ptr.To("https://10.0.0.x/api/ipamdnsproviderprofile/test"),
-	})
-	aviClient.NetworkCreate(&models.Network{
-		Name: ptr.To("fake-control-plane"),
+	aviServer.Reset()
+	createAVICloud("fake-cloud", "fake-seg",
+		"fake-control-plane", "fake-data-plane", "fake-public-vip", "fake-internal-vip")
+}
+
+// createAVICloud creates a cloud with an IPAM profile, and a service engine
+// group and networks in it
+func createAVICloud(cloud, seg string, networks ...string) {
+	ipamRef, err := aviServer.Create("ipamdnsproviderprofile", &models.IPAMDNSProviderProfile{
+		Name: ptr.To(cloud + "-ipam"),
+		Type: ptr.To("IPAMDNS_TYPE_INTERNAL"),
 	})
-	aviClient.NetworkCreate(&models.Network{
-		Name: ptr.To("fake-data-plane"),
+	if err != nil {
+		panic(err)
+	}
+	cloudRef, err := aviServer.Create("cloud", &models.Cloud{
+		Name:            ptr.To(cloud),
+		IPAMProviderRef: ptr.To(ipamRef),
 	})
+	if err != nil {
+		panic(err)
+	}
+	if _, err := aviServer.Create("serviceenginegroup", &models.ServiceEngineGroup{
+		Name:     ptr.To(seg),
+		CloudRef: ptr.To(cloudRef),
+	}); err != nil {
+		panic(err)
+	}
+	for _, network := range networks {
+		if _, err := aviServer.Create("network", &models.Network{
+			Name:     ptr.To(network),
+			CloudRef: ptr.To(cloudRef),
+		}); err != nil {
+			panic(err)
+		}
+	}
 }
 
 func TestCreateNewAKODeploymentConfig(t *testing.T) {
@@ -209,7 +246,7 @@ func TestCreateNewAKODeploymentConfig(t *testing.T) {
 			certificateSecret: staticCASecret.DeepCopy(),
 			adc:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
-				aviClient.CloudCreate(nil)
+				aviServer.Delete("cloud", "fake-cloud")
 				return adminSecret, certificateSecret, adc
 			},
 			expectErr: true,
@@ -220,7 +257,31 @@ func TestCreateNewAKODeploymentConfig(t *testing.T) {
 			certificateSecret: staticCASecret.DeepCopy(),
 			adc:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
-				aviClient.ServiceEngineGroupCreate(nil)
+				aviServer.Delete("serviceenginegroup", "fake-seg")
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "missing service engine group provisioned from a template should pass webhook validation",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				aviServer.Delete("serviceenginegroup", "fake-seg")
+				adc.Spec.ServiceEngineGroupTemplate = &ServiceEngineGroupTemplate{MaxSEs: ptr.To(int32(4))}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if not find the service engine group to clone",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				aviServer.Delete("serviceenginegroup", "fake-seg")
+				adc.Spec.ServiceEngineGroupTemplate = &ServiceEngineGroupTemplate{CloneFrom: "Default-Group"}
 				return adminSecret, certificateSecret, adc
 			},
 			expectErr: true,
@@ -231,7 +292,7 @@ func TestCreateNewAKODeploymentConfig(t *testing.T) {
 			certificateSecret: staticCASecret.DeepCopy(),
 			adc:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
-				aviClient.NetworkCreate(nil)
+				aviServer.Delete("network", "fake-control-plane")
 				return adminSecret, certificateSecret, adc
 			},
 			expectErr: true,
@@ -256,7 +317,7 @@ func TestCreateNewAKODeploymentConfig(t *testing.T) {
 			certificateSecret: staticCASecret.DeepCopy(),
 			adc:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
-				aviClient.NetworkCreate(nil)
+				aviServer.Delete("network", "fake-data-plane")
 				return adminSecret, certificateSecret, adc
 			},
 			expectErr: true,
@@ -345,7 +406,7 @@ func TestCreateNewAKODeploymentConfig(t *testing.T) {
 			adc:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
 				adc.Spec.DataNetwork = DataNetwork{
-					Name: "VM Network 1",
+					Name: "fake-data-plane",
 					CIDR: "2002::1234:abcd:ffff:c0a8:101/64",
 					IPPools: []IPPool{
 						{
@@ -414,6 +475,358 @@ func TestCreateNewAKODeploymentConfig(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name:              "cluster ip pool size is a power of two",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.ClusterIPPoolSize = 4
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if cluster ip pool size is not a power of two",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.ClusterIPPoolSize = 6
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if cluster ip pools are enabled without ip pools",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.ClusterIPPoolSize = 4
+				adc.Spec.DataNetwork.IPPools = nil
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "password rotation interval of a day",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.AviUserPasswordRotationInterval = &v1.Duration{Duration: 24 * time.Hour}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if password rotation interval is less than an hour",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.AviUserPasswordRotationInterval = &v1.Duration{Duration: time.Minute}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "password policy should pass webhook validation",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.AviUserPasswordPolicy = &AviPasswordPolicy{Length: 24, ExcludedCharacters: "0Oo1lI"}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if every character of a class of the password policy is excluded",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.AviUserPasswordPolicy = &AviPasswordPolicy{
+					CharacterClasses:   []PasswordCharacterClass{"Digit"},
+					ExcludedCharacters: "0123456789",
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "role profile should pass webhook validation",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.RoleProfile = &AviRoleProfile{
+					Name:               "l4",
+					Permissions:        []AviPermission{{Resource: "PERMISSION_HTTPPOLICYSET", Type: "READ_ACCESS"}},
+					RemovedPermissions: []string{"PERMISSION_PKIPROFILE"},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if the role profile removes a permission AKO requires",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.RoleProfile = &AviRoleProfile{
+					Name:               "l4",
+					RemovedPermissions: []string{"PERMISSION_VIRTUALSERVICE"},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if the role profile downgrades a permission AKO requires",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.RoleProfile = &AviRoleProfile{
+					Name:        "l4",
+					Permissions: []AviPermission{{Resource: "PERMISSION_VIRTUALSERVICE", Type: "NO_ACCESS"}},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if the role profile has an unknown permission",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.RoleProfile = &AviRoleProfile{
+					Name:               "l4",
+					RemovedPermissions: []string{"PERMISSION_UNKNOWN"},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "per-cluster tenant name template should pass webhook validation",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.Tenant.PerCluster = &AVIClusterTenant{NameTemplate: "tkg-{{.Namespace}}-{{.Name}}"}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if per-cluster tenant name template is invalid",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.Tenant.PerCluster = &AVIClusterTenant{NameTemplate: "{{.Cluster}}"}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "dual-stack data network should pass webhook validation",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.V6CIDR = "2002::/64"
+				adc.Spec.DataNetwork.IPPools = append(adc.Spec.DataNetwork.IPPools, IPPool{
+					Start: "2002::10",
+					End:   "2002::20",
+					Type:  "V6",
+				})
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if data network has no cidr of the ip pool type",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.IPPools = append(adc.Spec.DataNetwork.IPPools, IPPool{
+					Start: "2002::10",
+					End:   "2002::20",
+					Type:  "V6",
+				})
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if data network v6cidr is not ipv6",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.V6CIDR = "10.1.0.0/24"
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if data network v6cidr is set with an ipv6 cidr",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.CIDR = "2001::/64"
+				adc.Spec.DataNetwork.IPPools = nil
+				adc.Spec.DataNetwork.V6CIDR = "2002::/64"
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if data network v6 ip pool is not in v6cidr",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.DataNetwork.V6CIDR = "2002::/64"
+				adc.Spec.DataNetwork.IPPools = append(adc.Spec.DataNetwork.IPPools, IPPool{
+					Start: "2003::10",
+					End:   "2003::20",
+					Type:  "V6",
+				})
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "valid vip networks should pass webhook validation",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{
+					{
+						NetworkName: "fake-public-vip",
+						CIDR:        "10.1.0.0/24",
+						IPPools:     []IPPool{{Start: "10.1.0.10", End: "10.1.0.20", Type: "V4"}},
+					},
+					{
+						NetworkName: "fake-internal-vip",
+						CIDR:        "10.2.0.0/24",
+						V6CIDR:      "2002::/64",
+					},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
+		{
+			name:              "should throw error if vip network ip pool is not in cidr",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{{
+					NetworkName: "fake-public-vip",
+					CIDR:        "10.1.0.0/24",
+					IPPools:     []IPPool{{Start: "10.2.0.10", End: "10.2.0.20", Type: "V4"}},
+				}}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if vip network has no cidr of the ip pool type",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{{
+					NetworkName: "fake-public-vip",
+					V6CIDR:      "2002::/64",
+					IPPools:     []IPPool{{Start: "10.1.0.10", End: "10.1.0.20", Type: "V4"}},
+				}}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if vip network cidr is not ipv4",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{{
+					NetworkName: "fake-public-vip",
+					CIDR:        "2002::/64",
+				}}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if vip network has no cidr",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{{
+					NetworkName: "fake-public-vip",
+				}}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if vip network names are duplicated",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{
+					{NetworkName: "fake-public-vip", CIDR: "10.1.0.0/24"},
+					{NetworkName: "fake-public-vip", CIDR: "10.2.0.0/24"},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if vip network is the data network",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{
+					{NetworkName: adc.Spec.DataNetwork.Name, CIDR: adc.Spec.DataNetwork.CIDR},
+				}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "should throw error if vip network doesn't exist",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			adc:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				aviServer.Delete("network", "fake-public-vip")
+				adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []VIPNetwork{{
+					NetworkName: "fake-public-vip",
+					CIDR:        "10.1.0.0/24",
+				}}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -458,16 +871,7 @@ func TestUpdateExistingAKODeploymentConfig(t *testing.T) {
 			old:               staticADC.DeepCopy(),
 			new:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
-				aviClient.CloudCreate(&models.Cloud{
-					Name:            ptr.To("fake-new-cloud"),
-					IPAMProviderRef: ptr.To("https://10.0.0.x/api/ipamdnsproviderprofile/test"),
-				})
-				aviClient.ServiceEngineGroupCreate(&models.ServiceEngineGroup{
-					Name: ptr.To("fake-seg"),
-				})
-				aviClient.NetworkCreate(&models.Network{
-					Name: ptr.To("fake-new-data-plane"),
-				})
+				createAVICloud("fake-new-cloud", "fake-new-seg", "fake-control-plane", "fake-new-data-plane")
 				adc.Spec.CloudName = "fake-new-cloud"
 				adc.Spec.ServiceEngineGroup = "fake-new-seg"
 				adc.Spec.DataNetwork = DataNetwork{
@@ -532,6 +936,61 @@ func TestUpdateExistingAKODeploymentConfig(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name:              "akodeployment should not update the delivery",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			old:               staticADC.DeepCopy(),
+			new:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.Delivery = AKODeliveryDirect
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "akodeployment should not turn on per-cluster tenants",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			old:               staticADC.DeepCopy(),
+			new:               staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.Tenant.PerCluster = &AVIClusterTenant{}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "akodeployment should not turn off per-cluster tenants",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			old: func() *AKODeploymentConfig {
+				adc := staticADC.DeepCopy()
+				adc.Spec.Tenant.PerCluster = &AVIClusterTenant{}
+				return adc
+			}(),
+			new: staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: true,
+		},
+		{
+			name:              "akodeployment can update the per-cluster tenant name template",
+			adminSecret:       staticAdminSecret.DeepCopy(),
+			certificateSecret: staticCASecret.DeepCopy(),
+			old: func() *AKODeploymentConfig {
+				adc := staticADC.DeepCopy()
+				adc.Spec.Tenant.PerCluster = &AVIClusterTenant{}
+				return adc
+			}(),
+			new: staticADC.DeepCopy(),
+			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
+				adc.Spec.Tenant.PerCluster = &AVIClusterTenant{NameTemplate: "tkg-{{.Name}}"}
+				return adminSecret, certificateSecret, adc
+			},
+			expectErr: false,
+		},
 		{
 			name:              "akodeployment should not update to invalid cloud, seg and data plane",
 			adminSecret:       staticAdminSecret.DeepCopy(),
@@ -539,10 +998,6 @@ func TestUpdateExistingAKODeploymentConfig(t *testing.T) {
 			old:               staticADC.DeepCopy(),
 			new:               staticADC.DeepCopy(),
 			customizeInput: func(adminSecret, certificateSecret *corev1.Secret, adc *AKODeploymentConfig) (*corev1.Secret, *corev1.Secret, *AKODeploymentConfig) {
-				aviClient.CloudCreate(nil)
-				aviClient.NetworkCreate(nil)
-				aviClient.ServiceEngineGroupCreate(nil)
-
 				adc.Spec.CloudName = "fake-new-cloud"
 				adc.Spec.ServiceEngineGroup = "fake-new-seg"
 				adc.Spec.DataNetwork = DataNetwork{
@@ -642,3 +1097,19 @@ func TestDeleteAKODeploymentConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestAVIClusterTenantName(t *testing.T) {
+	g := NewWithT(t)
+	cluster := v1.ObjectMeta{Name: "workload", Namespace: "dev"}
+
+	name, err := (&AVIClusterTenant{}).TenantName(cluster)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(name).To(Equal("dev-workload"))
+
+	name, err = (&AVIClusterTenant{NameTemplate: "tkg-{{.Name}}"}).TenantName(cluster)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(name).To(Equal("tkg-workload"))
+
+	_, err = (&AVIClusterTenant{NameTemplate: "{{if false}}x{{end}}"}).TenantName(cluster)
+	g.Expect(err).Should(HaveOccurred())
+}
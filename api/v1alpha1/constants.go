@@ -27,6 +27,7 @@ const (
 	WorkloadClusterAkoDeploymentConfig   = "install-ako-for-all"
 
 	AkoUserRoleName                  = "ako-essential-role"
+	AkoUserFullName                  = "AKO user managed by ako-operator"
 	ClusterFinalizer                 = "ako-operator.networking.tkg.tanzu.vmware.com"
 	AkoDeploymentConfigFinalizer     = "ako-operator.networking.tkg.tanzu.vmware.com"
 	AkoDeploymentConfigKind          = "AKODeploymentConfig"
@@ -39,14 +40,28 @@ const (
 	AviClusterLabel                                                     = "networking.tkg.tanzu.vmware.com/avi"
 	AviClusterDeleteConfigLabel                                         = "networking.tkg.tanzu.vmware.com/avi-config-delete"
 	AviClusterSecretType                                                = "avi.cluster.x-k8s.io/secret"
+	AviUserSecretClusterLabel                                           = "networking.tkg.tanzu.vmware.com/avi-user-cluster"
+	AviUserPasswordHashAnnotation                                       = "networking.tkg.tanzu.vmware.com/avi-user-password-hash"
+	AviClusterIPPoolAnnotation                                          = "networking.tkg.tanzu.vmware.com/vip-pool"
+	AviClusterServiceEngineGroupAnnotation                              = "networking.tkg.tanzu.vmware.com/service-engine-group"
+	AviClusterServiceEngineGroupInUseAnnotation                         = "networking.tkg.tanzu.vmware.com/service-engine-group-in-use"
+	AviClusterTenantAnnotation                                          = "networking.tkg.tanzu.vmware.com/avi-tenant"
+	DefaultAviTenantNameTemplate                                        = "{{.Namespace}}-{{.Name}}"
+	AviClusterRotatePasswordAnnotation                                  = "networking.tkg.tanzu.vmware.com/rotate-avi-user-password"
+	AKOValuesHashAnnotation                                             = "networking.tkg.tanzu.vmware.com/ako-values-hash"
+	AKOClusterResourceSetLabel                                          = "networking.tkg.tanzu.vmware.com/ako-cluster-resource-set"
 	AviNamespace                                                        = "avi-system"
 	AviCredentialName                                                   = "avi-controller-credentials"
 	AviCAName                                                           = "avi-controller-ca"
 	AviCertificateKey                                                   = "certificateAuthorityData"
+	AviPendingPasswordKey                                               = "pendingPassword"
+	AviPendingUsernameKey                                               = "pendingUsername"
+	AviPreviousUsernameKey                                              = "previousUsername"
 	AviResourceCleanupReason                                            = "AviResourceCleanup"
 	AviResourceCleanupSucceededCondition        clusterv1.ConditionType = "AviResourceCleanupSucceeded"
 	AviUserCleanupSucceededCondition            clusterv1.ConditionType = "AviUserCleanupSucceeded"
 	ClusterIpFamilyValidationSucceededCondition clusterv1.ConditionType = "ClusterIpFamilyValidationSucceeded"
+	AKOFeaturesSupportedCondition               clusterv1.ConditionType = "AKOFeaturesSupported"
 	PreTerminateAnnotation                                              = clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/avi-cleanup"
 
 	HAServiceName                      = "control-plane"
@@ -55,6 +70,153 @@ const (
 	HAAVIInfraSettingAnnotationsKey    = "aviinfrasetting.ako.vmware.com/name"
 
 	AKODeploymentConfigControllerName = "akodeploymentconfig-controller"
+	ClusterControllerName             = "cluster-controller"
+	MachineControllerName             = "machine-controller"
 
 	AVIControllerEnterpriseOnlyVersion = "v30.0.0"
+
+	DefaultVIPPoolLowThreshold = 10
 )
+
+// Reasons of the Events emitted by the operator on AKODeploymentConfigs,
+// Clusters and Machines
+const (
+	// AKODeploymentConfig events, the ServiceEngineGroup ones are emitted
+	// on Clusters too for their dedicated Service Engine Group
+	AviClientInitFailedReason       = "AviClientInitFailed"
+	NetworkSubnetUpdatedReason      = "NetworkSubnetUpdated"
+	NetworkSubnetUpdateFailedReason = "NetworkSubnetUpdateFailed"
+	UsableNetworkAddFailedReason    = "UsableNetworkAddFailed"
+	StaticIPRangeInUseReason        = "StaticIPRangeInUse"
+	UsableNetworkRemoveFailedReason = "UsableNetworkRemoveFailed"
+	AviInfraSettingCreatedReason    = "AviInfraSettingCreated"
+	AviInfraSettingDeletedReason    = "AviInfraSettingDeleted"
+	AviInfraSettingFailedReason     = "AviInfraSettingFailed"
+	VIPPoolLowReason                = "VIPPoolLow"
+	ServiceEngineGroupCreatedReason = "ServiceEngineGroupCreated"
+	ServiceEngineGroupUpdatedReason = "ServiceEngineGroupUpdated"
+	ServiceEngineGroupDeletedReason = "ServiceEngineGroupDeleted"
+	ServiceEngineGroupInUseReason   = "ServiceEngineGroupInUse"
+	ServiceEngineGroupFailedReason  = "ServiceEngineGroupFailed"
+	AviTenantCreatedReason          = "AviTenantCreated"
+	AviTenantDeletedReason          = "AviTenantDeleted"
+	AviTenantFailedReason           = "AviTenantFailed"
+	OrphanFoundReason               = "OrphanFound"
+	OrphanDeletedReason             = "OrphanDeleted"
+	OrphanDeleteFailedReason        = "OrphanDeleteFailed"
+
+	// Cluster events
+	AKOAddonSecretCreatedReason       = "AKOAddonSecretCreated"
+	AKOAddonSecretUpdatedReason       = "AKOAddonSecretUpdated"
+	AKOAddonSecretFailedReason        = "AKOAddonSecretFailed"
+	AKODeliveredReason                = "AKODelivered"
+	AKODeliveryFailedReason           = "AKODeliveryFailed"
+	AKODriftCorrectedReason           = "AKODriftCorrected"
+	AKORemovedReason                  = "AKORemoved"
+	AKOFeatureUnsupportedReason       = "AKOFeatureUnsupported"
+	AKOVersionUnknownReason           = "AKOVersionUnknown"
+	ClusterIpFamilyInvalidReason      = "ClusterIpFamilyInvalid"
+	AviResourceCleanupStartedReason   = "AviResourceCleanupStarted"
+	AviResourceCleanupSucceededReason = "AviResourceCleanupSucceeded"
+	AviResourceCleanupFailedReason    = "AviResourceCleanupFailed"
+	AviUserCreatedReason              = "AviUserCreated"
+	AviUserPasswordUpdatedReason      = "AviUserPasswordUpdated"
+	AviUserPasswordRotatedReason      = "AviUserPasswordRotated"
+	AviUserSyncFailedReason           = "AviUserSyncFailed"
+	AviUserDeletedReason              = "AviUserDeleted"
+	AviUserDeleteFailedReason         = "AviUserDeleteFailed"
+	AviRoleCreatedReason              = "AviRoleCreated"
+	AviRoleSyncedReason               = "AviRoleSynced"
+	AviRoleDeletedReason              = "AviRoleDeleted"
+	AviRoleDeleteFailedReason         = "AviRoleDeleteFailed"
+	AviUserRoleBoundReason            = "AviUserRoleBound"
+	HAServiceCreatedReason            = "HAServiceCreated"
+	HAServiceVIPNotReadyReason        = "HAServiceVIPNotReady"
+	HAServiceFailedReason             = "HAServiceFailed"
+	ControlPlaneEndpointUpdatedReason = "ControlPlaneEndpointUpdated"
+	ClusterIPPoolAllocatedReason      = "ClusterIPPoolAllocated"
+	ClusterIPPoolReleasedReason       = "ClusterIPPoolReleased"
+	ClusterIPPoolExhaustedReason      = "ClusterIPPoolExhausted"
+
+	// Machine events
+	HAEndpointAddedReason         = "HAEndpointAdded"
+	HAEndpointRemovedReason       = "HAEndpointRemoved"
+	HAEndpointUpdateFailedReason  = "HAEndpointUpdateFailed"
+	PreTerminateHookRemovedReason = "PreTerminateHookRemoved"
+)
+
+// Conditions of AKODeploymentConfigs and their reasons
+const (
+	// AviControllerReachableCondition is true when a session could be
+	// opened with the Avi Controller
+	AviControllerReachableCondition clusterv1.ConditionType = "AviControllerReachable"
+	AviControllerUnreachableReason                          = "AviControllerUnreachable"
+
+	// AviCredentialsValidCondition is true when the admin credentials and
+	// the CA secrets exist and the Avi Controller accepts the credentials
+	AviCredentialsValidCondition    clusterv1.ConditionType = "AviCredentialsValid"
+	AviCredentialsNotFoundReason                            = "AviCredentialsNotFound"
+	AviAuthenticationFailedReason                           = "AviAuthenticationFailed"
+	AviCredentialsNotVerifiedReason                         = "AviCredentialsNotVerified"
+
+	// CloudReadyCondition is true when the cloud exists and has an IPAM
+	// provider
+	CloudReadyCondition          clusterv1.ConditionType = "CloudReady"
+	CloudNotFoundReason                                  = "CloudNotFound"
+	CloudIPAMNotConfiguredReason                         = "CloudIPAMNotConfigured"
+
+	// DataNetworkSyncedCondition is true when the data network subnet and
+	// static ranges are configured on the Avi Controller and the network is
+	// usable by the cloud
+	DataNetworkSyncedCondition clusterv1.ConditionType = "DataNetworkSynced"
+	// ControlPlaneNetworkSyncedCondition is true when the control plane
+	// network is usable by the cloud. It's absent when no control plane
+	// network is configured.
+	ControlPlaneNetworkSyncedCondition    clusterv1.ConditionType = "ControlPlaneNetworkSynced"
+	NetworkNotFoundReason                                         = "NetworkNotFound"
+	NetworkCIDRInvalidReason                                      = "NetworkCIDRInvalid"
+	NetworkUpdateFailedReason                                     = "NetworkUpdateFailed"
+	UsableNetworkAddFailedConditionReason                         = "UsableNetworkAddFailed"
+	NetworkRuntimeUnavailableReason                               = "NetworkRuntimeUnavailable"
+	StaticIPRangeInUseConditionReason                             = "StaticIPRangeInUse"
+
+	// AviInfraSettingReadyCondition is true when the AviInfraSetting of the
+	// control plane network is up to date. It's absent when no control
+	// plane network is configured.
+	AviInfraSettingReadyCondition   clusterv1.ConditionType = "AviInfraSettingReady"
+	AviInfraSettingSyncFailedReason                         = "AviInfraSettingSyncFailed"
+
+	// ControllerVersionDetectedCondition is true when the version of the
+	// Avi Controller is known
+	ControllerVersionDetectedCondition clusterv1.ConditionType = "ControllerVersionDetected"
+	ControllerVersionUnknownReason                             = "ControllerVersionUnknown"
+
+	// ServiceEngineGroupReadyCondition is true when the Service Engine Group
+	// exists and matches the ServiceEngineGroupTemplate. It's absent when no
+	// template is configured.
+	ServiceEngineGroupReadyCondition            clusterv1.ConditionType = "ServiceEngineGroupReady"
+	ServiceEngineGroupCloneSourceNotFoundReason                         = "ServiceEngineGroupCloneSourceNotFound"
+	ServiceEngineGroupSyncFailedReason                                  = "ServiceEngineGroupSyncFailed"
+
+	// VIPPoolLowCondition is true when the free IPs of a static IP pool of
+	// the data or VIP networks are under the VIPPoolLowThreshold of the
+	// AKODeploymentConfig. It isn't summarized by the Ready condition, a
+	// pool running low is not a failure.
+	VIPPoolLowCondition             clusterv1.ConditionType = "VIPPoolLow"
+	VIPPoolLowConditionReason                               = "VIPPoolLow"
+	VIPPoolSufficientReason                                 = "VIPPoolSufficient"
+	VIPPoolUtilizationUnknownReason                         = "VIPPoolUtilizationUnknown"
+)
+
+// AKODeploymentConfigConditions are summarized by the Ready condition of
+// AKODeploymentConfigs
+var AKODeploymentConfigConditions = []clusterv1.ConditionType{
+	AviControllerReachableCondition,
+	AviCredentialsValidCondition,
+	CloudReadyCondition,
+	DataNetworkSyncedCondition,
+	ControlPlaneNetworkSyncedCondition,
+	AviInfraSettingReadyCondition,
+	ControllerVersionDetectedCondition,
+	ServiceEngineGroupReadyCondition,
+}
@@ -8,6 +8,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -74,12 +75,27 @@ func (in *AKODeploymentConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AKODeploymentConfigSpec) DeepCopyInto(out *AKODeploymentConfigSpec) {
 	*out = *in
+	if in.ServiceEngineGroupTemplate != nil {
+		in, out := &in.ServiceEngineGroupTemplate, &out.ServiceEngineGroupTemplate
+		*out = new(ServiceEngineGroupTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
 	if in.WorkloadCredentialRef != nil {
 		in, out := &in.WorkloadCredentialRef, &out.WorkloadCredentialRef
 		*out = new(SecretRef)
 		**out = **in
 	}
+	if in.AviUserPasswordRotationInterval != nil {
+		in, out := &in.AviUserPasswordRotationInterval, &out.AviUserPasswordRotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AviUserPasswordPolicy != nil {
+		in, out := &in.AviUserPasswordPolicy, &out.AviUserPasswordPolicy
+		*out = new(AviPasswordPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdminCredentialRef != nil {
 		in, out := &in.AdminCredentialRef, &out.AdminCredentialRef
 		*out = new(SecretRef)
@@ -90,10 +106,20 @@ func (in *AKODeploymentConfigSpec) DeepCopyInto(out *AKODeploymentConfigSpec) {
 		*out = new(SecretRef)
 		**out = **in
 	}
-	out.Tenant = in.Tenant
+	in.Tenant.DeepCopyInto(&out.Tenant)
+	if in.RoleProfile != nil {
+		in, out := &in.RoleProfile, &out.RoleProfile
+		*out = new(AviRoleProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	in.DataNetwork.DeepCopyInto(&out.DataNetwork)
 	out.ControlPlaneNetwork = in.ControlPlaneNetwork
 	in.ExtraConfigs.DeepCopyInto(&out.ExtraConfigs)
+	if in.VIPPoolLowThreshold != nil {
+		in, out := &in.VIPPoolLowThreshold, &out.VIPPoolLowThreshold
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKODeploymentConfigSpec.
@@ -116,6 +142,48 @@ func (in *AKODeploymentConfigStatus) DeepCopyInto(out *AKODeploymentConfigStatus
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaticIPRanges != nil {
+		in, out := &in.StaticIPRanges, &out.StaticIPRanges
+		*out = make([]NetworkIPPool, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]NetworkSubnet, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsableNetworks != nil {
+		in, out := &in.UsableNetworks, &out.UsableNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VIPPools != nil {
+		in, out := &in.VIPPools, &out.VIPPools
+		*out = make([]VIPPoolStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterIPPools != nil {
+		in, out := &in.ClusterIPPools, &out.ClusterIPPools
+		*out = make([]ClusterIPPool, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterServiceEngineGroups != nil {
+		in, out := &in.ClusterServiceEngineGroups, &out.ClusterServiceEngineGroups
+		*out = make([]ClusterServiceEngineGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterTenants != nil {
+		in, out := &in.ClusterTenants, &out.ClusterTenants
+		*out = make([]ClusterTenant, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKODeploymentConfigStatus.
@@ -220,9 +288,30 @@ func (in *AKORbacConfig) DeepCopy() *AKORbacConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AVIClusterTenant) DeepCopyInto(out *AVIClusterTenant) {
+	*out = *in
+	in.ConfigSettings.DeepCopyInto(&out.ConfigSettings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AVIClusterTenant.
+func (in *AVIClusterTenant) DeepCopy() *AVIClusterTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(AVIClusterTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AVITenant) DeepCopyInto(out *AVITenant) {
 	*out = *in
+	if in.PerCluster != nil {
+		in, out := &in.PerCluster, &out.PerCluster
+		*out = new(AVIClusterTenant)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AVITenant.
@@ -235,6 +324,169 @@ func (in *AVITenant) DeepCopy() *AVITenant {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AVITenantConfigSettings) DeepCopyInto(out *AVITenantConfigSettings) {
+	*out = *in
+	if in.SEInProviderContext != nil {
+		in, out := &in.SEInProviderContext, &out.SEInProviderContext
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TenantAccessToProviderSE != nil {
+		in, out := &in.TenantAccessToProviderSE, &out.TenantAccessToProviderSE
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TenantVRF != nil {
+		in, out := &in.TenantVRF, &out.TenantVRF
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AVITenantConfigSettings.
+func (in *AVITenantConfigSettings) DeepCopy() *AVITenantConfigSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AVITenantConfigSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviPasswordPolicy) DeepCopyInto(out *AviPasswordPolicy) {
+	*out = *in
+	if in.CharacterClasses != nil {
+		in, out := &in.CharacterClasses, &out.CharacterClasses
+		*out = make([]PasswordCharacterClass, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviPasswordPolicy.
+func (in *AviPasswordPolicy) DeepCopy() *AviPasswordPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AviPasswordPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviPermission) DeepCopyInto(out *AviPermission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviPermission.
+func (in *AviPermission) DeepCopy() *AviPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(AviPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviRoleProfile) DeepCopyInto(out *AviRoleProfile) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]AviPermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedPermissions != nil {
+		in, out := &in.RemovedPermissions, &out.RemovedPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviRoleProfile.
+func (in *AviRoleProfile) DeepCopy() *AviRoleProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AviRoleProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterIPPool) DeepCopyInto(out *ClusterIPPool) {
+	*out = *in
+	out.IPPool = in.IPPool
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterIPPool.
+func (in *ClusterIPPool) DeepCopy() *ClusterIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceEngineGroup) DeepCopyInto(out *ClusterServiceEngineGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceEngineGroup.
+func (in *ClusterServiceEngineGroup) DeepCopy() *ClusterServiceEngineGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceEngineGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+	*out = *in
+	if in.ValuesHashHistory != nil {
+		in, out := &in.ValuesHashHistory, &out.ValuesHashHistory
+		*out = make([]ValuesHashRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastPasswordRotationTime != nil {
+		in, out := &in.LastPasswordRotationTime, &out.LastPasswordRotationTime
+		*out = (*in).DeepCopy()
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
+func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTenant) DeepCopyInto(out *ClusterTenant) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTenant.
+func (in *ClusterTenant) DeepCopy() *ClusterTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneNetwork) DeepCopyInto(out *ControlPlaneNetwork) {
 	*out = *in
@@ -403,6 +655,37 @@ func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkIPPool) DeepCopyInto(out *NetworkIPPool) {
+	*out = *in
+	out.IPPool = in.IPPool
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkIPPool.
+func (in *NetworkIPPool) DeepCopy() *NetworkIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSubnet) DeepCopyInto(out *NetworkSubnet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSubnet.
+func (in *NetworkSubnet) DeepCopy() *NetworkSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworksConfig) DeepCopyInto(out *NetworksConfig) {
 	*out = *in
@@ -416,6 +699,13 @@ func (in *NetworksConfig) DeepCopyInto(out *NetworksConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.VipNetworkList != nil {
+		in, out := &in.VipNetworkList, &out.VipNetworkList
+		*out = make([]VIPNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworksConfig.
@@ -478,9 +768,44 @@ func (in *SecretRef) DeepCopy() *SecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEngineGroupTemplate) DeepCopyInto(out *ServiceEngineGroupTemplate) {
+	*out = *in
+	if in.MaxSEs != nil {
+		in, out := &in.MaxSEs, &out.MaxSEs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxVSPerSE != nil {
+		in, out := &in.MaxVSPerSE, &out.MaxVSPerSE
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BufferSEs != nil {
+		in, out := &in.BufferSEs, &out.BufferSEs
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEngineGroupTemplate.
+func (in *ServiceEngineGroupTemplate) DeepCopy() *ServiceEngineGroupTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEngineGroupTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VIPNetwork) DeepCopyInto(out *VIPNetwork) {
 	*out = *in
+	if in.IPPools != nil {
+		in, out := &in.IPPools, &out.IPPools
+		*out = make([]IPPool, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VIPNetwork.
@@ -492,3 +817,35 @@ func (in *VIPNetwork) DeepCopy() *VIPNetwork {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VIPPoolStatus) DeepCopyInto(out *VIPPoolStatus) {
+	*out = *in
+	out.IPPool = in.IPPool
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VIPPoolStatus.
+func (in *VIPPoolStatus) DeepCopy() *VIPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VIPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValuesHashRecord) DeepCopyInto(out *ValuesHashRecord) {
+	*out = *in
+	in.ReplacedTime.DeepCopyInto(&out.ReplacedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesHashRecord.
+func (in *ValuesHashRecord) DeepCopy() *ValuesHashRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesHashRecord)
+	in.DeepCopyInto(out)
+	return out
+}
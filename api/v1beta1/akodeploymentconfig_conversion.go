@@ -0,0 +1,11 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// Hub marks AKODeploymentConfig as the conversion hub, older versions
+// convert to and from it
+func (*AKODeploymentConfig) Hub() {}
+
+// Hub marks AKODeploymentConfigList as the conversion hub
+func (*AKODeploymentConfigList) Hub() {}
@@ -0,0 +1,908 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// AKODeploymentConfigSpec defines the desired state of an AKODeploymentConfig
+// AKODeploymentConfig describes the shared configurations for AKO deployments across a set
+// of Clusters.
+type AKODeploymentConfigSpec struct {
+	// CloudName speficies the AVI Cloud AKO will be deployed with
+	CloudName string `json:"cloudName"`
+
+	// Controller is the AVI Controller endpoint to which AKO talks to
+	// provision Load Balancer resources
+	// The format is [scheme://]address[:port]
+	// * scheme                     http or https, defaults to https if not
+	//                              specified
+	// * address                    IP address of the AVI Controller
+	//                              specified
+	// * port                       if not specified, use default port for
+	//                              the corresponding scheme
+	Controller string `json:"controller"`
+
+	// ControllerVersion is the AVI Controller version which AKO Operator and AKO talks to.
+	// this value can be auto detected and corrected.
+	ControllerVersion string `json:"controllerVersion,omitempty"`
+
+	// ServiceEngineGroup is the group name of Service Engine that's to be used by the set
+	// of AKO Deployments
+	ServiceEngineGroup string `json:"serviceEngineGroup"`
+
+	// ServiceEngineGroupTemplate describes the Service Engine Group named by
+	// ServiceEngineGroup. When it's set, the Service Engine Group is created
+	// in the cloud if it doesn't exist and the fields set in the template are
+	// kept in sync.
+	// +optional
+	ServiceEngineGroupTemplate *ServiceEngineGroupTemplate `json:"serviceEngineGroupTemplate,omitempty"`
+
+	// ServiceEngineGroupPerCluster dedicates a Service Engine Group to each
+	// selected Cluster. It's cloned from ServiceEngineGroup, named
+	// <namespace>_<name>-seg after the Cluster, and deleted once AKO cleaned
+	// up the Avi resources of the deleted Cluster. Turning it off moves AKO
+	// back to ServiceEngineGroup, the groups are deleted once AKO moved its
+	// Virtual Services out of them.
+	// +optional
+	ServiceEngineGroupPerCluster bool `json:"serviceEngineGroupPerCluster,omitempty"`
+
+	// Label selector for Clusters. The Clusters that are
+	// selected by this will be the ones affected by this
+	// AKODeploymentConfig.
+	// It must match the Cluster labels. This field is immutable.
+	// +optional
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// WorkloadCredentialRef points to a Secret resource which includes the username
+	// and password to access and configure the Avi Controller.
+	//
+	// * username                   Username used with basic authentication for
+	//                              the Avi REST API
+	// * password                   Password used with basic authentication for
+	//                              the Avi REST API
+	//
+	// This field is optional. When it's not specified, username/password
+	// will be automatically generated for each Cluster and Tenant needs to
+	// be non-nil in this case.
+	// +optional
+	WorkloadCredentialRef *SecretRef `json:"workloadCredentialRef,omitempty"`
+
+	// AviUserPasswordRotationInterval is how often the password of the Avi
+	// user generated for each Cluster is rotated. The rotation of a single
+	// Cluster can be forced at any time with the
+	// networking.tkg.tanzu.vmware.com/rotate-avi-user-password annotation.
+	// Every rotation switches the Cluster to the other of its two Avi
+	// users, the previous one is deleted once AKO runs with the new one.
+	// It has no effect when WorkloadCredentialRef is set.
+	// When it's not specified, the passwords are never rotated on a schedule.
+	// +optional
+	AviUserPasswordRotationInterval *metav1.Duration `json:"aviUserPasswordRotationInterval,omitempty"`
+
+	// AviUserPasswordPolicy describes the passwords generated for the Avi
+	// user of each Cluster, the unset fields are taken from the policy the
+	// operator is started with. The policy has to meet the password
+	// complexity settings of the Avi Controller.
+	// It has no effect when WorkloadCredentialRef is set.
+	// +optional
+	AviUserPasswordPolicy *AviPasswordPolicy `json:"aviUserPasswordPolicy,omitempty"`
+
+	// AdminCredentialRef points to a Secret resource which includes the username
+	// and password to access and configure the Avi Controller.
+	//
+	// * username                   Username used with basic authentication for
+	//                              the Avi REST API
+	// * password                   Password used with basic authentication for
+	//                              the Avi REST API
+	//
+	// This credential needs to be bound with admin tenant and will be used
+	// by AKO Operator to automate configurations and operations.
+	AdminCredentialRef *SecretRef `json:"adminCredentialRef"`
+
+	// CertificateAuthorityRef points to a Secret resource that includes the
+	// AVI Controller's CA
+	//
+	// * certificateAuthorityData   PEM-encoded certificate authority
+	//                              certificates
+	//
+	CertificateAuthorityRef *SecretRef `json:"certificateAuthorityRef"`
+
+	// The AVI tenant for the current AKODeploymentConfig
+	// This field is optional.
+	// +optional
+	Tenant AVITenant `json:"tenant,omitempty"`
+
+	// RoleProfile customizes the permissions of the Avi role bound to the
+	// Avi users generated for the Clusters. When it's not specified, the
+	// users are bound to the ako-essential-role.
+	// +optional
+	RoleProfile *AviRoleProfile `json:"roleProfile,omitempty"`
+
+	// DataNetworks describes the Data Networks the AKO will be deployed
+	// with.
+	// This field is immutable.
+	DataNetwork DataNetwork `json:"dataNetwork"`
+
+	// ControlPlaneNetwork describes the control plane network of the clusters selected by an akoDeploymentConfig
+	//
+	// +optional
+	ControlPlaneNetwork ControlPlaneNetwork `json:"controlPlaneNetwork,omitempty"`
+
+	// ExtraConfigs contains extra configurations for AKO Deployment
+	//
+	// +optional
+	ExtraConfigs ExtraConfigs `json:"extraConfigs,omitempty"`
+
+	// VIPPoolLowThreshold is the percentage of free IPs under which a
+	// static IP pool of the data or VIP networks is reported low by the
+	// VIPPoolLow condition. 0 disables the check.
+	// Default value: 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	VIPPoolLowThreshold *int32 `json:"vipPoolLowThreshold,omitempty"`
+
+	// Delivery is how AKO is delivered to the selected Clusters:
+	//
+	// * TKGAddon             a TKG add-on secret, installed by the
+	//                        addons-manager of tanzu-framework
+	// * ClusterBootstrap     a TKG add-on secret referenced by the
+	//                        ClusterBootstrap of the Cluster
+	// * ClusterResourceSet   a Cluster API ClusterResourceSet
+	// * Direct               server-side applied in the Cluster by AKO
+	//                        Operator, which reverts the changes made to it
+	//
+	// When it's not specified, ClusterBootstrap is used for the ClusterClass
+	// based Clusters and TKGAddon for the others. This field is immutable,
+	// the backends don't hand AKO over to each other.
+	// +optional
+	Delivery AKODelivery `json:"delivery,omitempty"`
+}
+
+// AKODelivery is the backend delivering AKO to the Clusters
+// +kubebuilder:validation:Enum=TKGAddon;ClusterBootstrap;ClusterResourceSet;Direct
+type AKODelivery string
+
+const (
+	AKODeliveryTKGAddon           AKODelivery = "TKGAddon"
+	AKODeliveryClusterBootstrap   AKODelivery = "ClusterBootstrap"
+	AKODeliveryClusterResourceSet AKODelivery = "ClusterResourceSet"
+	AKODeliveryDirect             AKODelivery = "Direct"
+)
+
+// ServiceEngineGroupTemplate contains the settings of a Service Engine Group
+// provisioned by AKO Operator. Unset fields are left to the Avi Controller.
+type ServiceEngineGroupTemplate struct {
+	// CloneFrom is the name of an existing Service Engine Group of the
+	// cloud, e.g. Default-Group, whose settings are copied when the Service
+	// Engine Group is created. The other fields of the template override
+	// the copied settings.
+	// +optional
+	CloneFrom string `json:"cloneFrom,omitempty"`
+
+	// HAMode is the high availability mode of the Service Engines
+	// +kubebuilder:validation:Enum=HA_MODE_SHARED_PAIR;HA_MODE_SHARED;HA_MODE_LEGACY_ACTIVE_STANDBY
+	// +optional
+	HAMode string `json:"haMode,omitempty"`
+
+	// MaxSEs is the maximum number of Service Engines in the group
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	MaxSEs *int32 `json:"maxSEs,omitempty"`
+
+	// MaxVSPerSE is the maximum number of Virtual Services placed on a
+	// Service Engine
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	MaxVSPerSE *int32 `json:"maxVSPerSE,omitempty"`
+
+	// Flavor is the instance flavor of the Service Engines
+	// +optional
+	Flavor string `json:"flavor,omitempty"`
+
+	// BufferSEs is the number of spare Service Engines kept to absorb a
+	// Service Engine failure
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BufferSEs *int32 `json:"bufferSEs,omitempty"`
+}
+
+// ExtraConfigs contains extra configurations for AKO Deployment
+type ExtraConfigs struct {
+	// Defines the number of AKO instances to deploy to allow of high availablity. Max number of replicas is two.
+	// Default value: 1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	ReplicaCount *int `json:"replicaCount,omitempty"`
+
+	// Defines AKO instance is primary or not. Value `true` indicates that AKO instance is primary.
+	// In a multiple AKO deployment in a cluster, only one AKO instance should be primary.
+	// Default value: true.
+	// +optional
+	PrimaryInstance *bool `json:"primaryInstance,omitempty"`
+
+	// Log specifies the configuration for AKO logging
+	// +optional
+	Log AKOLogConfig `json:"log,omitempty"`
+
+	// FullSyncFrequency controls how often AKO polls the Avi controller to update itself
+	// with cloud configurations. Default value is 1800
+	// +optional
+	FullSyncFrequency string `json:"fullSyncFrequency,omitempty"`
+
+	// ApiServerPort specifies Internal port for AKO's API server for the liveness probe of the AKO pod
+	// default port is 8080
+	// +optional
+	ApiServerPort *int `json:"apiServerPort,omitempty"`
+
+	// Defines Enable or disable Event broadcasting via AKO
+	// +optional
+	EnableEvents *bool `json:"enableEvents,omitempty"`
+
+	// DisableStaticRouteSync describes ako should sync static routing or not.
+	// If the POD networks are reachable from the Avi SE, this should be to true.
+	// Otherwise, it should be false.
+	// It would be true by default.
+	// +optional
+	DisableStaticRouteSync *bool `json:"disableStaticRouteSync,omitempty"`
+
+	// CniPlugin describes which cni plugin cluster is using.
+	// default value is antrea, set this string if cluster cni is other type.
+	// For Cilium CNI, set the string as cilium only when using Cluster Scope mode for IPAM
+	// and leave it empty if using Kubernetes Host Scope mode for IPAM.
+	// AKO supported CNI: antrea|calico|canal|flannel|openshift|ncp|ovn-kubernetes|cilium
+	// +kubebuilder:validation:Enum=antrea;calico;canal;flannel;openshift;ncp;ovn-kubernetes;cilium
+	// +optional
+	CniPlugin string `json:"cniPlugin,omitempty"`
+
+	// EnableEVH specifies if you want to enable the Enhanced Virtual Hosting Model
+	// in Avi Controller for the Virtual Services, default value is false
+	// +optional
+	EnableEVH *bool `json:"enableEVH,omitempty"`
+
+	// Layer7Only specifies if you want AKO only to do layer 7 load balancing.
+	// default value is false
+	// +optional
+	Layer7Only *bool `json:"layer7Only,omitempty"`
+
+	// NameSpaceSelector contains label key and value used for namespace migration.
+	// Same label has to be present on namespace/s which needs migration/sync to AKO
+	// +optional
+	NamespaceSelector NamespaceSelector `json:"namespaceSelector,omitempty"`
+
+	// ServicesAPI specifies if enables AKO in services API mode: https://kubernetes-sigs.github.io/service-apis/.
+	// Currently, implemented only for L4. This flag uses the upstream GA APIs which are not backward compatible
+	// with the advancedL4 APIs which uses a fork and a version of v1alpha1pre1
+	// default value is false
+	// +optional
+	ServicesAPI *bool `json:"servicesAPI,omitempty"`
+
+	// Enabling this flag would tell AKO to create Parent VS per Namespace in EVH mode
+	// default value is false
+	// +optional
+	VIPPerNamespace *bool `json:"vipPerNamespace,omitempty"`
+
+	// This flag needs to be enabled when AKO is be to brought up in an Istio environment
+	// default value is false
+	// +optional
+	IstioEnabled *bool `json:"istioEnabled,omitempty"`
+
+	// This is the list of system namespaces from which AKO will not listen any Kubernetes object event.
+	// +optional
+	BlockedNamespaceList []string `json:"blockedNamespaceList,omitempty"`
+
+	// This flag can take values V4 or V6 (default V4)
+	// default value is V4
+	// +kubebuilder:validation:Enum=V4;V6
+	// +optional
+	IpFamily string `json:"ipFamily,omitempty"`
+
+	// If this flag is set to true, AKO will only handle default secrets from the namespace where AKO is installed
+	// This flag is applicable only to Openshift clusters
+	// default value is false
+	// +optional
+	UseDefaultSecretsOnly *bool `json:"useDefaultSecretsOnly,omitempty"`
+
+	// NetworksConfig specifies the network configurations for virtual services.
+	// +optional
+	NetworksConfig NetworksConfig `json:"networksConfig,omitempty"`
+
+	// IngressConfigs specifies ingress configuration for ako
+	// +optional
+	IngressConfigs AKOIngressConfig `json:"ingress,omitempty"`
+
+	// IngressConfigs specifies L4 load balancer configuration for ako
+	// +optional
+	L4Configs AKOL4Config `json:"l4Config,omitempty"`
+
+	// NodePortSelector selects the nodes AKO adds to the pools of NodePort
+	// services, it's only applicable if serviceType is NodePort
+	// +optional
+	NodePortSelector *metav1.LabelSelector `json:"nodePortSelector,omitempty"`
+
+	// Rbac specifies the configuration for AKO Rbac
+	// +optional
+	Rbac AKORbacConfig `json:"rbac,omitempty"`
+
+	// FeatureGates specifies the configuration for AKO features
+	// +optional
+	FeatureGates FeatureGates `json:"featureGates,omitempty"`
+}
+
+// NameSpaceSelector contains label key and value used for namespace migration
+type NamespaceSelector struct {
+	LabelKey   string `json:"labelKey,omitempty"`
+	LabelValue string `json:"labelValue,omitempty"`
+}
+
+type NetworksConfig struct {
+	// EnableRHI specifies cluster wide setting for BGP peering.
+	// default value is false
+	// +optional
+	EnableRHI *bool `json:"enableRHI,omitempty"`
+
+	// BGPPeerLabels specifies BGP peers, this is used for selective VsVip advertisement.
+	// +optional
+	BGPPeerLabels []string `json:"bgpPeerLabels,omitempty"`
+
+	// T1 Logical Segment mapping for backend network. Only applies to NSX-T cloud.
+	// +optional
+	NsxtT1LR string `json:"nsxtT1LR,omitempty"`
+
+	// VipNetworkList specifies the networks AKO allocates virtual service
+	// VIPs from, e.g. to split public and internal VIPs or for clouds with
+	// several VIP networks. The operator adds the CIDRs and IP pools of each
+	// network to the Avi network and registers it as a usable network of
+	// the cloud. The data network can't be one of them.
+	// default will be the network specified in Data Networks
+	// +optional
+	VipNetworkList []VIPNetwork `json:"vipNetworkList,omitempty"`
+}
+
+// AKOIngressConfig contains ingress configurations for AKO Deployment
+type AKOIngressConfig struct {
+	// DisableIngressClass will prevent AKO Operator to install AKO
+	// IngressClass into workload clusters for old version of K8s
+	//
+	// +optional
+	DisableIngressClass *bool `json:"disableIngressClass,omitempty"`
+
+	// DefaultIngressController bool describes ako is the default
+	// ingress controller to use
+	//
+	// +optional
+	DefaultIngressController *bool `json:"defaultIngressController,omitempty"`
+
+	// ServiceType string describes ingress methods for a service
+	// Valid value should be NodePort, ClusterIP and NodePortLocal
+	// +kubebuilder:validation:Enum=NodePort;ClusterIP;NodePortLocal
+	// +optional
+	ServiceType string `json:"serviceType,omitempty"`
+
+	// ShardVSSize describes ingress shared virtual service size
+	// Valid value should be SMALL, MEDIUM, LARGE or DEDICATED, default value is SMALL
+	// +kubebuilder:validation:Enum=SMALL;MEDIUM;LARGE;DEDICATED
+	// +optional
+	ShardVSSize string `json:"shardVSSize,omitempty"`
+
+	// PassthroughShardSize controls the passthrough virtualservice numbers
+	// Valid value should be SMALL, MEDIUM or LARGE, default value is SMALL
+	// +kubebuilder:validation:Enum=SMALL;MEDIUM;LARGE
+	// +optional
+	PassthroughShardSize string `json:"passthroughShardSize,omitempty"`
+
+	// NodeNetworkList describes the details of network and CIDRs
+	// are used in pool placement network for vcenter cloud. Node Network details
+	// are not needed when in NodePort mode / static routes are disabled / non vcenter clouds.
+	// +optional
+	NodeNetworkList []NodeNetwork `json:"nodeNetworkList,omitempty"`
+
+	// NoPGForSNI describes if you want to get rid of poolgroups from SNI VSes.
+	// Do not use this flag, if you don't want http caching, default value is false.
+	// +optional
+	NoPGForSNI *bool `json:"noPGForSNI,omitempty"`
+
+	// Enabling this flag would tell AKO to start processing multi-cluster ingress objects
+	// +optional
+	EnableMCI *bool `json:"enableMCI,omitempty"`
+}
+
+// AKOL4Config contains L4 load balancer configurations for AKO Deployment
+type AKOL4Config struct {
+	// DefaultDomain controls the default sub-domain to use for L4 VSes when multiple sub-domains
+	// are configured in the cloud.
+	// +optional
+	DefaultDomain string `json:"defaultDomain,omitempty"`
+
+	// AutoFQDN controls the FQDN generation.
+	// Valid value should be default(<svc>.<ns>.<subdomain>), flat (<svc>-<ns>.<subdomain>) or disabled,
+	// +kubebuilder:validation:Enum=default;flat;disabled
+	// +optional
+	AutoFQDN string `json:"autoFQDN,omitempty"`
+}
+
+type NodeNetwork struct {
+	// NetworkName is the name of this network
+	// +optional
+	NetworkName string `json:"networkName,omitempty"`
+	// Cidrs represents all the IP CIDRs in this network
+	// +optional
+	Cidrs []string `json:"cidrs,omitempty"`
+}
+
+type AKOLogConfig struct {
+	// LogLevel specifies the AKO pod log level
+	// Valid value should be INFO, DEBUG, WARN or ERROR, default value is INFO
+	// +kubebuilder:validation:Enum=INFO;DEBUG;WARN;ERROR
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// PersistentVolumeClaim specifies if a PVC should make for AKO logging
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+
+	// MountPath specifies the path to mount PVC
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// LogFile specifies the log file name
+	// +optional
+	LogFile string `json:"logFile,omitempty"`
+
+	// AKOGatewayLogFile specifies the AKO Gateway log file name
+	// +optional
+	AKOGatewayLogFile string `json:"akoGatewayLogFile,omitempty"`
+}
+
+type AKORbacConfig struct {
+	// PspPolicyAPIVersion decides the API version of the PodSecurityPolicy
+	PspPolicyAPIVersion string `json:"pspPolicyAPIVersion,omitempty"`
+
+	// PspEnabled enables the deployment of a PodSecurityPolicy that grants
+	// AKO the proper role
+	// +optional
+	PspEnabled *bool `json:"pspEnabled,omitempty"`
+}
+
+// FeatureGates describes the configuration for AKO features
+type FeatureGates struct {
+	// GatewayAPI enables/disables processing of Kubernetes Gateway API CRDs.
+	// +optional
+	GatewayAPI *bool `json:"gatewayAPI,omitempty"`
+}
+
+// AviRoleProfile describes how the permissions of an Avi role differ from the
+// ones AKO is granted by default
+type AviRoleProfile struct {
+	// Name is the name of the profile, its Avi role is named
+	// ako-essential-role-<name>-<hash> after the hash of the permissions.
+	// AKODeploymentConfigs share the role only when their profiles are the
+	// same, the role is deleted once no Avi user is bound to it.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Permissions adds permissions to the default ones, or downgrades the
+	// access type of the optional default ones listed by RemovedPermissions.
+	// +optional
+	Permissions []AviPermission `json:"permissions,omitempty"`
+
+	// RemovedPermissions lists optional default permissions AKO can do
+	// without, e.g. PERMISSION_PKIPROFILE when L7 is disabled.
+	// +optional
+	RemovedPermissions []string `json:"removedPermissions,omitempty"`
+}
+
+// AviPermission describes the access type of an Avi role to a resource
+type AviPermission struct {
+	// Resource is the resource of the permission, e.g.
+	// PERMISSION_VIRTUALSERVICE
+	Resource string `json:"resource"`
+
+	// Type is the access type to the resource.
+	// +kubebuilder:validation:Enum=NO_ACCESS;READ_ACCESS;WRITE_ACCESS
+	Type string `json:"type"`
+}
+
+// AVITenant describes settings for an AVI Tenant object
+type AVITenant struct {
+	// Context is the type of AVI tenant context. Defaults to Provider. This field is immutable.
+	// +kubebuilder:validation:Enum=Provider;Tenant
+	Context string `json:"context,omitempty"`
+
+	// Name is the name of an existing tenant. This field is immutable.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// PerCluster creates a dedicated tenant for each selected workload
+	// cluster instead, AKO and its Avi user are confined to the tenant of
+	// their cluster. Name is ignored when it's set. Whether it's set is
+	// immutable, the clusters can't move between the tenants.
+	// +optional
+	PerCluster *AVIClusterTenant `json:"perCluster,omitempty"`
+}
+
+// AVIClusterTenant describes the tenants created for the workload clusters
+type AVIClusterTenant struct {
+	// NameTemplate is the Go template of the tenant name, it's executed
+	// against the Cluster metadata, e.g. {{.Namespace}}-{{.Name}}, which
+	// is the default.
+	// +optional
+	NameTemplate string `json:"nameTemplate,omitempty"`
+
+	// ConfigSettings are the settings the tenants are created with.
+	// +optional
+	ConfigSettings AVITenantConfigSettings `json:"configSettings,omitempty"`
+}
+
+// AVITenantConfigSettings are the config settings of an AVI Tenant object,
+// the Avi Controller defaults apply to the unset ones
+type AVITenantConfigSettings struct {
+	// SEInProviderContext places the Service Engines of the tenant in the
+	// provider context, i.e. they're shared with the other tenants.
+	// +optional
+	SEInProviderContext *bool `json:"seInProviderContext,omitempty"`
+
+	// TenantAccessToProviderSE allows the tenant to use the Service Engines
+	// of the provider context.
+	// +optional
+	TenantAccessToProviderSE *bool `json:"tenantAccessToProviderSE,omitempty"`
+
+	// TenantVRF gives the tenant its own VRF contexts.
+	// +optional
+	TenantVRF *bool `json:"tenantVRF,omitempty"`
+}
+
+// AviPasswordPolicy describes generated passwords
+type AviPasswordPolicy struct {
+	// Length of the passwords, 16 by default.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	Length int32 `json:"length,omitempty"`
+
+	// CharacterClasses the passwords are made of, they contain one
+	// character of each class at least. All of them by default.
+	// +optional
+	CharacterClasses []PasswordCharacterClass `json:"characterClasses,omitempty"`
+
+	// ExcludedCharacters are never used in the passwords, e.g. the ones
+	// which are hard to tell apart.
+	// +optional
+	ExcludedCharacters string `json:"excludedCharacters,omitempty"`
+}
+
+// PasswordCharacterClass is a class of characters of the passwords
+// +kubebuilder:validation:Enum=Lowercase;Uppercase;Digit;Special
+type PasswordCharacterClass string
+
+// DataNetwork describes one AVI Data Network
+type DataNetwork struct {
+	Name string `json:"name"`
+	// CIDR is the IPv4 or, for IPv6 only data networks, the IPv6 subnet of
+	// the network
+	CIDR string `json:"cidr"`
+	// V6CIDR is the IPv6 subnet of a dual-stack data network, CIDR is its
+	// IPv4 subnet
+	// +optional
+	V6CIDR string `json:"v6cidr,omitempty"`
+	// IPPools are the static IP ranges of the network, each one is added
+	// to the subnet of its type
+	// +optional
+	IPPools []IPPool `json:"ipPools,omitempty"`
+	// ClusterIPPoolSize opts in dedicated VIP sub-pools: every selected
+	// Cluster is given a contiguous range of ClusterIPPoolSize IPs carved
+	// from IPPools, configured as a subnet of its own in the Avi network
+	// and the only one the AKO of the Cluster allocates VIPs from. The
+	// size must be a power of two, the ranges are aligned subnets.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ClusterIPPoolSize int32 `json:"clusterIPPoolSize,omitempty"`
+}
+
+// ControlPlaneNetwork describes the ControlPlane Network of the clusters selected by an akoDeploymentConfig
+type ControlPlaneNetwork struct {
+	Name string `json:"name"`
+	CIDR string `json:"cidr"`
+}
+
+// VIPNetwork describes a VIPNetwork in the adc file
+type VIPNetwork struct {
+	// NetworkName is the name of the Avi network
+	NetworkName string `json:"networkName"`
+	// CIDR is the IPv4 subnet of the network
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+	// V6CIDR is the IPv6 subnet of the network
+	// +optional
+	V6CIDR string `json:"v6cidr,omitempty"`
+	// IPPools are the static IP ranges of the network, each one is added
+	// to the subnet of its type
+	// +optional
+	IPPools []IPPool `json:"ipPools,omitempty"`
+}
+
+// IPPool defines a contiguous range of IP Addresses
+type IPPool struct {
+	// Start represents the starting IP address of the pool.
+	Start string `json:"start"`
+	// End represents the ending IP address of the pool.
+	End string `json:"end"`
+	// Type represents the type of IP Address
+	// +kubebuilder:validation:Enum=V4;V6
+	Type string `json:"type"`
+}
+
+// SecretRef references a Kind Secret object in the same kubernetes
+// cluster
+type SecretRef struct {
+	// Name is the name of resource being referenced.
+	Name string `json:"name"`
+	// Namespace of the resource being referenced.
+	Namespace string `json:"namespace"`
+}
+
+// AKODeploymentConfigStatus defines the observed state of AKODeploymentConfig
+type AKODeploymentConfigStatus struct {
+	// ObservedGeneration reflects the generation of the most recently
+	// observed AKODeploymentConfig.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current state of the AKODeploymentConfig.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// Clusters lists the Clusters selected by the AKODeploymentConfig and
+	// the rollout state of AKO on each of them.
+	// +optional
+	Clusters []ClusterStatus `json:"clusters,omitempty"`
+
+	// SelectedClusters is the number of Clusters selected by the
+	// AKODeploymentConfig.
+	// +optional
+	SelectedClusters int32 `json:"selectedClusters"`
+
+	// ReadyClusters is the number of selected Clusters in the Ready phase.
+	// +optional
+	ReadyClusters int32 `json:"readyClusters"`
+
+	// FailedClusters is the number of selected Clusters in the Failed phase.
+	// +optional
+	FailedClusters int32 `json:"failedClusters"`
+
+	// StaticIPRanges lists the static IP ranges the operator added to the
+	// subnets of the Avi networks. Only these ranges are removed once they
+	// leave the spec, ranges created by hand are left untouched.
+	// +optional
+	StaticIPRanges []NetworkIPPool `json:"staticIPRanges,omitempty"`
+
+	// Subnets lists the subnets the operator added to the Avi networks.
+	// They are removed along with the AKODeploymentConfig unless another
+	// AKODeploymentConfig still uses them.
+	// +optional
+	Subnets []NetworkSubnet `json:"subnets,omitempty"`
+
+	// UsableNetworks lists the networks the operator added to the usable
+	// networks of the cloud's IPAM profile. They are removed along with the
+	// AKODeploymentConfig unless another AKODeploymentConfig still uses
+	// them.
+	// +optional
+	UsableNetworks []string `json:"usableNetworks,omitempty"`
+
+	// VIPPools reports the utilization of the static IP pools of the data
+	// and VIP networks, it's refreshed periodically from the Avi Controller.
+	// +optional
+	VIPPools []VIPPoolStatus `json:"vipPools,omitempty"`
+
+	// ClusterIPPools lists the sub-ranges of the data network IP pools
+	// dedicated to the selected Clusters when ClusterIPPoolSize is set.
+	// +optional
+	ClusterIPPools []ClusterIPPool `json:"clusterIPPools,omitempty"`
+
+	// ClusterServiceEngineGroups lists the Service Engine Groups created
+	// for the selected Clusters when ServiceEngineGroupPerCluster is set.
+	// +optional
+	ClusterServiceEngineGroups []ClusterServiceEngineGroup `json:"clusterServiceEngineGroups,omitempty"`
+
+	// ClusterTenants lists the Avi tenants created for the selected
+	// Clusters when Tenant.PerCluster is set.
+	// +optional
+	ClusterTenants []ClusterTenant `json:"clusterTenants,omitempty"`
+}
+
+// ClusterTenant is the Avi tenant dedicated to a Cluster
+type ClusterTenant struct {
+	// Name is the name of the Cluster.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Cluster.
+	Namespace string `json:"namespace"`
+
+	// Tenant is the name of the Avi tenant.
+	Tenant string `json:"tenant"`
+}
+
+// ClusterServiceEngineGroup is the Service Engine Group dedicated to a Cluster
+type ClusterServiceEngineGroup struct {
+	// Name is the name of the Cluster.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Cluster.
+	Namespace string `json:"namespace"`
+
+	// ServiceEngineGroup is the name of the Service Engine Group.
+	ServiceEngineGroup string `json:"serviceEngineGroup"`
+}
+
+// ClusterIPPool is the sub-range of the data network IP pools dedicated to a
+// Cluster
+type ClusterIPPool struct {
+	// Name is the name of the Cluster.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Cluster.
+	Namespace string `json:"namespace"`
+
+	IPPool `json:",inline"`
+}
+
+// VIPPoolStatus is the utilization of a static IP pool of an Avi network
+type VIPPoolStatus struct {
+	// NetworkName is the name of the Avi network holding the pool.
+	NetworkName string `json:"networkName"`
+
+	IPPool `json:",inline"`
+
+	// Total is the number of IPs of the pool.
+	Total int64 `json:"total"`
+
+	// Used is the number of IPs of the pool allocated by the Avi
+	// Controller.
+	Used int64 `json:"used"`
+
+	// Free is the number of IPs of the pool left to allocate.
+	Free int64 `json:"free"`
+}
+
+// NetworkIPPool is an IP pool added as a static IP range to an Avi network
+type NetworkIPPool struct {
+	// NetworkName is the name of the Avi network holding the range.
+	NetworkName string `json:"networkName"`
+
+	IPPool `json:",inline"`
+}
+
+// NetworkSubnet is a subnet added to an Avi network
+type NetworkSubnet struct {
+	// NetworkName is the name of the Avi network holding the subnet.
+	NetworkName string `json:"networkName"`
+
+	// CIDR of the subnet.
+	CIDR string `json:"cidr"`
+}
+
+// ClusterPhase is the rollout phase of AKO on a Cluster selected by an
+// AKODeploymentConfig
+// +kubebuilder:validation:Enum=Pending;Deploying;Ready;Deleting;Failed
+type ClusterPhase string
+
+const (
+	// ClusterPhasePending means the AKO values haven't been rendered for the
+	// Cluster yet
+	ClusterPhasePending ClusterPhase = "Pending"
+
+	// ClusterPhaseDeploying means the AKO values have just been rendered or
+	// changed, or one of the phases asked for a requeue
+	ClusterPhaseDeploying ClusterPhase = "Deploying"
+
+	// ClusterPhaseReady means every phase succeeded and the rendered values
+	// are up to date
+	ClusterPhaseReady ClusterPhase = "Ready"
+
+	// ClusterPhaseDeleting means either the Cluster or the
+	// AKODeploymentConfig is being deleted
+	ClusterPhaseDeleting ClusterPhase = "Deleting"
+
+	// ClusterPhaseFailed means at least one phase returned an error
+	ClusterPhaseFailed ClusterPhase = "Failed"
+)
+
+// ValuesHashRecord is a previous hash of the AKO values of a Cluster
+type ValuesHashRecord struct {
+	// Hash is the sha256 hash of the AKO values.
+	Hash string `json:"hash"`
+
+	// ReplacedTime is when the AKO values were replaced.
+	ReplacedTime metav1.Time `json:"replacedTime"`
+}
+
+// ClusterStatus describes the rollout state of AKO on one Cluster selected
+// by an AKODeploymentConfig
+type ClusterStatus struct {
+	// Name is the name of the Cluster.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Cluster.
+	Namespace string `json:"namespace"`
+
+	// Phase is the rollout phase of AKO on the Cluster.
+	Phase ClusterPhase `json:"phase"`
+
+	// ValuesHash is the sha256 hash of the last AKO values rendered for the
+	// Cluster.
+	// +optional
+	ValuesHash string `json:"valuesHash,omitempty"`
+
+	// ValuesHashHistory lists the previous hashes of the AKO values rendered
+	// for the Cluster, the most recent first, to tell when AKO got
+	// re-applied.
+	// +optional
+	ValuesHashHistory []ValuesHashRecord `json:"valuesHashHistory,omitempty"`
+
+	// Delivery is the backend AKO was last delivered to the Cluster with.
+	// +optional
+	Delivery AKODelivery `json:"delivery,omitempty"`
+
+	// LastError is the error returned by the last failed reconciliation of
+	// the Cluster. It's cleared once the Cluster reconciles successfully.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastPasswordRotationTime is the last time the password of the Avi
+	// user generated for the Cluster changed, i.e. it was generated or
+	// rotated.
+	// +optional
+	LastPasswordRotationTime *metav1.Time `json:"lastPasswordRotationTime,omitempty"`
+
+	// LastTransitionTime is the last time Phase changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=adc,path=akodeploymentconfigs,scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Clusters",type="integer",JSONPath=".status.selectedClusters",description="Number of Clusters selected by the AKODeploymentConfig"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyClusters",description="Number of selected Clusters in the Ready phase"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedClusters",description="Number of selected Clusters in the Failed phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AKODeploymentConfig is the Schema for the akodeploymentconfigs API
+type AKODeploymentConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AKODeploymentConfigSpec   `json:"spec,omitempty"`
+	Status AKODeploymentConfigStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the conditions of the AKODeploymentConfig
+func (r *AKODeploymentConfig) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the conditions of the AKODeploymentConfig
+func (r *AKODeploymentConfig) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// AKODeploymentConfigList contains a list of AKODeploymentConfig
+type AKODeploymentConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AKODeploymentConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AKODeploymentConfig{}, &AKODeploymentConfigList{})
+}
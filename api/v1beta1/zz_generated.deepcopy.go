@@ -0,0 +1,840 @@
+//go:build !ignore_autogenerated
+
+// Copyright 2020 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKODeploymentConfig) DeepCopyInto(out *AKODeploymentConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKODeploymentConfig.
+func (in *AKODeploymentConfig) DeepCopy() *AKODeploymentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AKODeploymentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AKODeploymentConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKODeploymentConfigList) DeepCopyInto(out *AKODeploymentConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AKODeploymentConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKODeploymentConfigList.
+func (in *AKODeploymentConfigList) DeepCopy() *AKODeploymentConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(AKODeploymentConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AKODeploymentConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKODeploymentConfigSpec) DeepCopyInto(out *AKODeploymentConfigSpec) {
+	*out = *in
+	if in.ServiceEngineGroupTemplate != nil {
+		in, out := &in.ServiceEngineGroupTemplate, &out.ServiceEngineGroupTemplate
+		*out = new(ServiceEngineGroupTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	if in.WorkloadCredentialRef != nil {
+		in, out := &in.WorkloadCredentialRef, &out.WorkloadCredentialRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.AviUserPasswordRotationInterval != nil {
+		in, out := &in.AviUserPasswordRotationInterval, &out.AviUserPasswordRotationInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AviUserPasswordPolicy != nil {
+		in, out := &in.AviUserPasswordPolicy, &out.AviUserPasswordPolicy
+		*out = new(AviPasswordPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminCredentialRef != nil {
+		in, out := &in.AdminCredentialRef, &out.AdminCredentialRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	if in.CertificateAuthorityRef != nil {
+		in, out := &in.CertificateAuthorityRef, &out.CertificateAuthorityRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	in.Tenant.DeepCopyInto(&out.Tenant)
+	if in.RoleProfile != nil {
+		in, out := &in.RoleProfile, &out.RoleProfile
+		*out = new(AviRoleProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	in.DataNetwork.DeepCopyInto(&out.DataNetwork)
+	out.ControlPlaneNetwork = in.ControlPlaneNetwork
+	in.ExtraConfigs.DeepCopyInto(&out.ExtraConfigs)
+	if in.VIPPoolLowThreshold != nil {
+		in, out := &in.VIPPoolLowThreshold, &out.VIPPoolLowThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKODeploymentConfigSpec.
+func (in *AKODeploymentConfigSpec) DeepCopy() *AKODeploymentConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AKODeploymentConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKODeploymentConfigStatus) DeepCopyInto(out *AKODeploymentConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaticIPRanges != nil {
+		in, out := &in.StaticIPRanges, &out.StaticIPRanges
+		*out = make([]NetworkIPPool, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]NetworkSubnet, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsableNetworks != nil {
+		in, out := &in.UsableNetworks, &out.UsableNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VIPPools != nil {
+		in, out := &in.VIPPools, &out.VIPPools
+		*out = make([]VIPPoolStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterIPPools != nil {
+		in, out := &in.ClusterIPPools, &out.ClusterIPPools
+		*out = make([]ClusterIPPool, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterServiceEngineGroups != nil {
+		in, out := &in.ClusterServiceEngineGroups, &out.ClusterServiceEngineGroups
+		*out = make([]ClusterServiceEngineGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterTenants != nil {
+		in, out := &in.ClusterTenants, &out.ClusterTenants
+		*out = make([]ClusterTenant, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKODeploymentConfigStatus.
+func (in *AKODeploymentConfigStatus) DeepCopy() *AKODeploymentConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AKODeploymentConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKOIngressConfig) DeepCopyInto(out *AKOIngressConfig) {
+	*out = *in
+	if in.DisableIngressClass != nil {
+		in, out := &in.DisableIngressClass, &out.DisableIngressClass
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultIngressController != nil {
+		in, out := &in.DefaultIngressController, &out.DefaultIngressController
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeNetworkList != nil {
+		in, out := &in.NodeNetworkList, &out.NodeNetworkList
+		*out = make([]NodeNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NoPGForSNI != nil {
+		in, out := &in.NoPGForSNI, &out.NoPGForSNI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableMCI != nil {
+		in, out := &in.EnableMCI, &out.EnableMCI
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKOIngressConfig.
+func (in *AKOIngressConfig) DeepCopy() *AKOIngressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AKOIngressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKOL4Config) DeepCopyInto(out *AKOL4Config) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKOL4Config.
+func (in *AKOL4Config) DeepCopy() *AKOL4Config {
+	if in == nil {
+		return nil
+	}
+	out := new(AKOL4Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKOLogConfig) DeepCopyInto(out *AKOLogConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKOLogConfig.
+func (in *AKOLogConfig) DeepCopy() *AKOLogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AKOLogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKORbacConfig) DeepCopyInto(out *AKORbacConfig) {
+	*out = *in
+	if in.PspEnabled != nil {
+		in, out := &in.PspEnabled, &out.PspEnabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKORbacConfig.
+func (in *AKORbacConfig) DeepCopy() *AKORbacConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AKORbacConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AVIClusterTenant) DeepCopyInto(out *AVIClusterTenant) {
+	*out = *in
+	in.ConfigSettings.DeepCopyInto(&out.ConfigSettings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AVIClusterTenant.
+func (in *AVIClusterTenant) DeepCopy() *AVIClusterTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(AVIClusterTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AVITenant) DeepCopyInto(out *AVITenant) {
+	*out = *in
+	if in.PerCluster != nil {
+		in, out := &in.PerCluster, &out.PerCluster
+		*out = new(AVIClusterTenant)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AVITenant.
+func (in *AVITenant) DeepCopy() *AVITenant {
+	if in == nil {
+		return nil
+	}
+	out := new(AVITenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AVITenantConfigSettings) DeepCopyInto(out *AVITenantConfigSettings) {
+	*out = *in
+	if in.SEInProviderContext != nil {
+		in, out := &in.SEInProviderContext, &out.SEInProviderContext
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TenantAccessToProviderSE != nil {
+		in, out := &in.TenantAccessToProviderSE, &out.TenantAccessToProviderSE
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TenantVRF != nil {
+		in, out := &in.TenantVRF, &out.TenantVRF
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AVITenantConfigSettings.
+func (in *AVITenantConfigSettings) DeepCopy() *AVITenantConfigSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AVITenantConfigSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviPasswordPolicy) DeepCopyInto(out *AviPasswordPolicy) {
+	*out = *in
+	if in.CharacterClasses != nil {
+		in, out := &in.CharacterClasses, &out.CharacterClasses
+		*out = make([]PasswordCharacterClass, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviPasswordPolicy.
+func (in *AviPasswordPolicy) DeepCopy() *AviPasswordPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AviPasswordPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviPermission) DeepCopyInto(out *AviPermission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviPermission.
+func (in *AviPermission) DeepCopy() *AviPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(AviPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AviRoleProfile) DeepCopyInto(out *AviRoleProfile) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]AviPermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedPermissions != nil {
+		in, out := &in.RemovedPermissions, &out.RemovedPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AviRoleProfile.
+func (in *AviRoleProfile) DeepCopy() *AviRoleProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AviRoleProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterIPPool) DeepCopyInto(out *ClusterIPPool) {
+	*out = *in
+	out.IPPool = in.IPPool
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterIPPool.
+func (in *ClusterIPPool) DeepCopy() *ClusterIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceEngineGroup) DeepCopyInto(out *ClusterServiceEngineGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceEngineGroup.
+func (in *ClusterServiceEngineGroup) DeepCopy() *ClusterServiceEngineGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceEngineGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+	*out = *in
+	if in.ValuesHashHistory != nil {
+		in, out := &in.ValuesHashHistory, &out.ValuesHashHistory
+		*out = make([]ValuesHashRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastPasswordRotationTime != nil {
+		in, out := &in.LastPasswordRotationTime, &out.LastPasswordRotationTime
+		*out = (*in).DeepCopy()
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
+func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTenant) DeepCopyInto(out *ClusterTenant) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTenant.
+func (in *ClusterTenant) DeepCopy() *ClusterTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneNetwork) DeepCopyInto(out *ControlPlaneNetwork) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneNetwork.
+func (in *ControlPlaneNetwork) DeepCopy() *ControlPlaneNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataNetwork) DeepCopyInto(out *DataNetwork) {
+	*out = *in
+	if in.IPPools != nil {
+		in, out := &in.IPPools, &out.IPPools
+		*out = make([]IPPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataNetwork.
+func (in *DataNetwork) DeepCopy() *DataNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(DataNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraConfigs) DeepCopyInto(out *ExtraConfigs) {
+	*out = *in
+	if in.ReplicaCount != nil {
+		in, out := &in.ReplicaCount, &out.ReplicaCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.PrimaryInstance != nil {
+		in, out := &in.PrimaryInstance, &out.PrimaryInstance
+		*out = new(bool)
+		**out = **in
+	}
+	out.Log = in.Log
+	if in.ApiServerPort != nil {
+		in, out := &in.ApiServerPort, &out.ApiServerPort
+		*out = new(int)
+		**out = **in
+	}
+	if in.EnableEvents != nil {
+		in, out := &in.EnableEvents, &out.EnableEvents
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableStaticRouteSync != nil {
+		in, out := &in.DisableStaticRouteSync, &out.DisableStaticRouteSync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableEVH != nil {
+		in, out := &in.EnableEVH, &out.EnableEVH
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Layer7Only != nil {
+		in, out := &in.Layer7Only, &out.Layer7Only
+		*out = new(bool)
+		**out = **in
+	}
+	out.NamespaceSelector = in.NamespaceSelector
+	if in.ServicesAPI != nil {
+		in, out := &in.ServicesAPI, &out.ServicesAPI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VIPPerNamespace != nil {
+		in, out := &in.VIPPerNamespace, &out.VIPPerNamespace
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IstioEnabled != nil {
+		in, out := &in.IstioEnabled, &out.IstioEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BlockedNamespaceList != nil {
+		in, out := &in.BlockedNamespaceList, &out.BlockedNamespaceList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UseDefaultSecretsOnly != nil {
+		in, out := &in.UseDefaultSecretsOnly, &out.UseDefaultSecretsOnly
+		*out = new(bool)
+		**out = **in
+	}
+	in.NetworksConfig.DeepCopyInto(&out.NetworksConfig)
+	in.IngressConfigs.DeepCopyInto(&out.IngressConfigs)
+	out.L4Configs = in.L4Configs
+	if in.NodePortSelector != nil {
+		in, out := &in.NodePortSelector, &out.NodePortSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Rbac.DeepCopyInto(&out.Rbac)
+	in.FeatureGates.DeepCopyInto(&out.FeatureGates)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraConfigs.
+func (in *ExtraConfigs) DeepCopy() *ExtraConfigs {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraConfigs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGates) DeepCopyInto(out *FeatureGates) {
+	*out = *in
+	if in.GatewayAPI != nil {
+		in, out := &in.GatewayAPI, &out.GatewayAPI
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGates.
+func (in *FeatureGates) DeepCopy() *FeatureGates {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPool) DeepCopyInto(out *IPPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPool.
+func (in *IPPool) DeepCopy() *IPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSelector) DeepCopyInto(out *NamespaceSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSelector.
+func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkIPPool) DeepCopyInto(out *NetworkIPPool) {
+	*out = *in
+	out.IPPool = in.IPPool
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkIPPool.
+func (in *NetworkIPPool) DeepCopy() *NetworkIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSubnet) DeepCopyInto(out *NetworkSubnet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSubnet.
+func (in *NetworkSubnet) DeepCopy() *NetworkSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworksConfig) DeepCopyInto(out *NetworksConfig) {
+	*out = *in
+	if in.EnableRHI != nil {
+		in, out := &in.EnableRHI, &out.EnableRHI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BGPPeerLabels != nil {
+		in, out := &in.BGPPeerLabels, &out.BGPPeerLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VipNetworkList != nil {
+		in, out := &in.VipNetworkList, &out.VipNetworkList
+		*out = make([]VIPNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworksConfig.
+func (in *NetworksConfig) DeepCopy() *NetworksConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworksConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetwork) DeepCopyInto(out *NodeNetwork) {
+	*out = *in
+	if in.Cidrs != nil {
+		in, out := &in.Cidrs, &out.Cidrs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetwork.
+func (in *NodeNetwork) DeepCopy() *NodeNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRef) DeepCopyInto(out *SecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRef.
+func (in *SecretRef) DeepCopy() *SecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEngineGroupTemplate) DeepCopyInto(out *ServiceEngineGroupTemplate) {
+	*out = *in
+	if in.MaxSEs != nil {
+		in, out := &in.MaxSEs, &out.MaxSEs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxVSPerSE != nil {
+		in, out := &in.MaxVSPerSE, &out.MaxVSPerSE
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BufferSEs != nil {
+		in, out := &in.BufferSEs, &out.BufferSEs
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEngineGroupTemplate.
+func (in *ServiceEngineGroupTemplate) DeepCopy() *ServiceEngineGroupTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEngineGroupTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VIPNetwork) DeepCopyInto(out *VIPNetwork) {
+	*out = *in
+	if in.IPPools != nil {
+		in, out := &in.IPPools, &out.IPPools
+		*out = make([]IPPool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VIPNetwork.
+func (in *VIPNetwork) DeepCopy() *VIPNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(VIPNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VIPPoolStatus) DeepCopyInto(out *VIPPoolStatus) {
+	*out = *in
+	out.IPPool = in.IPPool
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VIPPoolStatus.
+func (in *VIPPoolStatus) DeepCopy() *VIPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VIPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValuesHashRecord) DeepCopyInto(out *ValuesHashRecord) {
+	*out = *in
+	in.ReplacedTime.DeepCopyInto(&out.ReplacedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesHashRecord.
+func (in *ValuesHashRecord) DeepCopy() *ValuesHashRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesHashRecord)
+	in.DeepCopyInto(out)
+	return out
+}
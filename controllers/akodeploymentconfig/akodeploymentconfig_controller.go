@@ -6,6 +6,7 @@ package akodeploymentconfig
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/cluster"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
@@ -20,16 +21,22 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/handlers"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -44,21 +51,44 @@ func (r *AKODeploymentConfigReconciler) SetupWithManager(mgr ctrl.Manager) error
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.secretToAKODeploymentConfig(r.Client, r.Log)),
 		).
+		// failed Avi Controller requests aren't retried in place, the
+		// reconcile is requeued with backoff
+		WithOptions(controller.Options{RateLimiter: aviclient.RequeueRateLimiter()}).
 		Complete(r)
 }
 
 type AKODeploymentConfigReconciler struct {
 	client.Client
-	aviClient         aviclient.Client
-	Log               logr.Logger
-	Scheme            *runtime.Scheme
-	userReconciler    *user.AkoUserReconciler
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// AviClients caches the Avi Controller sessions of every
+	// AKODeploymentConfig
+	AviClients        *aviclient.Registry
 	ClusterReconciler *cluster.ClusterReconciler
 	netprovider.UsableNetworkProvider
+	// VIPPoolSyncPeriod is how often the utilization of the static IP pools
+	// is refreshed from the Avi Controller, 0 only refreshes it when the
+	// AKODeploymentConfig is reconciled
+	VIPPoolSyncPeriod time.Duration
+	// OrphanSweeper deletes the Avi users and Secrets of the clusters which
+	// are gone, nil disables it
+	OrphanSweeper *user.OrphanSweeper
+	// PasswordPolicy is the default policy of the passwords generated for
+	// the Avi users
+	PasswordPolicy utils.PasswordPolicy
+	// ManifestOptions configures the AKO manifests rendered by the operator
+	ManifestOptions ako.ManifestOptions
+	// ManagementClusterID marks the Avi users created by the operator of
+	// this management cluster
+	ManagementClusterID string
 }
 
+// SetAviClient makes every AKODeploymentConfig share the given client
 func (r *AKODeploymentConfigReconciler) SetAviClient(client aviclient.Client) {
-	r.aviClient = client
+	r.AviClients = aviclient.NewRegistry(func(*aviclient.AviClientConfig, string) (aviclient.Client, error) {
+		return client, nil
+	})
 }
 
 // AKODeploymentConfigReconciler reconciles a AKODeploymentConfig object
@@ -67,6 +97,8 @@ func (r *AKODeploymentConfigReconciler) SetAviClient(client aviclient.Client) {
 // +kubebuilder:rbac:groups=networking.tkg.tanzu.vmware.com,resources=akodeploymentconfigs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.tkg.tanzu.vmware.com,resources=akodeploymentconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;create;list;watch;update;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
 // +kubebuilder:rbac:groups=ako.vmware.com,resources=aviinfrasettings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=run.tanzu.vmware.com,resources=clusterbootstraps;clusterbootstraps/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=run.tanzu.vmware.com,resources=tanzukubernetesreleases;tanzukubernetesreleases/status,verbs=get;list;watch
@@ -81,6 +113,12 @@ func (r *AKODeploymentConfigReconciler) Reconcile(ctx context.Context, req ctrl.
 	if err = r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("AKODeploymentConfig not found, will not reconcile")
+			r.AviClients.Release(req.Name)
+			metrics.SelectedClusters.DeleteLabelValues(req.Name)
+			metrics.DeleteVIPPools(req.Name)
+			if r.OrphanSweeper != nil {
+				r.OrphanSweeper.Forget(req.Name)
+			}
 			return res, nil
 		}
 		return res, err
@@ -93,7 +131,7 @@ func (r *AKODeploymentConfigReconciler) Reconcile(ctx context.Context, req ctrl.
 			obj.GroupVersionKind(), req.NamespacedName)
 	}
 	defer func() {
-		if err := patchHelper.Patch(ctx, obj); err != nil {
+		if err := patchAKODeploymentConfig(ctx, patchHelper, obj); err != nil {
 			if reterr == nil {
 				reterr = err
 			}
@@ -114,12 +152,26 @@ func (r *AKODeploymentConfigReconciler) Reconcile(ctx context.Context, req ctrl.
 	// Handle non-deleted resources.
 	res, err = r.reconcileNormal(ctx, log, obj)
 	if err != nil {
+		if aviclient.IsRetriableError(err) {
+			log.Info("Avi Controller is unavailable, requeue with backoff", "error", err.Error())
+			return res, err
+		}
 		log.Error(err, "failed to reconcile AKODeploymentConfig")
 		return res, err
 	}
 	return res, nil
 }
 
+// patchAKODeploymentConfig summarizes the conditions set by the phases into
+// the Ready condition and patches the AKODeploymentConfig
+func patchAKODeploymentConfig(ctx context.Context, patchHelper *patch.Helper, obj *akoov1alpha1.AKODeploymentConfig) error {
+	conditions.SetSummary(obj, conditions.WithConditions(akoov1alpha1.AKODeploymentConfigConditions...))
+	return patchHelper.Patch(ctx, obj, patch.WithOwnedConditions{
+		Conditions: append([]clusterv1.ConditionType{clusterv1.ReadyCondition, akoov1alpha1.VIPPoolLowCondition},
+			akoov1alpha1.AKODeploymentConfigConditions...),
+	})
+}
+
 func (r *AKODeploymentConfigReconciler) reconcileNormal(
 	ctx context.Context,
 	log logr.Logger,
@@ -132,8 +184,13 @@ func (r *AKODeploymentConfigReconciler) reconcileNormal(
 		// patcher helper update the object, and then proceed on the next reconciliation.
 		ctrlutil.AddFinalizer(obj, akoov1alpha1.AkoDeploymentConfigFinalizer)
 	}
-	return phases.ReconcilePhases(ctx, log, obj,
-		[]phases.ReconcilePhase{r.reconcileAVI, r.reconcileClusters})
+	return phases.ReconcilePhases(ctx, log, obj, []phases.ReconcilePhase{
+		phases.Named("reconcileAVI", r.reconcileAVI),
+		phases.Named("reconcileClusters", r.reconcileClusters),
+		phases.Named("reconcileClusterIPPools", r.reconcileClusterIPPools),
+		phases.Named("reconcileClusterServiceEngineGroups", r.reconcileClusterServiceEngineGroups),
+		phases.Named("reconcileClusterTenants", r.reconcileClusterTenants),
+	})
 }
 
 func (r *AKODeploymentConfigReconciler) reconcileDelete(
@@ -153,10 +210,13 @@ func (r *AKODeploymentConfigReconciler) reconcileDelete(
 			// remove finalizer when clean up finishes successfully
 			log.Info("Removing finalizer", "finalizer", akoov1alpha1.AkoDeploymentConfigFinalizer)
 			ctrlutil.RemoveFinalizer(obj, akoov1alpha1.AkoDeploymentConfigFinalizer)
+			r.AviClients.Release(obj.Name)
 		}
 	}()
-	return phases.ReconcilePhases(ctx, log, obj,
-		[]phases.ReconcilePhase{r.reconcileClustersDelete, r.reconcileAVIDelete})
+	return phases.ReconcilePhases(ctx, log, obj, []phases.ReconcilePhase{
+		phases.Named("reconcileClustersDelete", r.reconcileClustersDelete),
+		phases.Named("reconcileAVIDelete", r.reconcileAVIDelete),
+	})
 }
 
 func (r *AKODeploymentConfigReconciler) secretToAKODeploymentConfig(c client.Client, log logr.Logger) handler.MapFunc {
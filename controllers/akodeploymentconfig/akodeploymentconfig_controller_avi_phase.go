@@ -7,12 +7,15 @@ import (
 	"bytes"
 	"context"
 	"errors"
-	"sync"
+	"fmt"
 
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 
 	"net"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/vmware/alb-sdk/go/models"
@@ -20,7 +23,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,86 +42,65 @@ import (
 	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
 )
 
-var lock = &sync.Mutex{}
-
-func getAviCAFromADC(c client.Client, ctx context.Context,
-	log logr.Logger, obj *akoov1alpha1.AKODeploymentConfig) (string, error) {
-	aviControllerCA := &corev1.Secret{}
-	if err := c.Get(ctx, client.ObjectKey{
-		Name:      obj.Spec.CertificateAuthorityRef.Name,
-		Namespace: obj.Spec.CertificateAuthorityRef.Namespace,
-	}, aviControllerCA); err != nil {
-		if apierrors.IsNotFound(err) {
-			log.Info("Cannot find referenced secret " + obj.Spec.CertificateAuthorityRef.Name + "/" + obj.Spec.CertificateAuthorityRef.Namespace + " requeue the request")
-		} else {
-			log.Error(err, "Failed to find referenced "+obj.Spec.CertificateAuthorityRef.Name+"/"+obj.Spec.CertificateAuthorityRef.Namespace+" Secret")
-		}
-		return "", err
-	}
-
-	return string(aviControllerCA.Data["certificateAuthorityData"][:]), nil
-}
-
 func (r *AKODeploymentConfigReconciler) initAVI(
 	ctx context.Context,
 	log logr.Logger,
 	obj *akoov1alpha1.AKODeploymentConfig,
-) (ctrl.Result, error) {
-	res := ctrl.Result{}
-
-	var currentCa, newCa string
-
-	if r.aviClient != nil {
-		currentCa, _ = r.aviClient.AviCertificateConfig()
-	}
-	newCa, err := getAviCAFromADC(r.Client, ctx, log, obj)
+) (aviclient.Client, error) {
+	config, err := aviclient.NewAviClientConfigFromSecrets(r.Client, ctx, log, obj.Spec.Controller,
+		obj.Spec.AdminCredentialRef.Name, obj.Spec.AdminCredentialRef.Namespace,
+		obj.Spec.CertificateAuthorityRef.Name, obj.Spec.CertificateAuthorityRef.Namespace)
 	if err != nil {
-		return res, err
+		log.Error(err, "Cannot init AVI clients from secrets")
+		conditions.MarkFalse(obj, akoov1alpha1.AviCredentialsValidCondition, akoov1alpha1.AviCredentialsNotFoundReason,
+			clusterv1.ConditionSeverityError, "Failed to read the Avi Controller credentials: %v", err)
+		return nil, err
 	}
-	reInit := currentCa != newCa
-
-	lock.Lock()
-	// Lazily initialize aviClient so we don't skip other reconciliations
-	if r.aviClient == nil || reInit {
-		var err error
-		r.aviClient, err = aviclient.NewAviClientFromSecrets(r.Client, ctx, log, obj.Spec.Controller,
-			obj.Spec.AdminCredentialRef.Name, obj.Spec.AdminCredentialRef.Namespace,
-			obj.Spec.CertificateAuthorityRef.Name, obj.Spec.CertificateAuthorityRef.Namespace,
-			obj.Spec.ControllerVersion)
-
-		if err != nil {
-			log.Error(err, "Cannot init AVI clients from secrets")
-			return res, err
-		}
-
-		version, err := r.aviClient.GetControllerVersion()
-		if err != nil {
-			return ctrl.Result{}, err
-		}
 
-		if obj.Spec.ControllerVersion != version {
-			// re-init aviClient with real version
-			r.aviClient, err = aviclient.NewAviClientFromSecrets(r.Client, ctx, log, obj.Spec.Controller,
-				obj.Spec.AdminCredentialRef.Name, obj.Spec.AdminCredentialRef.Namespace,
-				obj.Spec.CertificateAuthorityRef.Name, obj.Spec.CertificateAuthorityRef.Namespace,
-				version)
-
-			if err != nil {
-				log.Error(err, "Cannot init AVI clients with actual avi controller version")
-				return res, err
-			}
-		}
+	// reuse the session shared with other AKODeploymentConfigs talking to
+	// the same controller, whatever version it was created with
+	key := obj.AviClientKey()
+	aviClient, err := r.AviClients.Acquire(obj.Name, key, config, "")
+	if err != nil {
+		log.Error(err, "Failed to initialize AVI Controller Client, requeue the request")
+		markAviSessionFailed(obj, err)
+		return nil, err
+	}
 
-		log.Info("AVI Client initialized successfully")
+	version, err := aviClient.GetControllerVersion()
+	if err != nil {
+		markAviSessionFailed(obj, err)
+		conditions.MarkFalse(obj, akoov1alpha1.ControllerVersionDetectedCondition, akoov1alpha1.ControllerVersionUnknownReason,
+			clusterv1.ConditionSeverityWarning, "Failed to get the Avi Controller version: %v", err)
+		return nil, err
 	}
-	lock.Unlock()
 
-	if r.userReconciler == nil || reInit {
-		r.userReconciler = user.NewProvider(r.Client, r.aviClient, r.Log, r.Scheme)
-		log.Info("Ako User Reconciler initialized")
+	// re-init aviClient with real version when the cached one differs
+	aviClient, err = r.AviClients.Acquire(obj.Name, key, config, version)
+	if err != nil {
+		log.Error(err, "Cannot init AVI clients with actual avi controller version")
+		markAviSessionFailed(obj, err)
+		return nil, err
 	}
+	conditions.MarkTrue(obj, akoov1alpha1.AviControllerReachableCondition)
+	conditions.MarkTrue(obj, akoov1alpha1.AviCredentialsValidCondition)
+	return aviClient, nil
+}
 
-	return res, nil
+// markAviSessionFailed sets the reachability and credentials conditions
+// from the error returned when opening an Avi Controller session
+func markAviSessionFailed(obj *akoov1alpha1.AKODeploymentConfig, err error) {
+	if aviclient.IsAviAuthenticationError(err) {
+		// the controller answered, it rejected the credentials
+		conditions.MarkTrue(obj, akoov1alpha1.AviControllerReachableCondition)
+		conditions.MarkFalse(obj, akoov1alpha1.AviCredentialsValidCondition, akoov1alpha1.AviAuthenticationFailedReason,
+			clusterv1.ConditionSeverityError, "The Avi Controller rejected the admin credentials: %v", err)
+		return
+	}
+	conditions.MarkFalse(obj, akoov1alpha1.AviControllerReachableCondition, akoov1alpha1.AviControllerUnreachableReason,
+		clusterv1.ConditionSeverityError, "Failed to connect to the Avi Controller %s: %v", obj.Spec.Controller, err)
+	conditions.MarkUnknown(obj, akoov1alpha1.AviCredentialsValidCondition, akoov1alpha1.AviCredentialsNotVerifiedReason,
+		"The Avi Controller is unreachable")
 }
 
 // reconcileAVI reconciles every cluster that matches the
@@ -125,29 +111,68 @@ func (r *AKODeploymentConfigReconciler) reconcileAVI(
 	log logr.Logger,
 	obj *akoov1alpha1.AKODeploymentConfig,
 ) (ctrl.Result, error) {
-	if res, err := r.initAVI(ctx, log, obj); err != nil {
+	aviClient, err := r.initAVI(ctx, log, obj)
+	if err != nil {
 		log.Error(err, "Failed to initialize avi related clients")
-		return res, err
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.AviClientInitFailedReason,
+			"Failed to connect to Avi Controller %s: %v", obj.Spec.Controller, err)
+		return ctrl.Result{}, err
 	}
+	userReconciler := user.NewProvider(r.Client, aviClient, r.Log, r.Scheme, r.Recorder)
+	userReconciler.PasswordPolicy = r.PasswordPolicy
+	userReconciler.ManagementClusterID = r.ManagementClusterID
 
 	return phases.ReconcilePhases(ctx, log, obj, []phases.ReconcilePhase{
-		r.reconcileNetworkSubnets,
-		r.reconcileCloudUsableNetwork,
-		r.reconcileAviInfraSetting,
-		r.reconcileControllerVersion,
-		func(ctx context.Context, log logr.Logger, obj *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
-			return phases.ReconcileClustersPhases(ctx, r.Client, log, obj,
+		phases.Named("reconcileNetworkSubnets", r.reconcileNetworkSubnets),
+		phases.Named("reconcileCloudUsableNetwork", r.reconcileCloudUsableNetwork),
+		phases.Named("reconcileServiceEngineGroup", r.reconcileServiceEngineGroup),
+		phases.Named("reconcileAviInfraSetting", r.reconcileAviInfraSetting),
+		phases.Named("reconcileControllerVersion", r.reconcileControllerVersion),
+		phases.Named("reconcileVIPPools", r.reconcileVIPPools),
+		phases.Named("reconcileClusterUsers", func(ctx context.Context, log logr.Logger, obj *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+			res, err := phases.ReconcileClustersPhases(ctx, r.Client, log, obj,
 				[]phases.ReconcileClusterPhase{
-					r.userReconciler.ReconcileAviUser,
+					phases.NamedCluster("reconcileClusterTenant", r.reconcileClusterTenant),
+					phases.NamedCluster("ReconcileAviUser", userReconciler.ReconcileAviUser),
 				},
 				[]phases.ReconcileClusterPhase{
-					r.userReconciler.ReconcileAviUserDelete,
+					phases.NamedCluster("ReconcileAviUserDelete", userReconciler.ReconcileAviUserDelete),
+					phases.NamedCluster("reconcileClusterTenantDelete", r.reconcileClusterTenantDelete),
 				},
 			)
-		},
+			// the rotations are scheduled here rather than by the user
+			// phase, a requeue from a cluster phase marks the cluster
+			// as deploying
+			return util.LowestNonZeroResult(res, ctrl.Result{RequeueAfter: user.PasswordRotationRequeueAfter(obj, time.Now())}), err
+		}),
+		phases.Named("reconcileOrphanedAviUsers", r.reconcileOrphanedAviUsers),
 	})
 }
 
+// reconcileOrphanedAviUsers sweeps the Avi users and the Secrets left behind by
+// the clusters deleted without cleanup. A failed sweep doesn't fail the
+// reconciliation, it's retried at the next sweep.
+// It's a reconcilePhase function
+func (r *AKODeploymentConfigReconciler) reconcileOrphanedAviUsers(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	if r.OrphanSweeper == nil {
+		return ctrl.Result{}, nil
+	}
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return ctrl.Result{}, errors.New("AVI client not initialized")
+	}
+	res, err := r.OrphanSweeper.Sweep(ctx, log.WithName("orphan-sweeper"), aviClient, obj)
+	if err != nil {
+		log.Error(err, "Failed to sweep the orphaned Avi users")
+	}
+	return res, nil
+}
+
 // reconcileAVIDelete reconciles every cluster that matches the
 // AKODeploymentConfig's selector by conducting AVI related operations
 // It's a reconcilePhase function
@@ -156,25 +181,30 @@ func (r *AKODeploymentConfigReconciler) reconcileAVIDelete(
 	log logr.Logger,
 	obj *akoov1alpha1.AKODeploymentConfig,
 ) (ctrl.Result, error) {
-	if res, err := r.initAVI(ctx, log, obj); err != nil {
+	aviClient, err := r.initAVI(ctx, log, obj)
+	if err != nil {
 		log.Error(err, "Failed to initialize avi related clients")
-		return res, err
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.AviClientInitFailedReason,
+			"Failed to connect to Avi Controller %s: %v", obj.Spec.Controller, err)
+		return ctrl.Result{}, err
 	}
+	userReconciler := user.NewProvider(r.Client, aviClient, r.Log, r.Scheme, r.Recorder)
 
 	return phases.ReconcilePhases(ctx, log, obj, []phases.ReconcilePhase{
-		r.reconcileAviInfraSettingDelete,
-		func(ctx context.Context, log logr.Logger, obj *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+		phases.Named("reconcileAviInfraSettingDelete", r.reconcileAviInfraSettingDelete),
+		phases.Named("reconcileNetworkSubnetsDelete", r.reconcileNetworkSubnetsDelete),
+		phases.Named("reconcileCloudUsableNetworkDelete", r.reconcileCloudUsableNetworkDelete),
+		phases.Named("reconcileClusterUsersDelete", func(ctx context.Context, log logr.Logger, obj *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
 			return phases.ReconcileClustersPhases(ctx, r.Client, log, obj,
 				[]phases.ReconcileClusterPhase{
-					r.userReconciler.ReconcileAviUserDelete,
+					phases.NamedCluster("ReconcileAviUserDelete", userReconciler.ReconcileAviUserDelete),
 				},
 				[]phases.ReconcileClusterPhase{
-					// TODO(fangyuanl): handle the data network configuration
-					// deletion
-					r.userReconciler.ReconcileAviUserDelete,
+					phases.NamedCluster("ReconcileAviUserDelete", userReconciler.ReconcileAviUserDelete),
+					phases.NamedCluster("reconcileClusterTenantDelete", r.reconcileClusterTenantDelete),
 				},
 			)
-		},
+		}),
 	})
 
 }
@@ -189,8 +219,15 @@ func (r *AKODeploymentConfigReconciler) reconcileControllerVersion(
 	log = log.WithValues("controllerVersion", obj.Spec.ControllerVersion)
 	log.Info("Start reconciling AVI controller version")
 
-	version, err := r.aviClient.GetControllerVersion()
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return ctrl.Result{}, errors.New("AVI client not initialized")
+	}
+	version, err := aviClient.GetControllerVersion()
 	if err != nil {
+		conditions.MarkFalse(obj, akoov1alpha1.ControllerVersionDetectedCondition, akoov1alpha1.ControllerVersionUnknownReason,
+			clusterv1.ConditionSeverityWarning, "Failed to get the Avi Controller version: %v", err)
 		return ctrl.Result{}, err
 	}
 
@@ -198,12 +235,13 @@ func (r *AKODeploymentConfigReconciler) reconcileControllerVersion(
 	if obj.Spec.ControllerVersion != version {
 		obj.Spec.ControllerVersion = version
 	}
+	conditions.MarkTrue(obj, akoov1alpha1.ControllerVersionDetectedCondition)
 
 	return ctrl.Result{}, nil
 }
 
-// reconcileNetworkSubnets ensures the Datanetwork configuration is in sync with
-// AVI Controller configuration
+// reconcileNetworkSubnets ensures the Datanetwork and VIP networks
+// configuration is in sync with AVI Controller configuration
 func (r *AKODeploymentConfigReconciler) reconcileNetworkSubnets(
 	ctx context.Context,
 	log logr.Logger,
@@ -213,49 +251,615 @@ func (r *AKODeploymentConfigReconciler) reconcileNetworkSubnets(
 
 	log.Info("Start reconciling AVI Network Subnets")
 
-	if r.aviClient == nil {
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
 		log.Info("AVI client not initialized, requeue")
 		return res, errors.New("AVI client not initialized")
 	}
+	siblings, err := r.siblingAKODeploymentConfigs(ctx, obj)
+	if err != nil {
+		log.Error(err, "Failed to list AKODeploymentConfigs")
+		return res, err
+	}
+
+	// every network is synced even if a previous one failed
+	synced := true
+	var errs []error
+	for _, vipNetwork := range aviNetworks(obj) {
+		ok, err := r.reconcileNetworkSubnet(log.WithValues("network", vipNetwork.NetworkName), aviClient, obj, vipNetwork, siblings)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		synced = synced && ok
+	}
+	// networks removed from the spec only have their static ranges pruned,
+	// they may have been deleted from the Avi Controller in the meantime so
+	// failures don't block the reconciliation
+	for _, networkName := range removedStaticRangeNetworks(obj) {
+		vipNetwork := akoov1alpha1.VIPNetwork{NetworkName: networkName}
+		if _, err := r.reconcileNetworkSubnet(log.WithValues("network", networkName), aviClient, obj, vipNetwork, siblings); err != nil {
+			log.Error(err, "Failed to prune the static ranges of a removed network", "network", networkName)
+		}
+	}
+	if len(errs) != 0 {
+		return res, kerrors.NewAggregate(errs)
+	}
+	// reconcileCloudUsableNetwork may still flag the networks as not usable
+	if synced {
+		conditions.MarkTrue(obj, akoov1alpha1.DataNetworkSyncedCondition)
+	}
+	return res, nil
+}
 
-	network, err := r.aviClient.NetworkGetByName(obj.Spec.DataNetwork.Name, obj.Spec.CloudName)
+// reconcileNetworkSubnet ensures the Avi network has one subnet per CIDR of
+// vipNetwork, with the IP pools of the same type as static ranges. The
+// subnets and static ranges added by the operator are recorded in the status,
+// static ranges are removed once they leave the spec unless another
+// AKODeploymentConfig uses them or some IPs are still allocated from them. It
+// returns false when the network can't be synced.
+func (r *AKODeploymentConfigReconciler) reconcileNetworkSubnet(
+	log logr.Logger,
+	aviClient aviclient.Client,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	vipNetwork akoov1alpha1.VIPNetwork,
+	siblings []akoov1alpha1.AKODeploymentConfig,
+) (bool, error) {
+	network, err := aviClient.NetworkGetByName(vipNetwork.NetworkName, obj.Spec.CloudName)
 	if err != nil {
-		log.Info("[WARN] Failed to get the Data Network from AVI Controller")
-		return res, nil
+		log.Error(err, "Failed to get the Network from AVI Controller")
+		conditions.MarkFalse(obj, akoov1alpha1.DataNetworkSyncedCondition, akoov1alpha1.NetworkNotFoundReason,
+			clusterv1.ConditionSeverityError, "Failed to get network %s in cloud %s: %v", vipNetwork.NetworkName, obj.Spec.CloudName, err)
+		return false, err
 	}
 
+	// the VIP sub-pools dedicated to clusters are carved out of the IP pools
+	// into subnets of their own, which the AKO of the clusters is scoped to
+	subPools := clusterIPPoolsOf(append([]akoov1alpha1.AKODeploymentConfig{*obj}, siblings...), vipNetwork.NetworkName)
+	ipPools := CarvedIPPools(vipNetwork.IPPools, subPools)
+	cidrs := networkCIDRs(vipNetwork)
+	for _, subPool := range commonPools(subPools, ipPools) {
+		cidrs = append(cidrs, subPool.CIDR())
+	}
+
+	// static ranges added for another AKODeploymentConfig are shared rather
+	// than created by hand
+	owned := ownedStaticRanges(obj, vipNetwork.NetworkName)
+	owned = append(owned, stalePools(commonPools(siblingStaticRanges(siblings, vipNetwork.NetworkName), ipPools), owned)...)
+	stale := stalePools(owned, ipPools)
+	kept := commonPools(stale, siblingIPPools(siblings, vipNetwork.NetworkName, subPools))
+	inUse, err := staticRangesInUse(aviClient, network, stalePools(stale, kept), ipPools)
+	if err != nil {
+		log.Error(err, "Failed to get the Network runtime from AVI Controller")
+		conditions.MarkFalse(obj, akoov1alpha1.DataNetworkSyncedCondition, akoov1alpha1.NetworkRuntimeUnavailableReason,
+			clusterv1.ConditionSeverityError, "Failed to get the allocated IPs of network %s: %v", vipNetwork.NetworkName, err)
+		return false, err
+	}
+	// ranges still used by another AKODeploymentConfig or with allocated IPs
+	// are left alone as if they were created by hand, they stay owned and
+	// are removed once released
+	kept = append(kept, inUse...)
+	prunable := stalePools(owned, kept)
+
 	// TODO(fangyuanl): move validation to webhook
 	// We also need to make sure IPPools are not overlapping
-	addr, cidr, err := net.ParseCIDR(obj.Spec.DataNetwork.CIDR)
+	added := missingSubnets(network, cidrs)
+	modified, stillOwned, err := EnsureAviNetworkSubnets(network, cidrs, ipPools, prunable, log)
 	if err != nil {
-		log.Error(err, "Failed to parse the Data Network CIDR")
-		return res, nil
+		log.Error(err, "Failed to parse the Network CIDR")
+		// retrying won't help until the spec changes
+		conditions.MarkFalse(obj, akoov1alpha1.DataNetworkSyncedCondition, akoov1alpha1.NetworkCIDRInvalidReason,
+			clusterv1.ConditionSeverityError, "Invalid network %s CIDR: %v", vipNetwork.NetworkName, err)
+		return false, nil
 	}
-	ones, _ := cidr.Mask.Size()
-	mask := int32(ones)
 
-	addrType := "V4"
-	if addr.To4() == nil {
-		addrType = "V6"
+	stillOwned = append(stillOwned, kept...)
+	// subnets added for another AKODeploymentConfig are shared as well
+	ownedSubnets := ownedSubnetCIDRs(obj, vipNetwork.NetworkName)
+	for _, cidr := range append(added, siblingSubnetCIDRs(siblings, vipNetwork.NetworkName)...) {
+		if containsCIDR(cidrs, cidr) && !containsCIDR(ownedSubnets, cidr) {
+			ownedSubnets = append(ownedSubnets, cidr)
+		}
+	}
+	// the subnets of the released sub-pools are removed once emptied
+	var keptSubnets []string
+	for _, cidr := range ownedSubnets {
+		if containsCIDR(cidrs, cidr) || !isNestedSubnet(cidr, networkCIDRs(vipNetwork)) {
+			keptSubnets = append(keptSubnets, cidr)
+			continue
+		}
+		if removeEmptySubnet(network, cidr) {
+			modified = true
+		} else if len(missingSubnets(network, []string{cidr})) == 0 {
+			keptSubnets = append(keptSubnets, cidr)
+		}
+	}
+	ownedSubnets = keptSubnets
+	if !modified {
+		log.Info("No change detected for Network")
+		setOwnedStaticRanges(obj, vipNetwork.NetworkName, stillOwned)
+		setOwnedSubnets(obj, vipNetwork.NetworkName, ownedSubnets)
+		return markStaticRangesInUse(r, obj, vipNetwork.NetworkName, inUse), nil
+	}
+	log.V(3).Info("Change detected, updating Network")
+	if _, err := aviClient.NetworkUpdate(network); err != nil {
+		log.Error(err, "Failed to update Network, requeue the request")
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.NetworkSubnetUpdateFailedReason,
+			"Failed to update the subnets of Avi network %s: %v", vipNetwork.NetworkName, err)
+		conditions.MarkFalse(obj, akoov1alpha1.DataNetworkSyncedCondition, akoov1alpha1.NetworkUpdateFailedReason,
+			clusterv1.ConditionSeverityError, "Failed to update the subnets of network %s: %v", vipNetwork.NetworkName, err)
+		return false, err
 	}
+	log.Info("Successfully updated Network", "subnets", network.ConfiguredSubnets)
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.NetworkSubnetUpdatedReason,
+		"Updated the subnets of Avi network %s with CIDR %s", vipNetwork.NetworkName, strings.Join(cidrs, ","))
+	setOwnedStaticRanges(obj, vipNetwork.NetworkName, stillOwned)
+	setOwnedSubnets(obj, vipNetwork.NetworkName, ownedSubnets)
+	return markStaticRangesInUse(r, obj, vipNetwork.NetworkName, inUse), nil
+}
 
-	modified := EnsureAviNetwork(network, addrType, cidr, mask, obj.Spec.DataNetwork.IPPools, log)
+// reconcileNetworkSubnetsDelete removes the subnets and static ranges the
+// operator added to the Avi networks, unless another AKODeploymentConfig
+// still uses them
+func (r *AKODeploymentConfigReconciler) reconcileNetworkSubnetsDelete(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	log.Info("Start cleaning up AVI Network Subnets")
+
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+	siblings, err := r.siblingAKODeploymentConfigs(ctx, obj)
+	if err != nil {
+		log.Error(err, "Failed to list AKODeploymentConfigs")
+		return res, err
+	}
+
+	var errs []error
+	for _, networkName := range ownedNetworks(obj) {
+		if err := r.reconcileNetworkSubnetDelete(log.WithValues("network", networkName), aviClient, obj, networkName, siblings); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return res, kerrors.NewAggregate(errs)
+}
+
+// reconcileNetworkSubnetDelete removes the owned static ranges and subnets of
+// the network which no other AKODeploymentConfig uses. Static ranges with
+// allocated IPs and subnets still holding static ranges are left in place.
+func (r *AKODeploymentConfigReconciler) reconcileNetworkSubnetDelete(
+	log logr.Logger,
+	aviClient aviclient.Client,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	networkName string,
+	siblings []akoov1alpha1.AKODeploymentConfig,
+) error {
+	network, err := aviClient.NetworkGetByName(networkName, obj.Spec.CloudName)
+	if err != nil {
+		if aviclient.IsAviObjectNonExistentError(err) {
+			log.Info("Network doesn't exist anymore, skip cleaning it up")
+			setOwnedStaticRanges(obj, networkName, nil)
+			setOwnedSubnets(obj, networkName, nil)
+			return nil
+		}
+		log.Error(err, "Failed to get the Network from AVI Controller")
+		return err
+	}
+
+	owned := stalePools(ownedStaticRanges(obj, networkName), siblingIPPools(siblings, networkName, clusterIPPoolsOf(siblings, networkName)))
+	inUse, err := staticRangesInUse(aviClient, network, owned, nil)
+	if err != nil {
+		log.Error(err, "Failed to get the Network runtime from AVI Controller")
+		return err
+	}
+	if len(inUse) != 0 {
+		var ranges []string
+		for _, ipPool := range inUse {
+			ranges = append(ranges, ipPool.Start+"-"+ipPool.End)
+		}
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.StaticIPRangeInUseReason,
+			"Leaving static ranges %s of Avi network %s in place as they still have allocated IPs", strings.Join(ranges, ","), networkName)
+	}
+	modified, _, _ := EnsureAviNetworkSubnets(network, nil, nil, stalePools(owned, inUse), log)
+
+	var usedSubnets []string
+	for _, vipNetwork := range usedNetworks(siblings) {
+		if vipNetwork.NetworkName == networkName {
+			usedSubnets = append(usedSubnets, normalizedCIDRs(networkCIDRs(vipNetwork))...)
+		}
+	}
+	for _, cidr := range ownedSubnetCIDRs(obj, networkName) {
+		if containsCIDR(usedSubnets, cidr) {
+			log.Info("Subnet is still used by another AKODeploymentConfig", "subnet", cidr)
+			continue
+		}
+		if removeEmptySubnet(network, cidr) {
+			modified = true
+		}
+	}
 
 	if modified {
-		log.V(3).Info("Change detected, updating Network", "network", obj.Spec.DataNetwork.Name)
-		_, err := r.aviClient.NetworkUpdate(network)
+		if _, err := aviClient.NetworkUpdate(network); err != nil {
+			log.Error(err, "Failed to update Network, requeue the request")
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.NetworkSubnetUpdateFailedReason,
+				"Failed to clean up the subnets of Avi network %s: %v", networkName, err)
+			return err
+		}
+		log.Info("Successfully cleaned up Network", "subnets", network.ConfiguredSubnets)
+		r.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.NetworkSubnetUpdatedReason,
+			"Removed the subnets and static ranges added to Avi network %s", networkName)
+	}
+	setOwnedStaticRanges(obj, networkName, nil)
+	setOwnedSubnets(obj, networkName, nil)
+	return nil
+}
+
+// removeEmptySubnet removes the subnet of network with cidr if it has no
+// static range left. It returns true when the subnet was removed.
+func removeEmptySubnet(network *models.Network, cidr string) bool {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	ones, _ := ipNet.Mask.Size()
+	index, found := AviNetworkContainsSubnet(network, ipNet.IP.String(), int32(ones))
+	if !found || len(network.ConfiguredSubnets[index].StaticIPRanges) != 0 {
+		return false
+	}
+	network.ConfiguredSubnets = append(network.ConfiguredSubnets[:index], network.ConfiguredSubnets[index+1:]...)
+	return true
+}
+
+// markStaticRangesInUse reports the stale static ranges of the network which
+// can't be removed yet. It returns false when there's any.
+func markStaticRangesInUse(r *AKODeploymentConfigReconciler, obj *akoov1alpha1.AKODeploymentConfig, networkName string, inUse []akoov1alpha1.IPPool) bool {
+	if len(inUse) == 0 {
+		return true
+	}
+	var ranges []string
+	for _, ipPool := range inUse {
+		ranges = append(ranges, ipPool.Start+"-"+ipPool.End)
+	}
+	r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.StaticIPRangeInUseReason,
+		"Static ranges %s of Avi network %s are no longer in the spec but still have allocated IPs", strings.Join(ranges, ","), networkName)
+	conditions.MarkFalse(obj, akoov1alpha1.DataNetworkSyncedCondition, akoov1alpha1.StaticIPRangeInUseConditionReason,
+		clusterv1.ConditionSeverityWarning, "Static ranges %s of network %s still have allocated IPs", strings.Join(ranges, ","), networkName)
+	return false
+}
+
+// ownedStaticRanges returns the static ranges the operator added to the network
+func ownedStaticRanges(obj *akoov1alpha1.AKODeploymentConfig, networkName string) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, networkIPPool := range obj.Status.StaticIPRanges {
+		if networkIPPool.NetworkName == networkName {
+			res = append(res, networkIPPool.IPPool)
+		}
+	}
+	return res
+}
+
+// setOwnedStaticRanges records ipPools as the static ranges the operator added
+// to the network
+func setOwnedStaticRanges(obj *akoov1alpha1.AKODeploymentConfig, networkName string, ipPools []akoov1alpha1.IPPool) {
+	var res []akoov1alpha1.NetworkIPPool
+	for _, networkIPPool := range obj.Status.StaticIPRanges {
+		if networkIPPool.NetworkName != networkName {
+			res = append(res, networkIPPool)
+		}
+	}
+	for _, ipPool := range ipPools {
+		res = append(res, akoov1alpha1.NetworkIPPool{NetworkName: networkName, IPPool: ipPool})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].NetworkName < res[j].NetworkName
+	})
+	obj.Status.StaticIPRanges = res
+}
+
+// removedStaticRangeNetworks returns the networks which still have owned
+// static ranges but aren't part of the spec anymore
+func removedStaticRangeNetworks(obj *akoov1alpha1.AKODeploymentConfig) []string {
+	inSpec := map[string]bool{}
+	for _, vipNetwork := range aviNetworks(obj) {
+		inSpec[vipNetwork.NetworkName] = true
+	}
+	var res []string
+	for _, networkIPPool := range obj.Status.StaticIPRanges {
+		if !inSpec[networkIPPool.NetworkName] {
+			inSpec[networkIPPool.NetworkName] = true
+			res = append(res, networkIPPool.NetworkName)
+		}
+	}
+	return res
+}
+
+// ownedSubnetCIDRs returns the CIDRs of the subnets the operator added to the
+// network
+func ownedSubnetCIDRs(obj *akoov1alpha1.AKODeploymentConfig, networkName string) []string {
+	var res []string
+	for _, subnet := range obj.Status.Subnets {
+		if subnet.NetworkName == networkName {
+			res = append(res, subnet.CIDR)
+		}
+	}
+	return res
+}
+
+// setOwnedSubnets records cidrs as the subnets the operator added to the
+// network
+func setOwnedSubnets(obj *akoov1alpha1.AKODeploymentConfig, networkName string, cidrs []string) {
+	var res []akoov1alpha1.NetworkSubnet
+	for _, subnet := range obj.Status.Subnets {
+		if subnet.NetworkName != networkName {
+			res = append(res, subnet)
+		}
+	}
+	for _, cidr := range cidrs {
+		res = append(res, akoov1alpha1.NetworkSubnet{NetworkName: networkName, CIDR: cidr})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].NetworkName < res[j].NetworkName
+	})
+	obj.Status.Subnets = res
+}
+
+// ownedNetworks returns the networks holding subnets or static ranges the
+// operator added
+func ownedNetworks(obj *akoov1alpha1.AKODeploymentConfig) []string {
+	var res []string
+	for _, networkIPPool := range obj.Status.StaticIPRanges {
+		if !slices.Contains(res, networkIPPool.NetworkName) {
+			res = append(res, networkIPPool.NetworkName)
+		}
+	}
+	for _, subnet := range obj.Status.Subnets {
+		if !slices.Contains(res, subnet.NetworkName) {
+			res = append(res, subnet.NetworkName)
+		}
+	}
+	return res
+}
+
+// missingSubnets returns the cidrs which have no subnet in the network yet
+func missingSubnets(network *models.Network, cidrs []string) []string {
+	var res []string
+	for _, cidr := range normalizedCIDRs(cidrs) {
+		_, ipNet, err := net.ParseCIDR(cidr)
 		if err != nil {
-			log.Error(err, "Failed to update Network, requeue the request", "network", network)
-			return res, err
+			continue
+		}
+		ones, _ := ipNet.Mask.Size()
+		if _, found := AviNetworkContainsSubnet(network, ipNet.IP.String(), int32(ones)); !found {
+			res = append(res, cidr)
 		}
-		log.Info("Successfully updated Network", "subnets", network.ConfiguredSubnets)
-	} else {
-		log.Info("No change detected for Network", "network", obj.Spec.DataNetwork.Name)
 	}
+	return res
+}
 
+// normalizedCIDRs returns the cidrs in their canonical form, e.g. 10.0.0.0/24
+// for 10.0.0.1/24
+func normalizedCIDRs(cidrs []string) []string {
+	var res []string
+	for _, cidr := range cidrs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			cidr = ipNet.String()
+		}
+		res = append(res, cidr)
+	}
+	return res
+}
+
+func containsCIDR(cidrs []string, cidr string) bool {
+	return slices.Contains(normalizedCIDRs(cidrs), normalizedCIDRs([]string{cidr})[0])
+}
+
+// siblingAKODeploymentConfigs returns the other AKODeploymentConfigs using the
+// same cloud of the same Avi Controller, which may share networks with obj
+func (r *AKODeploymentConfigReconciler) siblingAKODeploymentConfigs(
+	ctx context.Context,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) ([]akoov1alpha1.AKODeploymentConfig, error) {
+	var adcs akoov1alpha1.AKODeploymentConfigList
+	if err := r.List(ctx, &adcs); err != nil {
+		return nil, err
+	}
+	var res []akoov1alpha1.AKODeploymentConfig
+	for _, adc := range adcs.Items {
+		if adc.Name != obj.Name && adc.Spec.Controller == obj.Spec.Controller && adc.Spec.CloudName == obj.Spec.CloudName {
+			res = append(res, adc)
+		}
+	}
 	return res, nil
 }
 
+// usedNetworks returns the networks configured by the AKODeploymentConfigs
+// which are not being deleted, including their control plane network
+func usedNetworks(adcs []akoov1alpha1.AKODeploymentConfig) []akoov1alpha1.VIPNetwork {
+	var res []akoov1alpha1.VIPNetwork
+	for i := range adcs {
+		if !adcs[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		res = append(res, aviNetworks(&adcs[i])...)
+		if adcs[i].Spec.ControlPlaneNetwork.Name != "" {
+			res = append(res, akoov1alpha1.VIPNetwork{
+				NetworkName: adcs[i].Spec.ControlPlaneNetwork.Name,
+				CIDR:        adcs[i].Spec.ControlPlaneNetwork.CIDR,
+			})
+		}
+	}
+	return res
+}
+
+// siblingIPPools returns the IP pools of the network used by the siblings,
+// with the VIP sub-pools carved out of them
+func siblingIPPools(siblings []akoov1alpha1.AKODeploymentConfig, networkName string, subPools []akoov1alpha1.IPPool) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, vipNetwork := range usedNetworks(siblings) {
+		if vipNetwork.NetworkName == networkName {
+			res = append(res, CarvedIPPools(vipNetwork.IPPools, subPools)...)
+		}
+	}
+	return res
+}
+
+// siblingStaticRanges returns the static ranges of the network the operator
+// added for the siblings
+func siblingStaticRanges(siblings []akoov1alpha1.AKODeploymentConfig, networkName string) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for i := range siblings {
+		res = append(res, ownedStaticRanges(&siblings[i], networkName)...)
+	}
+	return res
+}
+
+// siblingSubnetCIDRs returns the subnets of the network the operator added
+// for the siblings
+func siblingSubnetCIDRs(siblings []akoov1alpha1.AKODeploymentConfig, networkName string) []string {
+	var res []string
+	for i := range siblings {
+		res = append(res, ownedSubnetCIDRs(&siblings[i], networkName)...)
+	}
+	return res
+}
+
+// commonPools returns the ipPools which are also part of others
+func commonPools(ipPools, others []akoov1alpha1.IPPool) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, ipPool := range ipPools {
+		if containsIPPool(others, ipPool) {
+			res = append(res, ipPool)
+		}
+	}
+	return res
+}
+
+// stalePools returns the ipPools which are not part of desired
+func stalePools(ipPools, desired []akoov1alpha1.IPPool) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, ipPool := range ipPools {
+		if !containsIPPool(desired, ipPool) {
+			res = append(res, ipPool)
+		}
+	}
+	return res
+}
+
+// staticRangesInUse returns the ipPools in which the Avi Controller allocated
+// at least one IP of the network which none of the desired pools covers, e.g.
+// a range split into smaller ones is not in use
+func staticRangesInUse(aviClient aviclient.Client, network *models.Network, ipPools, desired []akoov1alpha1.IPPool) ([]akoov1alpha1.IPPool, error) {
+	if len(ipPools) == 0 {
+		return nil, nil
+	}
+	runtime, err := aviClient.NetworkRuntimeGet(ptr.Deref(network.UUID, ""))
+	if err != nil {
+		return nil, err
+	}
+	var res []akoov1alpha1.IPPool
+	for _, ipPool := range ipPools {
+		if runtimeHasAllocatedIP(runtime, ipPool, desired) {
+			res = append(res, ipPool)
+		}
+	}
+	return res, nil
+}
+
+func runtimeHasAllocatedIP(runtime *models.NetworkRuntime, ipPool akoov1alpha1.IPPool, desired []akoov1alpha1.IPPool) bool {
+	start, end := net.ParseIP(ipPool.Start), net.ParseIP(ipPool.End)
+	for _, subnetRuntime := range runtime.SubnetRuntime {
+		for _, rangeRuntime := range subnetRuntime.IPRangeRuntimes {
+			for _, allocated := range rangeRuntime.AllocatedIps {
+				if allocated.IP == nil || allocated.IP.Addr == nil {
+					continue
+				}
+				ip := net.ParseIP(*allocated.IP.Addr)
+				if ip != nil && ipInRange(ip, start, end) && !ipInPools(ip, desired) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ipInPools returns whether ip is in one of ipPools
+func ipInPools(ip net.IP, ipPools []akoov1alpha1.IPPool) bool {
+	for _, ipPool := range ipPools {
+		if ipInRange(ip, net.ParseIP(ipPool.Start), net.ParseIP(ipPool.End)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipInRange returns whether ip is between start and end, inclusive
+func ipInRange(ip, start, end net.IP) bool {
+	return bytes.Compare(ip.To16(), start.To16()) >= 0 && bytes.Compare(ip.To16(), end.To16()) <= 0
+}
+
+// aviNetworks returns the data network followed by the VIP networks of the
+// AKODeploymentConfig
+func aviNetworks(obj *akoov1alpha1.AKODeploymentConfig) []akoov1alpha1.VIPNetwork {
+	res := []akoov1alpha1.VIPNetwork{obj.Spec.DataNetwork.VIPNetwork()}
+	for _, vipNetwork := range obj.Spec.ExtraConfigs.NetworksConfig.VipNetworkList {
+		// the DataNetwork is only synced once, from its own spec
+		if vipNetwork.NetworkName != obj.Spec.DataNetwork.Name {
+			res = append(res, vipNetwork)
+		}
+	}
+	return res
+}
+
+// networkCIDRs returns the CIDR and V6CIDR of the network which are set
+func networkCIDRs(vipNetwork akoov1alpha1.VIPNetwork) []string {
+	var cidrs []string
+	for _, cidr := range []string{vipNetwork.CIDR, vipNetwork.V6CIDR} {
+		if cidr != "" {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+// isNestedSubnet returns whether cidr is a narrower subnet of one of
+// networkCIDRs, such as the subnet of a VIP sub-pool
+func isNestedSubnet(cidr string, networkCIDRs []string) bool {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	ones, _ := ipNet.Mask.Size()
+	for _, networkCIDR := range networkCIDRs {
+		_, network, err := net.ParseCIDR(networkCIDR)
+		if err != nil {
+			continue
+		}
+		networkOnes, _ := network.Mask.Size()
+		if network.Contains(ipNet.IP) && ones > networkOnes {
+			return true
+		}
+	}
+	return false
+}
+
+// ipPoolsOfType returns the IP pools of addrType, nil when there's none so
+// that the static ranges of the subnet are left as they are
+func ipPoolsOfType(ipPools []akoov1alpha1.IPPool, addrType string) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, ipPool := range ipPools {
+		if ipPool.Type == addrType {
+			res = append(res, ipPool)
+		}
+	}
+	return res
+}
+
 func (r *AKODeploymentConfigReconciler) reconcileCloudUsableNetwork(
 	ctx context.Context,
 	log logr.Logger,
@@ -264,19 +868,148 @@ func (r *AKODeploymentConfigReconciler) reconcileCloudUsableNetwork(
 	log = log.WithValues("cloud", obj.Spec.CloudName)
 	log.Info("Start reconciling AVI cloud usable network")
 
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return ctrl.Result{}, errors.New("AVI client not initialized")
+	}
+
+	cloud, err := aviClient.CloudGetByName(obj.Spec.CloudName)
+	if err != nil {
+		log.Error(err, "Failed to get cloud")
+		conditions.MarkFalse(obj, akoov1alpha1.CloudReadyCondition, akoov1alpha1.CloudNotFoundReason,
+			clusterv1.ConditionSeverityError, "Failed to get cloud %s: %v", obj.Spec.CloudName, err)
+		return ctrl.Result{}, err
+	}
+	if cloud.IPAMProviderRef == nil {
+		err := fmt.Errorf("no IPAM provider is registered for cloud %s", obj.Spec.CloudName)
+		log.Error(err, "Cloud is not ready")
+		conditions.MarkFalse(obj, akoov1alpha1.CloudReadyCondition, akoov1alpha1.CloudIPAMNotConfiguredReason,
+			clusterv1.ConditionSeverityError, "No IPAM provider is registered for cloud %s", obj.Spec.CloudName)
+		return ctrl.Result{}, err
+	}
+	conditions.MarkTrue(obj, akoov1alpha1.CloudReadyCondition)
+
+	siblings, err := r.siblingAKODeploymentConfigs(ctx, obj)
+	if err != nil {
+		log.Error(err, "Failed to list AKODeploymentConfigs")
+		return ctrl.Result{}, err
+	}
+	// usable networks added for another AKODeploymentConfig are shared
+	// rather than configured by hand
+	addUsableNetwork := func(networkName string) error {
+		added, err := r.AddUsableNetwork(aviClient, obj.Spec.CloudName, networkName, log)
+		if err != nil {
+			return err
+		}
+		if added || siblingOwnsUsableNetwork(siblings, networkName) {
+			addOwnedUsableNetwork(obj, networkName)
+		}
+		return nil
+	}
+
 	if obj.Spec.ControlPlaneNetwork.Name != "" && obj.Spec.ControlPlaneNetwork.CIDR != "" {
-		if err := r.AddUsableNetwork(r.aviClient, obj.Spec.CloudName, obj.Spec.ControlPlaneNetwork.Name, log); err != nil {
+		if err := addUsableNetwork(obj.Spec.ControlPlaneNetwork.Name); err != nil {
 			log.Error(err, "Failed to add usable network", "network", obj.Spec.ControlPlaneNetwork.Name)
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.UsableNetworkAddFailedReason,
+				"Failed to add usable network %s to cloud %s: %v", obj.Spec.ControlPlaneNetwork.Name, obj.Spec.CloudName, err)
+			conditions.MarkFalse(obj, akoov1alpha1.ControlPlaneNetworkSyncedCondition, akoov1alpha1.UsableNetworkAddFailedConditionReason,
+				clusterv1.ConditionSeverityError, "Failed to add usable network %s: %v", obj.Spec.ControlPlaneNetwork.Name, err)
 			return ctrl.Result{}, err
 		}
+		conditions.MarkTrue(obj, akoov1alpha1.ControlPlaneNetworkSyncedCondition)
+	} else {
+		conditions.Delete(obj, akoov1alpha1.ControlPlaneNetworkSyncedCondition)
+	}
+
+	// the data network and every VIP network are usable networks of the cloud
+	var errs []error
+	added := map[string]bool{}
+	for _, vipNetwork := range aviNetworks(obj) {
+		if added[vipNetwork.NetworkName] {
+			continue
+		}
+		added[vipNetwork.NetworkName] = true
+		if err := addUsableNetwork(vipNetwork.NetworkName); err != nil {
+			log.Error(err, "Failed to add usable network", "network", vipNetwork.NetworkName)
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.UsableNetworkAddFailedReason,
+				"Failed to add usable network %s to cloud %s: %v", vipNetwork.NetworkName, obj.Spec.CloudName, err)
+			conditions.MarkFalse(obj, akoov1alpha1.DataNetworkSyncedCondition, akoov1alpha1.UsableNetworkAddFailedConditionReason,
+				clusterv1.ConditionSeverityError, "Failed to add usable network %s: %v", vipNetwork.NetworkName, err)
+			errs = append(errs, err)
+		}
 	}
 
-	if err := r.AddUsableNetwork(r.aviClient, obj.Spec.CloudName, obj.Spec.DataNetwork.Name, log); err != nil {
-		log.Error(err, "Failed to add usable network", "network", obj.Spec.DataNetwork.Name)
+	return ctrl.Result{}, kerrors.NewAggregate(errs)
+}
+
+// reconcileCloudUsableNetworkDelete removes the usable networks the operator
+// added to the cloud's IPAM profile, unless another AKODeploymentConfig still
+// uses them
+func (r *AKODeploymentConfigReconciler) reconcileCloudUsableNetworkDelete(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	log = log.WithValues("cloud", obj.Spec.CloudName)
+	log.Info("Start cleaning up AVI cloud usable network")
+
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return ctrl.Result{}, errors.New("AVI client not initialized")
+	}
+	siblings, err := r.siblingAKODeploymentConfigs(ctx, obj)
+	if err != nil {
+		log.Error(err, "Failed to list AKODeploymentConfigs")
 		return ctrl.Result{}, err
 	}
+	used := map[string]bool{}
+	for _, vipNetwork := range usedNetworks(siblings) {
+		used[vipNetwork.NetworkName] = true
+	}
 
-	return ctrl.Result{}, nil
+	var errs []error
+	var remaining []string
+	for _, networkName := range obj.Status.UsableNetworks {
+		if used[networkName] {
+			log.Info("Usable network is still used by another AKODeploymentConfig", "network", networkName)
+			continue
+		}
+		if err := r.RemoveUsableNetwork(aviClient, obj.Spec.CloudName, networkName, log); err != nil {
+			if aviclient.IsAviObjectNonExistentError(err) {
+				log.Info("Network doesn't exist anymore, skip removing it from the usable networks", "network", networkName)
+				continue
+			}
+			log.Error(err, "Failed to remove usable network", "network", networkName)
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.UsableNetworkRemoveFailedReason,
+				"Failed to remove usable network %s from cloud %s: %v", networkName, obj.Spec.CloudName, err)
+			errs = append(errs, err)
+			remaining = append(remaining, networkName)
+		}
+	}
+	obj.Status.UsableNetworks = remaining
+	return ctrl.Result{}, kerrors.NewAggregate(errs)
+}
+
+// addOwnedUsableNetwork records the network as a usable network the operator
+// added to the cloud's IPAM profile
+func addOwnedUsableNetwork(obj *akoov1alpha1.AKODeploymentConfig, networkName string) {
+	if !slices.Contains(obj.Status.UsableNetworks, networkName) {
+		obj.Status.UsableNetworks = append(obj.Status.UsableNetworks, networkName)
+		sort.Strings(obj.Status.UsableNetworks)
+	}
+}
+
+// siblingOwnsUsableNetwork returns if the operator added the network to the
+// usable networks for one of the siblings
+func siblingOwnsUsableNetwork(siblings []akoov1alpha1.AKODeploymentConfig, networkName string) bool {
+	for _, sibling := range siblings {
+		if slices.Contains(sibling.Status.UsableNetworks, networkName) {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *AKODeploymentConfigReconciler) reconcileAviInfraSetting(
@@ -290,6 +1023,7 @@ func (r *AKODeploymentConfigReconciler) reconcileAviInfraSetting(
 
 	if adc.Spec.ControlPlaneNetwork.Name == "" {
 		log.Info("ControlPlaneNetwork is empty in akoDeploymentConfig, skip creating AVIInfraSetting")
+		conditions.Delete(adc, akoov1alpha1.AviInfraSettingReadyCondition)
 		return res, nil
 	}
 
@@ -301,22 +1035,38 @@ func (r *AKODeploymentConfigReconciler) reconcileAviInfraSetting(
 	}, aviInfraSetting); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("AVIInfraSetting doesn't exist, start creating it")
-			return res, r.Create(ctx, newAviInfraSetting)
+			if err := r.Create(ctx, newAviInfraSetting); err != nil {
+				r.Recorder.Eventf(adc, corev1.EventTypeWarning, akoov1alpha1.AviInfraSettingFailedReason,
+					"Failed to create AviInfraSetting %s: %v", newAviInfraSetting.Name, err)
+				markAviInfraSettingFailed(adc, err)
+				return res, err
+			}
+			r.Recorder.Eventf(adc, corev1.EventTypeNormal, akoov1alpha1.AviInfraSettingCreatedReason,
+				"Created AviInfraSetting %s", newAviInfraSetting.Name)
+			conditions.MarkTrue(adc, akoov1alpha1.AviInfraSettingReadyCondition)
+			return res, nil
 		}
 		log.Error(err, "Failed to get AVIInfraSetting, requeue")
+		markAviInfraSettingFailed(adc, err)
 		return res, err
 	}
 	newAviInfraSetting.Spec.DeepCopyInto(&aviInfraSetting.Spec)
-	return res, r.Update(ctx, aviInfraSetting)
+	if err := r.Update(ctx, aviInfraSetting); err != nil {
+		r.Recorder.Eventf(adc, corev1.EventTypeWarning, akoov1alpha1.AviInfraSettingFailedReason,
+			"Failed to update AviInfraSetting %s: %v", aviInfraSetting.Name, err)
+		markAviInfraSettingFailed(adc, err)
+		return res, err
+	}
+	conditions.MarkTrue(adc, akoov1alpha1.AviInfraSettingReadyCondition)
+	return res, nil
 }
 
-func (r *AKODeploymentConfigReconciler) createAviInfraSetting(adc *akoov1alpha1.AKODeploymentConfig) *akov1beta1.AviInfraSetting {
-	// ShardVSSize describes ingress shared virtual service size, default value is SMALL
-	shardSize := "SMALL"
-	if adc.Spec.ExtraConfigs.IngressConfigs.ShardVSSize != "" {
-		shardSize = adc.Spec.ExtraConfigs.IngressConfigs.ShardVSSize
-	}
+func markAviInfraSettingFailed(adc *akoov1alpha1.AKODeploymentConfig, err error) {
+	conditions.MarkFalse(adc, akoov1alpha1.AviInfraSettingReadyCondition, akoov1alpha1.AviInfraSettingSyncFailedReason,
+		clusterv1.ConditionSeverityError, "Failed to sync AviInfraSetting %s: %v", haprovider.GetAviInfraSettingName(adc), err)
+}
 
+func (r *AKODeploymentConfigReconciler) createAviInfraSetting(adc *akoov1alpha1.AKODeploymentConfig) *akov1beta1.AviInfraSetting {
 	vipNetwork := []akov1beta1.AviInfraSettingVipNetwork{{
 		NetworkName: adc.Spec.ControlPlaneNetwork.Name,
 		Cidr:        adc.Spec.ControlPlaneNetwork.CIDR,
@@ -328,6 +1078,18 @@ func (r *AKODeploymentConfigReconciler) createAviInfraSetting(adc *akoov1alpha1.
 			V6Cidr:      adc.Spec.ControlPlaneNetwork.CIDR,
 		}}
 	}
+	return newAviInfraSetting(adc, haprovider.GetAviInfraSettingName(adc), vipNetwork)
+}
+
+// newAviInfraSetting returns an AviInfraSetting placing the virtual services
+// on the Service Engine Group of the AKODeploymentConfig and allocating their
+// VIPs from vipNetwork
+func newAviInfraSetting(adc *akoov1alpha1.AKODeploymentConfig, name string, vipNetwork []akov1beta1.AviInfraSettingVipNetwork) *akov1beta1.AviInfraSetting {
+	// ShardVSSize describes ingress shared virtual service size, default value is SMALL
+	shardSize := "SMALL"
+	if adc.Spec.ExtraConfigs.IngressConfigs.ShardVSSize != "" {
+		shardSize = adc.Spec.ExtraConfigs.IngressConfigs.ShardVSSize
+	}
 
 	t1LR := ptr.To("")
 	if adc.Spec.ExtraConfigs.NetworksConfig.NsxtT1LR != "" {
@@ -336,7 +1098,7 @@ func (r *AKODeploymentConfigReconciler) createAviInfraSetting(adc *akoov1alpha1.
 
 	return &akov1beta1.AviInfraSetting{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: haprovider.GetAviInfraSettingName(adc),
+			Name: name,
 		},
 		Spec: akov1beta1.AviInfraSettingSpec{
 			SeGroup: akov1beta1.AviInfraSettingSeGroup{
@@ -388,18 +1150,100 @@ func (r *AKODeploymentConfigReconciler) reconcileAviInfraSettingDelete(
 		log.Error(err, "Failed to get AVIInfraSetting, requeue")
 		return res, err
 	}
-	return res, r.Delete(ctx, aviInfraSetting)
+	if err := r.Delete(ctx, aviInfraSetting); err != nil {
+		return res, err
+	}
+	r.Recorder.Eventf(adc, corev1.EventTypeNormal, akoov1alpha1.AviInfraSettingDeletedReason,
+		"Deleted AviInfraSetting %s", aviInfraSetting.Name)
+	return res, nil
+}
+
+// EnsureAviNetworkSubnets brings network to the intended state by ensuring
+// there is one subnet per cidr, e.g. the IPv4 and IPv6 subnets of a
+// dual-stack network, with the ipPools of the same type as static ranges.
+// The owned static ranges which are not part of ipPools are removed from every
+// subnet, it returns the static ranges still owned afterwards.
+func EnsureAviNetworkSubnets(network *models.Network, cidrs []string, ipPools, owned []akoov1alpha1.IPPool, log logr.Logger) (bool, []akoov1alpha1.IPPool, error) {
+	modified := false
+	var stillOwned []akoov1alpha1.IPPool
+	synced := map[*models.Subnet]bool{}
+	subnets := make([]*net.IPNet, 0, len(cidrs))
+	for _, networkCIDR := range cidrs {
+		_, cidr, err := net.ParseCIDR(networkCIDR)
+		if err != nil {
+			return modified, nil, err
+		}
+		subnets = append(subnets, cidr)
+	}
+	for _, cidr := range subnets {
+		ones, _ := cidr.Mask.Size()
+		mask := int32(ones)
+
+		addrType := "V4"
+		if cidr.IP.To4() == nil {
+			addrType = "V6"
+		}
+		subnetModified, subnetOwned := EnsureAviNetwork(network, addrType, cidr, mask, subnetIPPools(ipPools, cidr, subnets), owned, log)
+		if subnetModified {
+			modified = true
+		}
+		stillOwned = append(stillOwned, subnetOwned...)
+		if index, found := AviNetworkContainsSubnet(network, cidr.IP.String(), mask); found {
+			synced[network.ConfiguredSubnets[index]] = true
+		}
+	}
+	// subnets out of the spec only have their owned static ranges removed
+	for _, subnet := range network.ConfiguredSubnets {
+		if synced[subnet] || subnet.Prefix == nil || subnet.Prefix.IPAddr == nil {
+			continue
+		}
+		subnetModified, subnetOwned := EnsureStaticRanges(subnet, nil, owned, ptr.Deref(subnet.Prefix.IPAddr.Type, "V4"))
+		if subnetModified {
+			modified = true
+		}
+		stillOwned = append(stillOwned, subnetOwned...)
+	}
+	return modified, stillOwned, nil
+}
+
+// subnetIPPools returns the ipPools which go to the subnet: the ones within it
+// but within none of the narrower subnets, so that a VIP sub-pool is only in
+// its own subnet. Pools out of every subnet go to all of them.
+func subnetIPPools(ipPools []akoov1alpha1.IPPool, subnet *net.IPNet, subnets []*net.IPNet) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, ipPool := range ipPools {
+		if narrowest := narrowestSubnet(net.ParseIP(ipPool.Start), subnets); narrowest == nil || narrowest == subnet {
+			res = append(res, ipPool)
+		}
+	}
+	return res
+}
+
+// narrowestSubnet returns the subnet with the longest prefix holding ip
+func narrowestSubnet(ip net.IP, subnets []*net.IPNet) *net.IPNet {
+	var res *net.IPNet
+	resOnes := -1
+	for _, subnet := range subnets {
+		ones, _ := subnet.Mask.Size()
+		if ip != nil && subnet.Contains(ip) && ones > resOnes {
+			res, resOnes = subnet, ones
+		}
+	}
+	return res
 }
 
 // EnsureAviNetwork brings network to the intented state by ensuring there is
-// one subnet in network that has the specified cidr/mask and ipPools
-func EnsureAviNetwork(network *models.Network, addrType string, cidr *net.IPNet, mask int32, ipPools []akoov1alpha1.IPPool, log logr.Logger) bool {
+// one subnet in network that has the specified cidr/mask and the ipPools of
+// addrType. It returns the owned static ranges of the subnet afterwards.
+func EnsureAviNetwork(network *models.Network, addrType string, cidr *net.IPNet, mask int32, ipPools, owned []akoov1alpha1.IPPool, log logr.Logger) (bool, []akoov1alpha1.IPPool) {
 	var foundSubnet, modified bool
+	var stillOwned []akoov1alpha1.IPPool
+	ipPools = ipPoolsOfType(ipPools, addrType)
 	var index int
 	if index, foundSubnet = AviNetworkContainsSubnet(network, cidr.IP.String(), mask); foundSubnet {
 		log.V(3).Info("Found matching subnet", "subnet", network.ConfiguredSubnets[index])
 		subnet := network.ConfiguredSubnets[index]
-		modified = EnsureStaticRanges(subnet, ipPools, addrType)
+		modified, stillOwned = EnsureStaticRanges(subnet, ipPools, owned, addrType)
 		if modified {
 			// Update the original subnet in network
 			network.ConfiguredSubnets[index] = subnet
@@ -418,28 +1262,81 @@ func EnsureAviNetwork(network *models.Network, addrType string, cidr *net.IPNet,
 		// Add subnet into the network
 		network.ConfiguredSubnets = append(network.ConfiguredSubnets, subnet)
 		modified = true
+		stillOwned = ipPools
 	}
-	return modified
+	return modified, stillOwned
 }
 
-// ensureStaticRanges creates or updates the subnet's static ranges to ensure IP
-// ranges in IPPools are reflected in the subnet. It does so by firstly doing a
-// sort on the static ranges, then try to extend an exisitng range or fill in
-// the hole.
+// EnsureStaticRanges adds the ipPools of addrType missing from the subnet's
+// static ranges and removes the owned static ranges which are not part of
+// ipPools. Static ranges the operator doesn't own, i.e. created by hand, are
+// never removed nor claimed when they match an IP pool. It returns the owned
+// static ranges of the subnet afterwards.
 // Note: ippools are guaranteed to be non-overlapping by validation
-func EnsureStaticRanges(subnet *models.Subnet, ipPools []akoov1alpha1.IPPool, addrType string) bool {
-	// no ip pools specified in akodeploymentconfig, don't update subnet settings
-	if ipPools == nil {
-		return false
+func EnsureStaticRanges(subnet *models.Subnet, ipPools, owned []akoov1alpha1.IPPool, addrType string) (bool, []akoov1alpha1.IPPool) {
+	ipPools = ipPoolsOfType(ipPools, addrType)
+	modified := false
+	var stillOwned []akoov1alpha1.IPPool
+	staticRanges := []*models.StaticIPRange{}
+	for _, staticRange := range subnet.StaticIPRanges {
+		ipPool := ipPoolFromStaticRange(staticRange, addrType)
+		if containsIPPool(owned, ipPool) {
+			if !containsIPPool(ipPools, ipPool) {
+				modified = true
+				continue
+			}
+			stillOwned = append(stillOwned, ipPool)
+		}
+		staticRanges = append(staticRanges, staticRange)
 	}
-	newStaticIPRanges := CreateStaticRangeFromIPPools(ipPools)
-	res := !IsStaticIPRangeEqual(newStaticIPRanges, subnet.StaticIPRanges)
-	if res {
-		subnet.StaticIPRanges = newStaticIPRanges
+	for _, ipPool := range ipPools {
+		if containsIPPool(ipPoolsFromStaticRanges(staticRanges, addrType), ipPool) {
+			continue
+		}
+		staticRanges = append(staticRanges, CreateStaticRangeFromIPPools([]akoov1alpha1.IPPool{ipPool})...)
+		stillOwned = append(stillOwned, ipPool)
+		modified = true
+	}
+	if modified {
+		SortStaticRanges(staticRanges)
+		subnet.StaticIPRanges = staticRanges
+	}
+	return modified, stillOwned
+}
+
+func ipPoolFromStaticRange(staticRange *models.StaticIPRange, addrType string) akoov1alpha1.IPPool {
+	return akoov1alpha1.IPPool{
+		Start: ptr.Deref(staticRange.Range.Begin.Addr, ""),
+		End:   ptr.Deref(staticRange.Range.End.Addr, ""),
+		Type:  addrType,
+	}
+}
+
+func ipPoolsFromStaticRanges(staticRanges []*models.StaticIPRange, addrType string) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, staticRange := range staticRanges {
+		res = append(res, ipPoolFromStaticRange(staticRange, addrType))
 	}
 	return res
 }
 
+// containsIPPool returns if ipPools has a pool with the same start and end IPs
+// as ipPool, whatever their notation
+func containsIPPool(ipPools []akoov1alpha1.IPPool, ipPool akoov1alpha1.IPPool) bool {
+	for _, p := range ipPools {
+		if isSameIPPool(p, ipPool) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSameIPPool compares the parsed addresses of the pools so that different
+// notations of the same IPv6 address match
+func isSameIPPool(p1, p2 akoov1alpha1.IPPool) bool {
+	return net.ParseIP(p1.Start).Equal(net.ParseIP(p2.Start)) && net.ParseIP(p1.End).Equal(net.ParseIP(p2.End))
+}
+
 func IsStaticIPRangeEqual(r1, r2 []*models.StaticIPRange) bool {
 	SortStaticRanges(r1)
 	SortStaticRanges(r2)
@@ -0,0 +1,408 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+)
+
+// ClusterAviInfraSettingName returns the name of the AviInfraSetting which
+// older releases created for the sub-pool of the Cluster
+func ClusterAviInfraSettingName(cluster *clusterv1.Cluster) string {
+	return cluster.Namespace + "-" + cluster.Name + "-vip-pool-ais"
+}
+
+// reconcileClusterIPPool is a reconcileClusterPhase. When the
+// AKODeploymentConfig dedicates VIP sub-pools to its clusters, it carves one
+// out of the data network IP pools for the Cluster, configures its subnet in
+// the Avi data network and records it on the Cluster, the AKO values of the
+// Cluster are scoped to that subnet. Otherwise the sub-pool of the Cluster, if
+// any, is released.
+func (r *AKODeploymentConfigReconciler) reconcileClusterIPPool(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	size := int64(adc.Spec.DataNetwork.ClusterIPPoolSize)
+	if size == 0 {
+		return r.reconcileClusterIPPoolDelete(ctx, log, cluster, adc)
+	}
+
+	others, err := r.otherClusterIPPools(ctx, cluster, adc)
+	if err != nil {
+		log.Error(err, "Failed to list the VIP sub-pools of the other clusters")
+		return res, err
+	}
+
+	ipPool, ok := clusterIPPool(adc, cluster)
+	if !ok {
+		// the status may have been lost, adopt the sub-pool recorded on
+		// the Cluster
+		ipPool, ok = akoov1alpha1.ParseIPPool(cluster.Annotations[akoov1alpha1.AviClusterIPPoolAnnotation])
+	}
+	if ok && !IsValidClusterIPPool(ipPool, adc.Spec.DataNetwork.IPPools, others, size) {
+		log.Info("VIP sub-pool no longer fits the data network IP pools, reallocate it",
+			"ipPool", ipPool.Start+"-"+ipPool.End)
+		ok = false
+	}
+	if ok {
+		setClusterIPPool(adc, cluster, ipPool)
+	} else {
+		removeClusterIPPool(adc, cluster.Namespace, cluster.Name)
+		ipPool, ok = AllocateClusterIPPool(adc.Spec.DataNetwork.IPPools, others, size)
+		if !ok {
+			err := fmt.Errorf("no free range of %d IPs left in the IP pools of data network %s", size, adc.Spec.DataNetwork.Name)
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.ClusterIPPoolExhaustedReason,
+				"Failed to allocate a VIP sub-pool: %v", err)
+			return res, err
+		}
+		setClusterIPPool(adc, cluster, ipPool)
+		// AKO is only pointed at the subnet of the sub-pool once it's
+		// configured in the Avi network
+		if err := r.syncDataNetwork(ctx, log, adc); err != nil {
+			removeClusterIPPool(adc, cluster.Namespace, cluster.Name)
+			return res, err
+		}
+		log.Info("Allocated VIP sub-pool", "ipPool", ipPool.Start+"-"+ipPool.End)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ClusterIPPoolAllocatedReason,
+			"Allocated VIP sub-pool %s-%s from data network %s", ipPool.Start, ipPool.End, adc.Spec.DataNetwork.Name)
+	}
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[akoov1alpha1.AviClusterIPPoolAnnotation] = ipPool.Start + "-" + ipPool.End
+
+	return res, r.deleteClusterAviInfraSetting(ctx, log, ClusterAviInfraSettingName(cluster))
+}
+
+// reconcileClusterIPPoolDelete is a reconcileClusterPhase. It deletes the
+// AviInfraSetting of the Cluster and gives its VIP sub-pool back.
+func (r *AKODeploymentConfigReconciler) reconcileClusterIPPoolDelete(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	if err := r.deleteClusterAviInfraSetting(ctx, log, ClusterAviInfraSettingName(cluster)); err != nil {
+		return res, err
+	}
+	if ipPool, ok := clusterIPPool(adc, cluster); ok {
+		removeClusterIPPool(adc, cluster.Namespace, cluster.Name)
+		log.Info("Released VIP sub-pool", "ipPool", ipPool.Start+"-"+ipPool.End)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ClusterIPPoolReleasedReason,
+			"Released VIP sub-pool %s-%s", ipPool.Start, ipPool.End)
+		// the sub-pool is merged back into the data network IP pools, which
+		// is retried with the network sync when it fails
+		if err := r.syncDataNetwork(ctx, log, adc); err != nil {
+			log.Error(err, "Failed to give the VIP sub-pool back to the data network")
+		}
+	}
+	delete(cluster.Annotations, akoov1alpha1.AviClusterIPPoolAnnotation)
+	return res, nil
+}
+
+// reconcileClusterIPPools releases the VIP sub-pools of the clusters which
+// are no longer selected by the AKODeploymentConfig, e.g. because they are
+// gone. It's a reconcilePhase function
+func (r *AKODeploymentConfigReconciler) reconcileClusterIPPools(
+	ctx context.Context,
+	log logr.Logger,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	var kept []akoov1alpha1.ClusterIPPool
+	for _, ipPool := range adc.Status.ClusterIPPools {
+		cluster := &clusterv1.Cluster{}
+		cluster.Name, cluster.Namespace = ipPool.Name, ipPool.Namespace
+		if phases.GetClusterStatus(adc, cluster) != nil {
+			kept = append(kept, ipPool)
+			continue
+		}
+		if err := r.deleteClusterAviInfraSetting(ctx, log, ClusterAviInfraSettingName(cluster)); err != nil {
+			kept = append(kept, ipPool)
+			continue
+		}
+		log.Info("Released VIP sub-pool of a cluster no longer selected",
+			"cluster", ipPool.Namespace+"/"+ipPool.Name, "ipPool", ipPool.Start+"-"+ipPool.End)
+	}
+	if len(kept) == len(adc.Status.ClusterIPPools) {
+		return res, nil
+	}
+	adc.Status.ClusterIPPools = kept
+	if err := r.syncDataNetwork(ctx, log, adc); err != nil {
+		log.Error(err, "Failed to give the VIP sub-pools back to the data network")
+	}
+	return res, nil
+}
+
+// syncDataNetwork syncs the subnets and static ranges of the Avi data network
+// with the VIP sub-pools of the clusters
+func (r *AKODeploymentConfigReconciler) syncDataNetwork(
+	ctx context.Context,
+	log logr.Logger,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) error {
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		return errors.New("AVI client not initialized")
+	}
+	siblings, err := r.siblingAKODeploymentConfigs(ctx, adc)
+	if err != nil {
+		log.Error(err, "Failed to list AKODeploymentConfigs")
+		return err
+	}
+	vipNetwork := adc.Spec.DataNetwork.VIPNetwork()
+	_, err = r.reconcileNetworkSubnet(log.WithValues("network", vipNetwork.NetworkName), aviClient, adc, vipNetwork, siblings)
+	return err
+}
+
+// deleteClusterAviInfraSetting deletes the AviInfraSetting of a cluster, if
+// any
+func (r *AKODeploymentConfigReconciler) deleteClusterAviInfraSetting(ctx context.Context, log logr.Logger, name string) error {
+	aviInfraSetting := &akov1beta1.AviInfraSetting{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, aviInfraSetting); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		log.Error(err, "Failed to get the AviInfraSetting of the cluster")
+		return err
+	}
+	if err := r.Delete(ctx, aviInfraSetting); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "Failed to delete the AviInfraSetting of the cluster")
+		return err
+	}
+	log.Info("Deleted the AviInfraSetting of the cluster", "aviInfraSetting", name)
+	return nil
+}
+
+// otherClusterIPPools returns the VIP sub-pools of every other cluster
+// carved from the same data network, including the ones of the sibling
+// AKODeploymentConfigs
+func (r *AKODeploymentConfigReconciler) otherClusterIPPools(
+	ctx context.Context,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) ([]akoov1alpha1.IPPool, error) {
+	siblings, err := r.siblingAKODeploymentConfigs(ctx, adc)
+	if err != nil {
+		return nil, err
+	}
+	var res []akoov1alpha1.IPPool
+	for _, ipPool := range adc.Status.ClusterIPPools {
+		if ipPool.Name != cluster.Name || ipPool.Namespace != cluster.Namespace {
+			res = append(res, ipPool.IPPool)
+		}
+	}
+	for _, sibling := range siblings {
+		if sibling.Spec.DataNetwork.Name != adc.Spec.DataNetwork.Name {
+			continue
+		}
+		for _, ipPool := range sibling.Status.ClusterIPPools {
+			res = append(res, ipPool.IPPool)
+		}
+	}
+	return res, nil
+}
+
+// clusterIPPool returns the VIP sub-pool of the Cluster recorded in the
+// status of the AKODeploymentConfig
+func clusterIPPool(adc *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) (akoov1alpha1.IPPool, bool) {
+	for _, ipPool := range adc.Status.ClusterIPPools {
+		if ipPool.Name == cluster.Name && ipPool.Namespace == cluster.Namespace {
+			return ipPool.IPPool, true
+		}
+	}
+	return akoov1alpha1.IPPool{}, false
+}
+
+// setClusterIPPool records ipPool as the VIP sub-pool of the Cluster
+func setClusterIPPool(adc *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, ipPool akoov1alpha1.IPPool) {
+	removeClusterIPPool(adc, cluster.Namespace, cluster.Name)
+	adc.Status.ClusterIPPools = append(adc.Status.ClusterIPPools, akoov1alpha1.ClusterIPPool{
+		Name:      cluster.Name,
+		Namespace: cluster.Namespace,
+		IPPool:    ipPool,
+	})
+}
+
+func removeClusterIPPool(adc *akoov1alpha1.AKODeploymentConfig, namespace, name string) {
+	var res []akoov1alpha1.ClusterIPPool
+	for _, ipPool := range adc.Status.ClusterIPPools {
+		if ipPool.Name != name || ipPool.Namespace != namespace {
+			res = append(res, ipPool)
+		}
+	}
+	adc.Status.ClusterIPPools = res
+}
+
+// clusterIPPoolsOf returns the VIP sub-pools the adcs carved out of the
+// network
+func clusterIPPoolsOf(adcs []akoov1alpha1.AKODeploymentConfig, networkName string) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, adc := range adcs {
+		if adc.Spec.DataNetwork.Name != networkName {
+			continue
+		}
+		for _, ipPool := range adc.Status.ClusterIPPools {
+			res = append(res, ipPool.IPPool)
+		}
+	}
+	return res
+}
+
+// CarvedIPPools returns ipPools with the subPools within them carved out,
+// followed by those subPools, so that every IP is in a single static range.
+// ipPools are returned as they are when no sub-pool is within them.
+func CarvedIPPools(ipPools, subPools []akoov1alpha1.IPPool) []akoov1alpha1.IPPool {
+	var res, carved []akoov1alpha1.IPPool
+	for _, ipPool := range ipPools {
+		start, end, ok := ipPoolBounds(ipPool)
+		if !ok {
+			res = append(res, ipPool)
+			continue
+		}
+		var within []akoov1alpha1.IPPool
+		for _, subPool := range subPools {
+			subStart, subEnd, ok := ipPoolBounds(subPool)
+			if ok && subPool.Type == ipPool.Type && subStart.Cmp(start) >= 0 && subEnd.Cmp(end) <= 0 &&
+				!containsIPPool(within, subPool) {
+				within = append(within, subPool)
+			}
+		}
+		if len(within) == 0 {
+			res = append(res, ipPool)
+			continue
+		}
+		sort.Slice(within, func(i, j int) bool { return isIPLessThan(within[i].Start, within[j].Start) })
+		for _, subPool := range within {
+			subStart, subEnd, _ := ipPoolBounds(subPool)
+			if subStart.Cmp(start) > 0 {
+				res = append(res, akoov1alpha1.IPPool{
+					Start: intToIP(start).String(),
+					End:   intToIP(new(big.Int).Sub(subStart, big.NewInt(1))).String(),
+					Type:  ipPool.Type,
+				})
+			}
+			start = new(big.Int).Add(subEnd, big.NewInt(1))
+		}
+		if start.Cmp(end) <= 0 {
+			res = append(res, akoov1alpha1.IPPool{Start: intToIP(start).String(), End: ipPool.End, Type: ipPool.Type})
+		}
+		carved = append(carved, within...)
+	}
+	return append(res, carved...)
+}
+
+// AllocateClusterIPPool returns the first range of size IPs, aligned on its
+// size, which is within one of ipPools and doesn't overlap the allocated
+// ranges. It returns false when there's no room left.
+func AllocateClusterIPPool(ipPools, allocated []akoov1alpha1.IPPool, size int64) (akoov1alpha1.IPPool, bool) {
+	if size <= 0 {
+		return akoov1alpha1.IPPool{}, false
+	}
+	n := big.NewInt(size)
+	for _, ipPool := range ipPools {
+		poolStart, poolEnd, ok := ipPoolBounds(ipPool)
+		if !ok {
+			continue
+		}
+		start := alignUp(poolStart, n)
+		for {
+			end := new(big.Int).Sub(new(big.Int).Add(start, n), big.NewInt(1))
+			if end.Cmp(poolEnd) > 0 {
+				break
+			}
+			overlapped, ok := overlappingIPPool(allocated, start, end)
+			if !ok {
+				return akoov1alpha1.IPPool{
+					Start: intToIP(start).String(),
+					End:   intToIP(end).String(),
+					Type:  ipPool.Type,
+				}, true
+			}
+			// skip past the overlapping range
+			_, overlappedEnd, _ := ipPoolBounds(overlapped)
+			start = alignUp(new(big.Int).Add(overlappedEnd, big.NewInt(1)), n)
+		}
+	}
+	return akoov1alpha1.IPPool{}, false
+}
+
+// IsValidClusterIPPool returns whether ipPool is a range of size IPs, aligned
+// on its size, within one of ipPools and not overlapping the allocated ranges
+func IsValidClusterIPPool(ipPool akoov1alpha1.IPPool, ipPools, allocated []akoov1alpha1.IPPool, size int64) bool {
+	start, end, ok := ipPoolBounds(ipPool)
+	if !ok || size <= 0 {
+		return false
+	}
+	n := big.NewInt(size)
+	if new(big.Int).Sub(end, start).Cmp(new(big.Int).Sub(n, big.NewInt(1))) != 0 ||
+		new(big.Int).Mod(start, n).Sign() != 0 {
+		return false
+	}
+	if _, overlaps := overlappingIPPool(allocated, start, end); overlaps {
+		return false
+	}
+	for _, p := range ipPools {
+		poolStart, poolEnd, ok := ipPoolBounds(p)
+		if ok && p.Type == ipPool.Type && poolStart.Cmp(start) <= 0 && poolEnd.Cmp(end) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// overlappingIPPool returns the first of ipPools overlapping [start, end]
+func overlappingIPPool(ipPools []akoov1alpha1.IPPool, start, end *big.Int) (akoov1alpha1.IPPool, bool) {
+	for _, ipPool := range ipPools {
+		poolStart, poolEnd, ok := ipPoolBounds(ipPool)
+		if ok && poolStart.Cmp(end) <= 0 && poolEnd.Cmp(start) >= 0 {
+			return ipPool, true
+		}
+	}
+	return akoov1alpha1.IPPool{}, false
+}
+
+// ipPoolBounds returns the start and the end of the pool as integers
+func ipPoolBounds(ipPool akoov1alpha1.IPPool) (*big.Int, *big.Int, bool) {
+	start, end := net.ParseIP(ipPool.Start), net.ParseIP(ipPool.End)
+	if start == nil || end == nil {
+		return nil, nil, false
+	}
+	return new(big.Int).SetBytes(start.To16()), new(big.Int).SetBytes(end.To16()), true
+}
+
+// intToIP is the reverse of ipPoolBounds, IPv4 addresses are kept in their
+// IPv4-mapped form which net.IP prints as IPv4
+func intToIP(i *big.Int) net.IP {
+	return i.FillBytes(make([]byte, net.IPv6len))
+}
+
+// alignUp rounds i up to a multiple of n
+func alignUp(i, n *big.Int) *big.Int {
+	res := new(big.Int).Add(i, new(big.Int).Sub(n, big.NewInt(1)))
+	return res.Sub(res, new(big.Int).Mod(res, n))
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
+)
+
+func unitTestClusterIPPools() {
+	v4Pool := func(start, end string) akoov1alpha1.IPPool {
+		return akoov1alpha1.IPPool{Start: start, End: end, Type: "V4"}
+	}
+
+	Context("AllocateClusterIPPool", func() {
+		var (
+			ipPools   []akoov1alpha1.IPPool
+			allocated []akoov1alpha1.IPPool
+			size      int64
+			ipPool    akoov1alpha1.IPPool
+			ok        bool
+		)
+		BeforeEach(func() {
+			ipPools = []akoov1alpha1.IPPool{v4Pool("10.0.0.1", "10.0.0.30")}
+			allocated = nil
+			size = 8
+		})
+		JustBeforeEach(func() {
+			ipPool, ok = akodeploymentconfig.AllocateClusterIPPool(ipPools, allocated, size)
+		})
+
+		It("should allocate the first aligned range of the pools", func() {
+			Expect(ok).To(BeTrue())
+			Expect(ipPool).To(Equal(v4Pool("10.0.0.8", "10.0.0.15")))
+		})
+
+		When("ranges are already allocated", func() {
+			BeforeEach(func() {
+				allocated = []akoov1alpha1.IPPool{v4Pool("10.0.0.8", "10.0.0.15")}
+			})
+			It("should skip them", func() {
+				Expect(ok).To(BeTrue())
+				Expect(ipPool).To(Equal(v4Pool("10.0.0.16", "10.0.0.23")))
+			})
+		})
+
+		When("the first pool is full", func() {
+			BeforeEach(func() {
+				allocated = []akoov1alpha1.IPPool{v4Pool("10.0.0.8", "10.0.0.15"), v4Pool("10.0.0.16", "10.0.0.23")}
+				ipPools = append(ipPools, v4Pool("10.0.1.0", "10.0.1.255"))
+			})
+			It("should allocate from the next pool", func() {
+				Expect(ok).To(BeTrue())
+				Expect(ipPool).To(Equal(v4Pool("10.0.1.0", "10.0.1.7")))
+			})
+		})
+
+		When("there is no room left", func() {
+			BeforeEach(func() {
+				size = 32
+			})
+			It("should fail", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the pools are IPv6", func() {
+			BeforeEach(func() {
+				ipPools = []akoov1alpha1.IPPool{{Start: "2001:db8::1", End: "2001:db8::ff", Type: "V6"}}
+				allocated = []akoov1alpha1.IPPool{{Start: "2001:db8::10", End: "2001:db8::1f", Type: "V6"}}
+				size = 16
+			})
+			It("should allocate an aligned IPv6 range", func() {
+				Expect(ok).To(BeTrue())
+				Expect(ipPool).To(Equal(akoov1alpha1.IPPool{Start: "2001:db8::20", End: "2001:db8::2f", Type: "V6"}))
+			})
+		})
+	})
+
+	Context("IsValidClusterIPPool", func() {
+		ipPools := []akoov1alpha1.IPPool{v4Pool("10.0.0.1", "10.0.0.30")}
+
+		It("should accept an aligned range of the size within the pools", func() {
+			Expect(akodeploymentconfig.IsValidClusterIPPool(v4Pool("10.0.0.8", "10.0.0.15"), ipPools, nil, 8)).To(BeTrue())
+		})
+		It("should refuse a range of another size", func() {
+			Expect(akodeploymentconfig.IsValidClusterIPPool(v4Pool("10.0.0.8", "10.0.0.15"), ipPools, nil, 4)).To(BeFalse())
+		})
+		It("should refuse an unaligned range", func() {
+			Expect(akodeploymentconfig.IsValidClusterIPPool(v4Pool("10.0.0.4", "10.0.0.11"), ipPools, nil, 8)).To(BeFalse())
+		})
+		It("should refuse a range out of the pools", func() {
+			Expect(akodeploymentconfig.IsValidClusterIPPool(v4Pool("10.0.0.24", "10.0.0.31"), ipPools, nil, 8)).To(BeFalse())
+		})
+		It("should refuse a range overlapping another cluster's", func() {
+			Expect(akodeploymentconfig.IsValidClusterIPPool(v4Pool("10.0.0.8", "10.0.0.15"), ipPools,
+				[]akoov1alpha1.IPPool{v4Pool("10.0.0.12", "10.0.0.15")}, 8)).To(BeFalse())
+		})
+	})
+
+	Context("CarvedIPPools", func() {
+		ipPools := []akoov1alpha1.IPPool{v4Pool("10.0.0.1", "10.0.0.30")}
+
+		It("should keep the pools as they are without sub-pools", func() {
+			Expect(akodeploymentconfig.CarvedIPPools(ipPools, nil)).To(Equal(ipPools))
+		})
+		It("should carve the sub-pools out of the pools", func() {
+			Expect(akodeploymentconfig.CarvedIPPools(ipPools, []akoov1alpha1.IPPool{
+				v4Pool("10.0.0.16", "10.0.0.23"), v4Pool("10.0.0.4", "10.0.0.7"), v4Pool("10.1.0.0", "10.1.0.3"),
+			})).To(Equal([]akoov1alpha1.IPPool{
+				v4Pool("10.0.0.1", "10.0.0.3"),
+				v4Pool("10.0.0.8", "10.0.0.15"),
+				v4Pool("10.0.0.24", "10.0.0.30"),
+				v4Pool("10.0.0.4", "10.0.0.7"),
+				v4Pool("10.0.0.16", "10.0.0.23"),
+			}))
+		})
+		It("should put each sub-pool in a subnet of its own", func() {
+			mask := int32(24)
+			network := &models.Network{ConfiguredSubnets: []*models.Subnet{{
+				Prefix:         &models.IPAddrPrefix{IPAddr: akodeploymentconfig.GetAddr("10.0.0.0", "V4"), Mask: &mask},
+				StaticIPRanges: akodeploymentconfig.CreateStaticRangeFromIPPools(ipPools),
+			}}}
+			carved := akodeploymentconfig.CarvedIPPools(ipPools, []akoov1alpha1.IPPool{v4Pool("10.0.0.4", "10.0.0.7")})
+			modified, stillOwned, err := akodeploymentconfig.EnsureAviNetworkSubnets(network,
+				[]string{"10.0.0.0/24", "10.0.0.4/30"}, carved, ipPools, log.Log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			Expect(stillOwned).To(ConsistOf(carved))
+			Expect(network.ConfiguredSubnets).To(HaveLen(2))
+			Expect(ipPoolsOf(network.ConfiguredSubnets[0].StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{
+				v4Pool("10.0.0.1", "10.0.0.3"), v4Pool("10.0.0.8", "10.0.0.30"),
+			}))
+			Expect(*network.ConfiguredSubnets[1].Prefix.Mask).To(Equal(int32(30)))
+			Expect(ipPoolsOf(network.ConfiguredSubnets[1].StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{
+				v4Pool("10.0.0.4", "10.0.0.7"),
+			}))
+		})
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -20,7 +21,8 @@ import (
 func (r *AKODeploymentConfigReconciler) initCluster(log logr.Logger) {
 	// Lazily initialize clusterReconciler
 	if r.ClusterReconciler == nil {
-		r.ClusterReconciler = cluster.NewReconciler(r.Client, r.Log, r.Scheme)
+		r.ClusterReconciler = cluster.NewReconciler(r.Client, r.Log, r.Scheme, r.Recorder)
+		r.ClusterReconciler.ManifestOptions = r.ManifestOptions
 		log.Info("Cluster reconciler initialized")
 	}
 }
@@ -37,12 +39,16 @@ func (r *AKODeploymentConfigReconciler) reconcileClusters(
 
 	return phases.ReconcileClustersPhases(ctx, r.Client, log, obj,
 		[]phases.ReconcileClusterPhase{
-			r.addClusterFinalizer,
-			r.ClusterReconciler.ReconcileAddonSecret,
+			phases.NamedCluster("addClusterFinalizer", r.addClusterFinalizer),
+			phases.NamedCluster("reconcileClusterIPPool", r.reconcileClusterIPPool),
+			phases.NamedCluster("reconcileClusterServiceEngineGroup", r.reconcileClusterServiceEngineGroup),
+			phases.NamedCluster("ReconcileDelivery", r.ClusterReconciler.ReconcileDelivery),
 		},
 		[]phases.ReconcileClusterPhase{
-			r.ClusterReconciler.ReconcileAddonSecretDelete,
-			r.ClusterReconciler.ReconcileDelete,
+			phases.NamedCluster("ReconcileDeliveryDelete", r.ClusterReconciler.ReconcileDeliveryDelete),
+			phases.NamedCluster("ReconcileDelete", r.ClusterReconciler.ReconcileDelete),
+			phases.NamedCluster("reconcileClusterIPPoolDelete", r.reconcileClusterIPPoolDelete),
+			phases.NamedCluster("reconcileClusterServiceEngineGroupDelete", r.reconcileClusterServiceEngineGroupDelete),
 		},
 	)
 }
@@ -59,15 +65,19 @@ func (r *AKODeploymentConfigReconciler) reconcileClustersDelete(
 
 	return phases.ReconcileClustersPhases(ctx, r.Client, log, obj,
 		// When AKODeploymentConfig is being deleted and the target
-		// cluster is in normal state, remove the label and finalizer to
-		// stop managing it
+		// cluster is in normal state, remove AKO, then the label and
+		// finalizer to stop managing it
 		[]phases.ReconcileClusterPhase{
-			r.removeClusterFinalizer,
-			r.ClusterReconciler.ReconcileAddonSecretDelete,
+			phases.NamedCluster("ReconcileDeliveryDelete", r.ClusterReconciler.ReconcileDeliveryDelete),
+			phases.NamedCluster("removeClusterFinalizer", r.removeClusterFinalizer),
+			phases.NamedCluster("reconcileClusterIPPoolDelete", r.reconcileClusterIPPoolDelete),
+			phases.NamedCluster("reconcileClusterServiceEngineGroupDelete", r.reconcileClusterServiceEngineGroupDelete),
 		},
 		[]phases.ReconcileClusterPhase{
-			r.ClusterReconciler.ReconcileAddonSecretDelete,
-			r.ClusterReconciler.ReconcileDelete,
+			phases.NamedCluster("ReconcileDeliveryDelete", r.ClusterReconciler.ReconcileDeliveryDelete),
+			phases.NamedCluster("ReconcileDelete", r.ClusterReconciler.ReconcileDelete),
+			phases.NamedCluster("reconcileClusterIPPoolDelete", r.reconcileClusterIPPoolDelete),
+			phases.NamedCluster("reconcileClusterServiceEngineGroupDelete", r.reconcileClusterServiceEngineGroupDelete),
 		},
 	)
 }
@@ -90,13 +100,18 @@ func (r *AKODeploymentConfigReconciler) addClusterFinalizer(
 
 // removeClusterFinalizer is a reconcileClusterPhase. It removes the AVI
 // finalizer from a Cluster. This can only be called when the cluster is not in
-// deletion state and AKODeploymentConfig is being deleted.
+// deletion state and AKODeploymentConfig is being deleted. The finalizer is
+// kept while AKO cleans up before its removal, see ReconcileDeliveryDelete.
 func (r *AKODeploymentConfigReconciler) removeClusterFinalizer(
 	_ context.Context,
 	log logr.Logger,
 	cluster *clusterv1.Cluster,
 	_ *akoov1alpha1.AKODeploymentConfig,
 ) (ctrl.Result, error) {
+	if conditions.IsFalse(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition) {
+		log.Info("Keep the finalizer of the cluster until AKO finishes its cleanup", "finalizer", akoov1alpha1.ClusterFinalizer)
+		return ctrl.Result{}, nil
+	}
 	if ctrlutil.ContainsFinalizer(cluster, akoov1alpha1.ClusterFinalizer) {
 		log.Info("Removing finalizer from cluster", "finalizer", akoov1alpha1.ClusterFinalizer)
 	}
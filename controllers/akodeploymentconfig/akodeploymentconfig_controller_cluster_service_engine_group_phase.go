@@ -0,0 +1,289 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+// clusterServiceEngineGroupRequeueAfter is how long until the deletion of a
+// Service Engine Group which still holds Virtual Services is retried
+const clusterServiceEngineGroupRequeueAfter = time.Minute
+
+// ClusterServiceEngineGroupName returns the name of the Service Engine Group
+// dedicated to the Cluster. The namespace is followed by an underscore, which
+// Kubernetes names can't hold, so that no two clusters share a name.
+func ClusterServiceEngineGroupName(cluster *clusterv1.Cluster) string {
+	return cluster.Namespace + "_" + cluster.Name + "-seg"
+}
+
+// reconcileClusterServiceEngineGroup is a reconcileClusterPhase. When the
+// AKODeploymentConfig dedicates a Service Engine Group to each workload
+// cluster, it clones the Service Engine Group of the AKODeploymentConfig for
+// the Cluster and records it on the Cluster, where the add-on secret picks it
+// up. Once it's turned off, the group is deleted when AKO moved its Virtual
+// Services back to the Service Engine Group of the AKODeploymentConfig.
+func (r *AKODeploymentConfigReconciler) reconcileClusterServiceEngineGroup(
+	_ context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	if cluster.Namespace == akoov1alpha1.TKGSystemNamespace {
+		return res, nil
+	}
+	if !adc.Spec.ServiceEngineGroupPerCluster {
+		return r.deleteClusterServiceEngineGroup(log, cluster, adc)
+	}
+
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+
+	// the groups created before the naming changed keep their name
+	name := ClusterServiceEngineGroupName(cluster)
+	if entry := clusterServiceEngineGroup(adc, cluster.Namespace, cluster.Name); entry != nil {
+		name = entry.ServiceEngineGroup
+	}
+	log = log.WithValues("serviceEngineGroup", name)
+
+	seg, err := aviClient.ServiceEngineGroupGetByName(name, adc.Spec.CloudName)
+	switch {
+	case err == nil:
+		// the template applies to the dedicated groups as well
+		if adc.Spec.ServiceEngineGroupTemplate != nil && ApplyServiceEngineGroupTemplate(seg, adc.Spec.ServiceEngineGroupTemplate) {
+			log.Info("Service engine group drifted from the template, updating")
+			if _, err := aviClient.ServiceEngineGroupUpdate(seg); err != nil {
+				log.Error(err, "Failed to update service engine group")
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.ServiceEngineGroupFailedReason,
+					"Failed to update Service Engine Group %s: %v", name, err)
+				return res, err
+			}
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupUpdatedReason,
+				"Service Engine Group %s updated to match the template", name)
+		}
+	case aviclient.IsAviObjectNonExistentError(err):
+		source, err := aviClient.ServiceEngineGroupGetByName(adc.Spec.ServiceEngineGroup, adc.Spec.CloudName)
+		if err != nil {
+			log.Error(err, "Failed to get the service engine group to clone", "cloneFrom", adc.Spec.ServiceEngineGroup)
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.ServiceEngineGroupFailedReason,
+				"Failed to get Service Engine Group %s to clone: %v", adc.Spec.ServiceEngineGroup, err)
+			return res, err
+		}
+		seg = CloneServiceEngineGroup(source, name)
+		if adc.Spec.ServiceEngineGroupTemplate != nil {
+			ApplyServiceEngineGroupTemplate(seg, adc.Spec.ServiceEngineGroupTemplate)
+		}
+		log.Info("Creating service engine group", "cloneFrom", adc.Spec.ServiceEngineGroup)
+		if _, err := aviClient.ServiceEngineGroupCreate(seg); err != nil {
+			log.Error(err, "Failed to create service engine group")
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.ServiceEngineGroupFailedReason,
+				"Failed to create Service Engine Group %s: %v", name, err)
+			return res, err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupCreatedReason,
+			"Service Engine Group %s created from %s", name, adc.Spec.ServiceEngineGroup)
+	default:
+		log.Error(err, "Failed to get service engine group")
+		return res, err
+	}
+
+	setClusterServiceEngineGroup(adc, cluster, name)
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[akoov1alpha1.AviClusterServiceEngineGroupAnnotation] = name
+	delete(cluster.Annotations, akoov1alpha1.AviClusterServiceEngineGroupInUseAnnotation)
+	return res, nil
+}
+
+// reconcileClusterServiceEngineGroupDelete is a reconcileClusterPhase. It
+// deletes the Service Engine Group dedicated to the Cluster once AKO removed
+// the Virtual Services placed on it: once AviResourceCleanupSucceeded is true
+// for a deleted Cluster, once AKO is removed from a Cluster which stays.
+func (r *AKODeploymentConfigReconciler) reconcileClusterServiceEngineGroupDelete(
+	_ context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	cleanedUp := conditions.IsTrue(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition)
+	if cluster.DeletionTimestamp.IsZero() {
+		cleanedUp = !conditions.IsFalse(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition)
+	}
+	if entry := clusterServiceEngineGroup(adc, cluster.Namespace, cluster.Name); entry != nil && !cleanedUp {
+		log.Info("Wait until AVI resource deletion for cluster finishes before deleting its service engine group",
+			"serviceEngineGroup", entry.ServiceEngineGroup)
+		return res, nil
+	}
+	return r.deleteClusterServiceEngineGroup(log, cluster, adc)
+}
+
+// deleteClusterServiceEngineGroup deletes the Service Engine Group recorded
+// for the Cluster and forgets it. Avi refuses to delete a group which still
+// holds Virtual Services, the deletion is then retried after
+// clusterServiceEngineGroupRequeueAfter until AKO moved or removed them.
+func (r *AKODeploymentConfigReconciler) deleteClusterServiceEngineGroup(
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	entry := clusterServiceEngineGroup(adc, cluster.Namespace, cluster.Name)
+	if entry == nil {
+		return res, nil
+	}
+
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+	if err := deleteServiceEngineGroup(aviClient, adc.Spec.CloudName, entry.ServiceEngineGroup); err != nil {
+		if aviclient.IsAviObjectInUseError(err) {
+			log.Info("Service engine group still holds virtual services, requeue", "serviceEngineGroup", entry.ServiceEngineGroup)
+			// report it once, not on every retry
+			if _, ok := cluster.Annotations[akoov1alpha1.AviClusterServiceEngineGroupInUseAnnotation]; !ok {
+				r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupInUseReason,
+					"Waiting for AKO to move the Virtual Services off Service Engine Group %s before deleting it", entry.ServiceEngineGroup)
+				if cluster.Annotations == nil {
+					cluster.Annotations = map[string]string{}
+				}
+				cluster.Annotations[akoov1alpha1.AviClusterServiceEngineGroupInUseAnnotation] = ""
+			}
+			res.RequeueAfter = clusterServiceEngineGroupRequeueAfter
+			return res, nil
+		}
+		log.Error(err, "Failed to delete service engine group", "serviceEngineGroup", entry.ServiceEngineGroup)
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.ServiceEngineGroupFailedReason,
+			"Failed to delete Service Engine Group %s: %v", entry.ServiceEngineGroup, err)
+		return res, err
+	}
+	log.Info("Deleted service engine group", "serviceEngineGroup", entry.ServiceEngineGroup)
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupDeletedReason,
+		"Service Engine Group %s deleted", entry.ServiceEngineGroup)
+	removeClusterServiceEngineGroup(adc, cluster.Namespace, cluster.Name)
+	delete(cluster.Annotations, akoov1alpha1.AviClusterServiceEngineGroupAnnotation)
+	delete(cluster.Annotations, akoov1alpha1.AviClusterServiceEngineGroupInUseAnnotation)
+	return res, nil
+}
+
+// reconcileClusterServiceEngineGroups deletes the dedicated Service Engine
+// Groups left behind by clusters which are gone, e.g. because the Service
+// Engine Group still had Service Engines when its Cluster was deleted.
+// It's a reconcilePhase function
+func (r *AKODeploymentConfigReconciler) reconcileClusterServiceEngineGroups(
+	ctx context.Context,
+	log logr.Logger,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	if len(adc.Status.ClusterServiceEngineGroups) == 0 {
+		return res, nil
+	}
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+
+	var kept []akoov1alpha1.ClusterServiceEngineGroup
+	var errs []error
+	for _, entry := range adc.Status.ClusterServiceEngineGroups {
+		err := r.Client.Get(ctx, client.ObjectKey{Name: entry.Name, Namespace: entry.Namespace}, &clusterv1.Cluster{})
+		if !apierrors.IsNotFound(err) {
+			// the Cluster is still around, its AKO may still use the group
+			kept = append(kept, entry)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := deleteServiceEngineGroup(aviClient, adc.Spec.CloudName, entry.ServiceEngineGroup); err != nil {
+			log.Error(err, "Failed to delete service engine group of a deleted cluster",
+				"cluster", entry.Namespace+"/"+entry.Name, "serviceEngineGroup", entry.ServiceEngineGroup)
+			kept = append(kept, entry)
+			errs = append(errs, err)
+			continue
+		}
+		log.Info("Deleted service engine group of a deleted cluster",
+			"cluster", entry.Namespace+"/"+entry.Name, "serviceEngineGroup", entry.ServiceEngineGroup)
+		r.Recorder.Eventf(adc, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupDeletedReason,
+			"Service Engine Group %s of deleted cluster %s/%s deleted", entry.ServiceEngineGroup, entry.Namespace, entry.Name)
+	}
+	adc.Status.ClusterServiceEngineGroups = kept
+	return res, kerrors.NewAggregate(errs)
+}
+
+// deleteServiceEngineGroup deletes the Service Engine Group of the cloud, a
+// group which is already gone is not an error
+func deleteServiceEngineGroup(aviClient aviclient.Client, cloudName, name string) error {
+	seg, err := aviClient.ServiceEngineGroupGetByName(name, cloudName)
+	if err != nil {
+		if aviclient.IsAviObjectNonExistentError(err) {
+			return nil
+		}
+		return err
+	}
+	return aviClient.ServiceEngineGroupDelete(ptr.Deref(seg.UUID, ""))
+}
+
+// clusterServiceEngineGroup returns the Service Engine Group recorded for the
+// Cluster, nil when there's none
+func clusterServiceEngineGroup(adc *akoov1alpha1.AKODeploymentConfig, namespace, name string) *akoov1alpha1.ClusterServiceEngineGroup {
+	for i := range adc.Status.ClusterServiceEngineGroups {
+		if adc.Status.ClusterServiceEngineGroups[i].Namespace == namespace && adc.Status.ClusterServiceEngineGroups[i].Name == name {
+			return &adc.Status.ClusterServiceEngineGroups[i]
+		}
+	}
+	return nil
+}
+
+// setClusterServiceEngineGroup records the Service Engine Group of the Cluster
+func setClusterServiceEngineGroup(adc *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, seg string) {
+	if entry := clusterServiceEngineGroup(adc, cluster.Namespace, cluster.Name); entry != nil {
+		entry.ServiceEngineGroup = seg
+		return
+	}
+	adc.Status.ClusterServiceEngineGroups = append(adc.Status.ClusterServiceEngineGroups, akoov1alpha1.ClusterServiceEngineGroup{
+		Name:               cluster.Name,
+		Namespace:          cluster.Namespace,
+		ServiceEngineGroup: seg,
+	})
+}
+
+// removeClusterServiceEngineGroup forgets the Service Engine Group of the
+// Cluster
+func removeClusterServiceEngineGroup(adc *akoov1alpha1.AKODeploymentConfig, namespace, name string) {
+	var kept []akoov1alpha1.ClusterServiceEngineGroup
+	for _, entry := range adc.Status.ClusterServiceEngineGroups {
+		if entry.Namespace != namespace || entry.Name != name {
+			kept = append(kept, entry)
+		}
+	}
+	adc.Status.ClusterServiceEngineGroups = kept
+}
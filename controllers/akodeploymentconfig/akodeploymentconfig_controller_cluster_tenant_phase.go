@@ -0,0 +1,290 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/user"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+// clusterTenantRequeueAfter is how long until the tenants of the deleted
+// clusters which still hold objects are checked again
+const clusterTenantRequeueAfter = time.Minute
+
+// ClusterTenantDescription returns the description of the tenant created for
+// the Cluster, an existing tenant is only adopted when it carries it
+func ClusterTenantDescription(namespace, name string) string {
+	return fmt.Sprintf("Tenant of cluster %s/%s, managed by ako-operator", namespace, name)
+}
+
+// NewClusterTenant returns the tenant to create for the Cluster
+func NewClusterTenant(name string, cluster *clusterv1.Cluster, settings akoov1alpha1.AVITenantConfigSettings) *models.Tenant {
+	return &models.Tenant{
+		Name:        ptr.To(name),
+		Description: ptr.To(ClusterTenantDescription(cluster.Namespace, cluster.Name)),
+		ConfigSettings: &models.TenantConfiguration{
+			SeInProviderContext:      settings.SEInProviderContext,
+			TenantAccessToProviderSe: settings.TenantAccessToProviderSE,
+			TenantVrf:                settings.TenantVRF,
+		},
+	}
+}
+
+// reconcileClusterTenant is a reconcileClusterPhase. When the
+// AKODeploymentConfig dedicates an Avi tenant to each workload cluster, it
+// creates the tenant of the Cluster and records it on the Cluster, where the
+// Avi user and the add-on secret pick it up.
+func (r *AKODeploymentConfigReconciler) reconcileClusterTenant(
+	_ context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	perCluster := adc.Spec.Tenant.PerCluster
+	if perCluster == nil || cluster.Namespace == akoov1alpha1.TKGSystemNamespace {
+		return res, nil
+	}
+
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+
+	// the tenant keeps its name when the template changes, AKO of the
+	// Cluster can't move to another tenant
+	name := cluster.Annotations[akoov1alpha1.AviClusterTenantAnnotation]
+	if name == "" {
+		var err error
+		if name, err = perCluster.TenantName(cluster.ObjectMeta); err != nil {
+			log.Error(err, "Failed to render the tenant name")
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviTenantFailedReason,
+				"Failed to render the name of the Avi tenant: %v", err)
+			return res, err
+		}
+	}
+	log = log.WithValues("tenant", name)
+
+	tenant, err := aviClient.TenantGetByName(name)
+	switch {
+	case err == nil:
+		if ptr.Deref(tenant.Description, "") != ClusterTenantDescription(cluster.Namespace, cluster.Name) {
+			err := fmt.Errorf("tenant %s already exists and doesn't belong to cluster %s/%s", name, cluster.Namespace, cluster.Name)
+			log.Error(err, "Refusing to use an existing tenant")
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviTenantFailedReason,
+				"Avi tenant %s already exists and doesn't belong to the cluster", name)
+			return res, err
+		}
+	case aviclient.IsAviObjectNonExistentError(err):
+		log.Info("Creating tenant")
+		if _, err := aviClient.TenantCreate(NewClusterTenant(name, cluster, perCluster.ConfigSettings)); err != nil {
+			log.Error(err, "Failed to create tenant")
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviTenantFailedReason,
+				"Failed to create Avi tenant %s: %v", name, err)
+			return res, err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviTenantCreatedReason,
+			"Avi tenant %s created", name)
+	default:
+		log.Error(err, "Failed to get tenant")
+		return res, err
+	}
+
+	setClusterTenant(adc, cluster, name)
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[akoov1alpha1.AviClusterTenantAnnotation] = name
+	return res, nil
+}
+
+// reconcileClusterTenantDelete is a reconcileClusterPhase. It deletes the
+// tenant dedicated to the deleted Cluster once AKO removed its objects and
+// the Avi user of the Cluster is gone, i.e. once AviResourceCleanupSucceeded
+// and AviUserCleanupSucceeded are true. A tenant which still holds objects is
+// left to reconcileClusterTenants.
+func (r *AKODeploymentConfigReconciler) reconcileClusterTenantDelete(
+	_ context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	entry := clusterTenant(adc, cluster.Namespace, cluster.Name)
+	if entry == nil {
+		return res, nil
+	}
+	log = log.WithValues("tenant", entry.Tenant)
+	if !conditions.IsTrue(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition) ||
+		!conditions.IsTrue(cluster, akoov1alpha1.AviUserCleanupSucceededCondition) {
+		log.Info("Wait until AVI resource and user deletion for cluster finishes before deleting its tenant")
+		return res, nil
+	}
+
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+	deleted, err := DeleteClusterTenant(aviClient, entry.Tenant, user.RoleProfileRoleName(adc.Spec.RoleProfile))
+	if err != nil {
+		log.Error(err, "Failed to delete tenant")
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviTenantFailedReason,
+			"Failed to delete Avi tenant %s: %v", entry.Tenant, err)
+		return res, err
+	}
+	if !deleted {
+		log.Info("Tenant still holds objects, keep it until they're gone")
+		return res, nil
+	}
+	log.Info("Deleted tenant")
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviTenantDeletedReason,
+		"Avi tenant %s deleted", entry.Tenant)
+	removeClusterTenant(adc, cluster.Namespace, cluster.Name)
+	delete(cluster.Annotations, akoov1alpha1.AviClusterTenantAnnotation)
+	return res, nil
+}
+
+// reconcileClusterTenants deletes the tenants left behind by clusters which
+// are gone, e.g. because the tenant still held objects when its Cluster was
+// deleted.
+// It's a reconcilePhase function
+func (r *AKODeploymentConfigReconciler) reconcileClusterTenants(
+	ctx context.Context,
+	log logr.Logger,
+	adc *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{}
+
+	if len(adc.Status.ClusterTenants) == 0 {
+		return res, nil
+	}
+	aviClient := r.AviClients.ClientFor(adc.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return res, errors.New("AVI client not initialized")
+	}
+
+	var kept []akoov1alpha1.ClusterTenant
+	var errs []error
+	for _, entry := range adc.Status.ClusterTenants {
+		err := r.Client.Get(ctx, client.ObjectKey{Name: entry.Name, Namespace: entry.Namespace}, &clusterv1.Cluster{})
+		if !apierrors.IsNotFound(err) {
+			// the Cluster is still around, its AKO may still use the tenant
+			kept = append(kept, entry)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		deleted, err := DeleteClusterTenant(aviClient, entry.Tenant, user.RoleProfileRoleName(adc.Spec.RoleProfile))
+		if err != nil {
+			log.Error(err, "Failed to delete tenant of a deleted cluster",
+				"cluster", entry.Namespace+"/"+entry.Name, "tenant", entry.Tenant)
+			kept = append(kept, entry)
+			errs = append(errs, err)
+			continue
+		}
+		if !deleted {
+			kept = append(kept, entry)
+			res.RequeueAfter = clusterTenantRequeueAfter
+			continue
+		}
+		log.Info("Deleted tenant of a deleted cluster",
+			"cluster", entry.Namespace+"/"+entry.Name, "tenant", entry.Tenant)
+		r.Recorder.Eventf(adc, corev1.EventTypeNormal, akoov1alpha1.AviTenantDeletedReason,
+			"Avi tenant %s of deleted cluster %s/%s deleted", entry.Tenant, entry.Namespace, entry.Name)
+	}
+	adc.Status.ClusterTenants = kept
+	return res, kerrors.NewAggregate(errs)
+}
+
+// DeleteClusterTenant deletes the role the Avi users of the Cluster were
+// bound to in the tenant, then the tenant when it holds no other objects, and
+// returns whether it's gone. A tenant which is already gone is not an error.
+func DeleteClusterTenant(aviClient aviclient.Client, name, roleName string) (bool, error) {
+	tenant, err := aviClient.TenantGetByName(name)
+	if err != nil {
+		if aviclient.IsAviObjectNonExistentError(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	role, err := aviClient.RoleGetByName(roleName, session.SetOptTenant(name))
+	switch {
+	case err == nil:
+		// the roles of the admin tenant are visible in every tenant
+		if aviclient.GetUUIDFromRef(ptr.Deref(role.TenantRef, "")) == ptr.Deref(tenant.UUID, "") {
+			if err := aviClient.RoleDelete(ptr.Deref(role.UUID, ""), session.SetOptTenant(name)); err != nil {
+				return false, err
+			}
+		}
+	case !aviclient.IsAviRoleNonExistentError(err):
+		return false, err
+	}
+	count, err := aviClient.TenantObjectCount(name)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	return true, aviClient.TenantDelete(ptr.Deref(tenant.UUID, ""))
+}
+
+// clusterTenant returns the tenant recorded for the Cluster, nil when there's
+// none
+func clusterTenant(adc *akoov1alpha1.AKODeploymentConfig, namespace, name string) *akoov1alpha1.ClusterTenant {
+	for i := range adc.Status.ClusterTenants {
+		if adc.Status.ClusterTenants[i].Namespace == namespace && adc.Status.ClusterTenants[i].Name == name {
+			return &adc.Status.ClusterTenants[i]
+		}
+	}
+	return nil
+}
+
+// setClusterTenant records the tenant of the Cluster
+func setClusterTenant(adc *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, tenant string) {
+	if entry := clusterTenant(adc, cluster.Namespace, cluster.Name); entry != nil {
+		entry.Tenant = tenant
+		return
+	}
+	adc.Status.ClusterTenants = append(adc.Status.ClusterTenants, akoov1alpha1.ClusterTenant{
+		Name:      cluster.Name,
+		Namespace: cluster.Namespace,
+		Tenant:    tenant,
+	})
+}
+
+// removeClusterTenant forgets the tenant of the Cluster
+func removeClusterTenant(adc *akoov1alpha1.AKODeploymentConfig, namespace, name string) {
+	var kept []akoov1alpha1.ClusterTenant
+	for _, entry := range adc.Status.ClusterTenants {
+		if entry.Namespace != namespace || entry.Name != name {
+			kept = append(kept, entry)
+		}
+	}
+	adc.Status.ClusterTenants = kept
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/test/aviserver"
+)
+
+func unitTestClusterTenants() {
+	Context("NewClusterTenant", func() {
+		It("should describe the cluster and carry the config settings", func() {
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: "dev"}}
+			tenant := akodeploymentconfig.NewClusterTenant("dev-workload", cluster, akoov1alpha1.AVITenantConfigSettings{
+				TenantVRF: ptr.To(true),
+			})
+			Expect(tenant).To(Equal(&models.Tenant{
+				Name:        ptr.To("dev-workload"),
+				Description: ptr.To(akodeploymentconfig.ClusterTenantDescription("dev", "workload")),
+				ConfigSettings: &models.TenantConfiguration{
+					TenantVrf: ptr.To(true),
+				},
+			}))
+		})
+	})
+	Context("DeleteClusterTenant", func() {
+		var (
+			server    *aviserver.Server
+			aviClient aviclient.Client
+			tenant    *models.Tenant
+		)
+		BeforeEach(func() {
+			server = aviserver.NewServer()
+			var err error
+			aviClient, err = aviclient.NewAviClient(server.ClientConfig(), "")
+			Expect(err).NotTo(HaveOccurred())
+			tenant, err = aviClient.TenantCreate(&models.Tenant{Name: ptr.To("dev-workload")})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = aviClient.RoleCreate(&models.Role{Name: ptr.To("ako-essential-role"), TenantRef: tenant.URL})
+			Expect(err).NotTo(HaveOccurred())
+			// the roles and certificates of the admin tenant are visible in
+			// every tenant
+			_, err = server.Create("role", &models.Role{Name: ptr.To("System-Admin")})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = server.Create("sslkeyandcertificate", &models.SSLKeyAndCertificate{Name: ptr.To("System-Default-Cert")})
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+		It("should delete the role and the tenant", func() {
+			deleted, err := akodeploymentconfig.DeleteClusterTenant(aviClient, "dev-workload", "ako-essential-role")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+			_, err = aviClient.TenantGetByName("dev-workload")
+			Expect(aviclient.IsAviObjectNonExistentError(err)).To(BeTrue())
+			Expect(server.Len("role")).To(Equal(1))
+			Expect(server.Len("sslkeyandcertificate")).To(Equal(1))
+		})
+		It("should keep the tenant while AKO objects are left in it", func() {
+			_, err := server.Create("virtualservice", &models.VirtualService{Name: ptr.To("vs"), TenantRef: tenant.URL})
+			Expect(err).NotTo(HaveOccurred())
+			deleted, err := akodeploymentconfig.DeleteClusterTenant(aviClient, "dev-workload", "ako-essential-role")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+			_, err = aviClient.TenantGetByName("dev-workload")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+}
@@ -5,6 +5,7 @@ package akodeploymentconfig_test
 
 import (
 	"os"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -23,6 +24,7 @@ import (
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
 	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/test/builder"
@@ -224,6 +226,17 @@ func intgTestAkoDeploymentConfigController() {
 		}, "30s", "3s").Should(BeTrue())
 	}
 
+	ensureAKODeploymentConfigConditionMatchExpectation := func(key client.ObjectKey, conditionType clusterv1.ConditionType, status corev1.ConditionStatus, reason string) {
+		Eventually(func() bool {
+			obj := &akoov1alpha1.AKODeploymentConfig{}
+			if err := ctx.Client.Get(ctx.Context, key, obj); err != nil {
+				return false
+			}
+			condition := conditions.Get(obj, conditionType)
+			return condition != nil && condition.Status == status && condition.Reason == reason
+		}, "30s", "3s").Should(BeTrue())
+	}
+
 	ensureSubnetMatchExpectation := func(newIPAddrEnd string, expect bool) {
 		Eventually(func() bool {
 			found := false
@@ -242,6 +255,18 @@ func intgTestAkoDeploymentConfigController() {
 		}).Should(BeTrue())
 	}
 
+	// copyNetwork returns a copy of network the reconciler can modify
+	copyNetwork := func(network *models.Network) *models.Network {
+		copied := *network
+		copied.ConfiguredSubnets = []*models.Subnet{}
+		for _, subnet := range network.ConfiguredSubnets {
+			s := *subnet
+			s.StaticIPRanges = append([]*models.StaticIPRange{}, subnet.StaticIPRanges...)
+			copied.ConfiguredSubnets = append(copied.ConfiguredSubnets, &s)
+		}
+		return &copied
+	}
+
 	BeforeEach(func() {
 		ctx = suite.NewIntegrationTestContext()
 		akoDeploymentConfig = staticAkoDeploymentConfig.DeepCopy()
@@ -326,7 +351,7 @@ func intgTestAkoDeploymentConfigController() {
 				},
 			}, nil
 		})
-		ctx.AviClient.Tenant.SetGetTenantFunc(func(uuid string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+		ctx.AviClient.Tenant.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
 			return &models.Tenant{}, nil
 		})
 	})
@@ -438,7 +463,7 @@ func intgTestAkoDeploymentConfigController() {
 					Namespace: cluster.Namespace,
 				}, true)
 
-				//Reconcile -> reconcileNormal -> reconcileClusters(normal phase) -> r.clusterReconciler.ReconcileAddonSecret
+				//Reconcile -> reconcileNormal -> reconcileClusters(normal phase) -> r.clusterReconciler.ReconcileDelivery
 				By("should Reconcile Cluster add-on secret")
 				ensureRuntimeObjectMatchExpectation(client.ObjectKey{
 					Name:      cluster.Name + "-load-balancer-and-ingress-service-addon",
@@ -481,6 +506,38 @@ func intgTestAkoDeploymentConfigController() {
 						It("shouldn't reconcile Network Subnets", func() {
 							ensureSubnetMatchExpectation("10.0.0.10", true)
 						})
+						It("should report the AKODeploymentConfig as ready", func() {
+							key := client.ObjectKey{Name: akoDeploymentConfig.Name}
+							ensureAKODeploymentConfigConditionMatchExpectation(key, akoov1alpha1.AviControllerReachableCondition, corev1.ConditionTrue, "")
+							ensureAKODeploymentConfigConditionMatchExpectation(key, akoov1alpha1.CloudReadyCondition, corev1.ConditionTrue, "")
+							ensureAKODeploymentConfigConditionMatchExpectation(key, akoov1alpha1.DataNetworkSyncedCondition, corev1.ConditionTrue, "")
+							ensureAKODeploymentConfigConditionMatchExpectation(key, clusterv1.ReadyCondition, corev1.ConditionTrue, "")
+						})
+					})
+
+					When("the data network doesn't exist", func() {
+						BeforeEach(func() {
+							ctx.AviClient.Network.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Network, error) {
+								return nil, errors.New("No object of type network with name " + name + " is found")
+							})
+						})
+						It("should report the data network as not synced", func() {
+							key := client.ObjectKey{Name: akoDeploymentConfig.Name}
+							ensureAKODeploymentConfigConditionMatchExpectation(key, akoov1alpha1.DataNetworkSyncedCondition, corev1.ConditionFalse, akoov1alpha1.NetworkNotFoundReason)
+							ensureAKODeploymentConfigConditionMatchExpectation(key, clusterv1.ReadyCondition, corev1.ConditionFalse, akoov1alpha1.NetworkNotFoundReason)
+						})
+					})
+
+					When("the cloud has no IPAM provider", func() {
+						BeforeEach(func() {
+							ctx.AviClient.Cloud.SetGetByNameCloudFunc(func(name string, options ...session.ApiOptionsParams) (*models.Cloud, error) {
+								return &models.Cloud{}, nil
+							})
+						})
+						It("should report the cloud as not ready", func() {
+							ensureAKODeploymentConfigConditionMatchExpectation(client.ObjectKey{Name: akoDeploymentConfig.Name},
+								akoov1alpha1.CloudReadyCondition, corev1.ConditionFalse, akoov1alpha1.CloudIPAMNotConfiguredReason)
+						})
 					})
 
 					When("subnet exists and AKODeploymentConfig's subnet is not contained", func() {
@@ -550,6 +607,480 @@ func intgTestAkoDeploymentConfigController() {
 						})
 					})
 
+					When("VIP networks are configured", func() {
+						var (
+							lock           sync.Mutex
+							networkUpdates map[string]*models.Network
+						)
+						BeforeEach(func() {
+							networkUpdates = map[string]*models.Network{}
+							ctx.AviClient.Network.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Network, error) {
+								return &models.Network{Name: ptr.To(name), URL: ptr.To("10.0.0.1#" + name)}, nil
+							})
+							ctx.AviClient.Network.SetUpdateFn(func(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+								lock.Lock()
+								defer lock.Unlock()
+								networkUpdates[*obj.Name] = obj
+								return obj, nil
+							})
+
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []akoov1alpha1.VIPNetwork{{
+								NetworkName: "public-vip",
+								CIDR:        "10.10.0.0/24",
+								V6CIDR:      "2002::/64",
+								IPPools:     []akoov1alpha1.IPPool{{Start: "10.10.0.10", End: "10.10.0.20", Type: "V4"}},
+							}}
+							updateObjects(adc)
+						})
+						It("should add the subnets of every VIP network", func() {
+							Eventually(func() []string {
+								lock.Lock()
+								defer lock.Unlock()
+								network, ok := networkUpdates["public-vip"]
+								if !ok {
+									return nil
+								}
+								var subnets []string
+								for _, subnet := range network.ConfiguredSubnets {
+									subnets = append(subnets, *subnet.Prefix.IPAddr.Addr)
+									for _, sr := range subnet.StaticIPRanges {
+										subnets = append(subnets, *sr.Range.Begin.Addr+"-"+*sr.Range.End.Addr)
+									}
+								}
+								return subnets
+							}).Should(ConsistOf("10.10.0.0", "10.10.0.10-10.10.0.20", "2002::"))
+						})
+					})
+
+					When("an owned static range leaves the spec", func() {
+						var (
+							lock      sync.Mutex
+							network   *models.Network
+							allocated bool
+						)
+						staticRanges := func() []string {
+							lock.Lock()
+							defer lock.Unlock()
+							var ranges []string
+							for _, subnet := range network.ConfiguredSubnets {
+								for _, sr := range subnet.StaticIPRanges {
+									ranges = append(ranges, *sr.Range.Begin.Addr+"-"+*sr.Range.End.Addr)
+								}
+							}
+							return ranges
+						}
+						setIPPools := func(ipPools ...akoov1alpha1.IPPool) {
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.ExtraConfigs.NetworksConfig.VipNetworkList = []akoov1alpha1.VIPNetwork{{
+								NetworkName: "public-vip",
+								CIDR:        "10.10.0.0/24",
+								IPPools:     ipPools,
+							}}
+							updateObjects(adc)
+						}
+						BeforeEach(func() {
+							allocated = false
+							// the range 10.10.0.1-10.10.0.9 was created by hand
+							network = &models.Network{
+								Name: ptr.To("public-vip"),
+								UUID: ptr.To("network-public-vip"),
+								URL:  ptr.To("10.0.0.1#public-vip"),
+								ConfiguredSubnets: []*models.Subnet{{
+									Prefix: &models.IPAddrPrefix{
+										IPAddr: &models.IPAddr{Addr: ptr.To("10.10.0.0"), Type: ptr.To("V4")},
+										Mask:   ptr.To(int32(24)),
+									},
+									StaticIPRanges: []*models.StaticIPRange{{
+										Range: &models.IPAddrRange{
+											Begin: &models.IPAddr{Addr: ptr.To("10.10.0.1"), Type: ptr.To("V4")},
+											End:   &models.IPAddr{Addr: ptr.To("10.10.0.9"), Type: ptr.To("V4")},
+										},
+									}},
+								}},
+							}
+							ctx.AviClient.Network.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Network, error) {
+								lock.Lock()
+								defer lock.Unlock()
+								if name != "public-vip" {
+									return &models.Network{Name: ptr.To(name), URL: ptr.To("10.0.0.1#" + name)}, nil
+								}
+								return copyNetwork(network), nil
+							})
+							ctx.AviClient.Network.SetUpdateFn(func(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+								lock.Lock()
+								defer lock.Unlock()
+								if *obj.Name == "public-vip" {
+									network = obj
+								}
+								return obj, nil
+							})
+							ctx.AviClient.Network.SetGetRuntimeFn(func(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error) {
+								lock.Lock()
+								defer lock.Unlock()
+								runtime := &models.NetworkRuntime{UUID: ptr.To(uuid)}
+								if allocated {
+									runtime.SubnetRuntime = []*models.SubnetRuntime{{
+										IPRangeRuntimes: []*models.StaticIPRangeRuntime{{
+											AllocatedIps: []*models.StaticIPAllocInfo{{
+												IP: &models.IPAddr{Addr: ptr.To("10.10.0.15"), Type: ptr.To("V4")},
+											}},
+										}},
+									}}
+								}
+								return runtime, nil
+							})
+
+							setIPPools(akoov1alpha1.IPPool{Start: "10.10.0.10", End: "10.10.0.20", Type: "V4"})
+							Eventually(staticRanges, "30s", "3s").Should(ConsistOf("10.10.0.1-10.10.0.9", "10.10.0.10-10.10.0.20"))
+							Eventually(func() []akoov1alpha1.NetworkIPPool {
+								adc := &akoov1alpha1.AKODeploymentConfig{}
+								if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+									return nil
+								}
+								return adc.Status.StaticIPRanges
+							}, "30s", "3s").Should(ContainElement(akoov1alpha1.NetworkIPPool{
+								NetworkName: "public-vip",
+								IPPool:      akoov1alpha1.IPPool{Start: "10.10.0.10", End: "10.10.0.20", Type: "V4"},
+							}))
+						})
+						It("should remove it and keep the range created by hand", func() {
+							setIPPools(akoov1alpha1.IPPool{Start: "10.10.0.30", End: "10.10.0.40", Type: "V4"})
+							Eventually(staticRanges, "30s", "3s").Should(ConsistOf("10.10.0.1-10.10.0.9", "10.10.0.30-10.10.0.40"))
+						})
+						It("should refuse to remove it while IPs are allocated from it", func() {
+							lock.Lock()
+							allocated = true
+							lock.Unlock()
+							setIPPools(akoov1alpha1.IPPool{Start: "10.10.0.30", End: "10.10.0.40", Type: "V4"})
+							ensureAKODeploymentConfigConditionMatchExpectation(client.ObjectKey{Name: akoDeploymentConfig.Name},
+								akoov1alpha1.DataNetworkSyncedCondition, corev1.ConditionFalse, akoov1alpha1.StaticIPRangeInUseConditionReason)
+							Expect(staticRanges()).To(ConsistOf("10.10.0.1-10.10.0.9", "10.10.0.10-10.10.0.20", "10.10.0.30-10.10.0.40"))
+						})
+						It("should report the utilization of the pool and flag it once low", func() {
+							lock.Lock()
+							allocated = true
+							lock.Unlock()
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.VIPPoolLowThreshold = ptr.To(int32(95))
+							updateObjects(adc)
+							Eventually(func() []akoov1alpha1.VIPPoolStatus {
+								adc := &akoov1alpha1.AKODeploymentConfig{}
+								if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+									return nil
+								}
+								return adc.Status.VIPPools
+							}, "30s", "3s").Should(ContainElement(akoov1alpha1.VIPPoolStatus{
+								NetworkName: "public-vip",
+								IPPool:      akoov1alpha1.IPPool{Start: "10.10.0.10", End: "10.10.0.20", Type: "V4"},
+								Total:       11,
+								Used:        1,
+								Free:        10,
+							}))
+							ensureAKODeploymentConfigConditionMatchExpectation(client.ObjectKey{Name: akoDeploymentConfig.Name},
+								akoov1alpha1.VIPPoolLowCondition, corev1.ConditionTrue, akoov1alpha1.VIPPoolLowConditionReason)
+						})
+					})
+
+					When("VIP sub-pools are dedicated to the clusters", func() {
+						setClusterIPPoolSize := func(size int32) {
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.DataNetwork.ClusterIPPoolSize = size
+							updateObjects(adc)
+						}
+						clusterIPPoolAnnotation := func() string {
+							obj := &clusterv1.Cluster{}
+							if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+								return ""
+							}
+							return obj.Annotations[akoov1alpha1.AviClusterIPPoolAnnotation]
+						}
+						BeforeEach(func() {
+							setClusterIPPoolSize(4)
+							// the data network pool is 10.0.0.1-10.0.0.10
+							Eventually(clusterIPPoolAnnotation, "30s", "3s").Should(Equal("10.0.0.4-10.0.0.7"))
+						})
+						It("should configure the sub-pool as a subnet of the data network", func() {
+							Expect(networkUpdate.ConfiguredSubnets).To(ContainElement(&models.Subnet{
+								Prefix: &models.IPAddrPrefix{
+									IPAddr: akodeploymentconfig.GetAddr("10.0.0.4", "V4"),
+									Mask:   ptr.To(int32(30)),
+								},
+								StaticIPRanges: akodeploymentconfig.CreateStaticRangeFromIPPools([]akoov1alpha1.IPPool{
+									{Start: "10.0.0.4", End: "10.0.0.7", Type: "V4"},
+								}),
+							}))
+							ensureSubnetMatchExpectation("10.0.0.3", true)
+							ensureSubnetMatchExpectation("10.0.0.10", true)
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							Expect(adc.Status.ClusterIPPools).To(ConsistOf(akoov1alpha1.ClusterIPPool{
+								Name:      cluster.Name,
+								Namespace: cluster.Namespace,
+								IPPool:    akoov1alpha1.IPPool{Start: "10.0.0.4", End: "10.0.0.7", Type: "V4"},
+							}))
+						})
+						It("should give the sub-pool back once disabled", func() {
+							setClusterIPPoolSize(0)
+							Eventually(clusterIPPoolAnnotation, "30s", "3s").Should(BeEmpty())
+							ensureSubnetMatchExpectation("10.0.0.7", false)
+						})
+						It("should give the sub-pool back once the cluster is deleted", func() {
+							deleteObjects(cluster)
+							Eventually(func() []akoov1alpha1.ClusterIPPool {
+								adc := &akoov1alpha1.AKODeploymentConfig{}
+								if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+									return nil
+								}
+								return adc.Status.ClusterIPPools
+							}, "30s", "3s").Should(BeEmpty())
+						})
+					})
+
+					When("the Service Engine Group is provisioned from a template", func() {
+						var segUpdate *models.ServiceEngineGroup
+						setServiceEngineGroupTemplate := func(template *akoov1alpha1.ServiceEngineGroupTemplate) {
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.ServiceEngineGroupTemplate = template
+							updateObjects(adc)
+						}
+						BeforeEach(func() {
+							segUpdate = nil
+							ctx.AviClient.ServiceEngineGroup.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+								return nil, errors.New("No object of type serviceenginegroup with name " + name + " is found")
+							})
+							ctx.AviClient.ServiceEngineGroup.SetUpdateFn(func(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+								segUpdate = obj
+								return obj, nil
+							})
+						})
+						It("should create the missing Service Engine Group", func() {
+							setServiceEngineGroupTemplate(&akoov1alpha1.ServiceEngineGroupTemplate{
+								HAMode: "HA_MODE_SHARED_PAIR",
+								MaxSEs: ptr.To(int32(4)),
+							})
+							Eventually(func() *int32 {
+								seg, err := ctx.AviClient.ServiceEngineGroupGetByName(akoDeploymentConfig.Spec.ServiceEngineGroup, "")
+								if err != nil {
+									return nil
+								}
+								return seg.MaxSe
+							}, "30s", "3s").Should(Equal(ptr.To(int32(4))))
+							ensureAKODeploymentConfigConditionMatchExpectation(client.ObjectKey{Name: akoDeploymentConfig.Name},
+								akoov1alpha1.ServiceEngineGroupReadyCondition, corev1.ConditionTrue, "")
+						})
+						It("should sync the fields of the template", func() {
+							ctx.AviClient.ServiceEngineGroupCreate(&models.ServiceEngineGroup{
+								Name:       ptr.To(akoDeploymentConfig.Spec.ServiceEngineGroup),
+								MaxSe:      ptr.To(int32(2)),
+								MaxVsPerSe: ptr.To(int32(10)),
+							})
+							setServiceEngineGroupTemplate(&akoov1alpha1.ServiceEngineGroupTemplate{MaxSEs: ptr.To(int32(4))})
+							Eventually(func() *models.ServiceEngineGroup { return segUpdate }, "30s", "3s").ShouldNot(BeNil())
+							Expect(segUpdate.MaxSe).To(Equal(ptr.To(int32(4))))
+							Expect(segUpdate.MaxVsPerSe).To(Equal(ptr.To(int32(10))))
+						})
+						It("should report a missing Service Engine Group to clone", func() {
+							setServiceEngineGroupTemplate(&akoov1alpha1.ServiceEngineGroupTemplate{CloneFrom: "Default-Group"})
+							ensureAKODeploymentConfigConditionMatchExpectation(client.ObjectKey{Name: akoDeploymentConfig.Name},
+								akoov1alpha1.ServiceEngineGroupReadyCondition, corev1.ConditionFalse, akoov1alpha1.ServiceEngineGroupCloneSourceNotFoundReason)
+						})
+					})
+
+					When("a Service Engine Group is dedicated to each cluster", func() {
+						var segName string
+						BeforeEach(func() {
+							segName = akodeploymentconfig.ClusterServiceEngineGroupName(cluster)
+							ctx.AviClient.ServiceEngineGroup.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+								if name == akoDeploymentConfig.Spec.ServiceEngineGroup {
+									return &models.ServiceEngineGroup{Name: ptr.To(name), UUID: ptr.To("serviceenginegroup-1")}, nil
+								}
+								return nil, errors.New("No object of type serviceenginegroup with name " + name + " is found")
+							})
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.ServiceEngineGroupPerCluster = true
+							updateObjects(adc)
+						})
+						It("should record the Service Engine Group of the cluster", func() {
+							Eventually(func() string {
+								obj := &clusterv1.Cluster{}
+								if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+									return ""
+								}
+								return obj.Annotations[akoov1alpha1.AviClusterServiceEngineGroupAnnotation]
+							}, "30s", "3s").Should(Equal(segName))
+							seg, err := ctx.AviClient.ServiceEngineGroupGetByName(segName, "")
+							Expect(err).ShouldNot(HaveOccurred())
+							Expect(*seg.Name).To(Equal(segName))
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							Expect(adc.Status.ClusterServiceEngineGroups).To(ConsistOf(akoov1alpha1.ClusterServiceEngineGroup{
+								Name:               cluster.Name,
+								Namespace:          cluster.Namespace,
+								ServiceEngineGroup: segName,
+							}))
+						})
+						When("it's turned off", func() {
+							var deleted []string
+							BeforeEach(func() {
+								deleted = nil
+								ctx.AviClient.ServiceEngineGroup.SetDeleteFn(func(uuid string, options ...session.ApiOptionsParams) error {
+									deleted = append(deleted, uuid)
+									return nil
+								})
+								Eventually(func() []akoov1alpha1.ClusterServiceEngineGroup {
+									adc := &akoov1alpha1.AKODeploymentConfig{}
+									if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+										return nil
+									}
+									return adc.Status.ClusterServiceEngineGroups
+								}, "30s", "3s").Should(HaveLen(1))
+								adc := &akoov1alpha1.AKODeploymentConfig{}
+								Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+								adc.Spec.ServiceEngineGroupPerCluster = false
+								updateObjects(adc)
+							})
+							It("should delete the Service Engine Group of the cluster", func() {
+								Eventually(func() []akoov1alpha1.ClusterServiceEngineGroup {
+									adc := &akoov1alpha1.AKODeploymentConfig{}
+									if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+										return nil
+									}
+									return adc.Status.ClusterServiceEngineGroups
+								}, "30s", "3s").Should(BeEmpty())
+								Expect(deleted).NotTo(BeEmpty())
+								Eventually(func() string {
+									obj := &clusterv1.Cluster{}
+									if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+										return "unknown"
+									}
+									return obj.Annotations[akoov1alpha1.AviClusterServiceEngineGroupAnnotation]
+								}, "30s", "3s").Should(BeEmpty())
+							})
+						})
+						When("it's turned off while the Service Engine Group still holds Virtual Services", func() {
+							var inUse bool
+							BeforeEach(func() {
+								inUse = true
+								ctx.AviClient.ServiceEngineGroup.SetDeleteFn(func(uuid string, options ...session.ApiOptionsParams) error {
+									if inUse {
+										return errors.New("Cannot delete, object is referred by: ['VirtualService vs-1']")
+									}
+									return nil
+								})
+								Eventually(func() []akoov1alpha1.ClusterServiceEngineGroup {
+									adc := &akoov1alpha1.AKODeploymentConfig{}
+									if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+										return nil
+									}
+									return adc.Status.ClusterServiceEngineGroups
+								}, "30s", "3s").Should(HaveLen(1))
+								adc := &akoov1alpha1.AKODeploymentConfig{}
+								Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+								adc.Spec.ServiceEngineGroupPerCluster = false
+								updateObjects(adc)
+							})
+							It("should wait for the Virtual Services to move before deleting it", func() {
+								Eventually(func() bool {
+									obj := &clusterv1.Cluster{}
+									if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+										return false
+									}
+									_, ok := obj.Annotations[akoov1alpha1.AviClusterServiceEngineGroupInUseAnnotation]
+									return ok
+								}, "30s", "3s").Should(BeTrue())
+								adc := &akoov1alpha1.AKODeploymentConfig{}
+								Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+								Expect(adc.Status.ClusterServiceEngineGroups).To(HaveLen(1))
+
+								inUse = false
+								Eventually(func() []akoov1alpha1.ClusterServiceEngineGroup {
+									adc := &akoov1alpha1.AKODeploymentConfig{}
+									if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+										return nil
+									}
+									return adc.Status.ClusterServiceEngineGroups
+								}, "90s", "3s").Should(BeEmpty())
+								Eventually(func() bool {
+									obj := &clusterv1.Cluster{}
+									if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+										return true
+									}
+									_, ok := obj.Annotations[akoov1alpha1.AviClusterServiceEngineGroupInUseAnnotation]
+									return ok
+								}, "30s", "3s").Should(BeFalse())
+							})
+						})
+						When("the AKODeploymentConfig is deleted", func() {
+							var deleted []string
+							BeforeEach(func() {
+								deleted = nil
+								ctx.AviClient.ServiceEngineGroup.SetDeleteFn(func(uuid string, options ...session.ApiOptionsParams) error {
+									deleted = append(deleted, uuid)
+									return nil
+								})
+								Eventually(func() string {
+									obj := &clusterv1.Cluster{}
+									if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+										return ""
+									}
+									return obj.Annotations[akoov1alpha1.AviClusterServiceEngineGroupAnnotation]
+								}, "30s", "3s").Should(Equal(segName))
+								deleteObjects(akoDeploymentConfig)
+							})
+							It("should delete the Service Engine Group of the cluster which stays", func() {
+								Eventually(func() []string { return deleted }, "30s", "3s").ShouldNot(BeEmpty())
+								Eventually(func() string {
+									obj := &clusterv1.Cluster{}
+									if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+										return "unknown"
+									}
+									return obj.Annotations[akoov1alpha1.AviClusterServiceEngineGroupAnnotation]
+								}, "30s", "3s").Should(BeEmpty())
+							})
+						})
+					})
+
+					When("an Avi tenant is dedicated to each cluster", func() {
+						var tenantName string
+						BeforeEach(func() {
+							tenantName = "avi-" + cluster.Name
+							ctx.AviClient.Tenant.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+								return nil, errors.New("No object of type tenant with name " + name + " is found")
+							})
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							adc.Spec.Tenant.PerCluster = &akoov1alpha1.AVIClusterTenant{NameTemplate: "avi-{{.Name}}"}
+							updateObjects(adc)
+						})
+						It("should record the tenant of the cluster", func() {
+							Eventually(func() string {
+								obj := &clusterv1.Cluster{}
+								if err := getCluster(obj, cluster.Name, cluster.Namespace); err != nil {
+									return ""
+								}
+								return obj.Annotations[akoov1alpha1.AviClusterTenantAnnotation]
+							}, "30s", "3s").Should(Equal(tenantName))
+							tenant, err := ctx.AviClient.TenantGetByName(tenantName)
+							Expect(err).ShouldNot(HaveOccurred())
+							Expect(*tenant.Name).To(Equal(tenantName))
+							Expect(*tenant.Description).To(Equal(akodeploymentconfig.ClusterTenantDescription(cluster.Namespace, cluster.Name)))
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							Expect(ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc)).To(Succeed())
+							Expect(adc.Status.ClusterTenants).To(ConsistOf(akoov1alpha1.ClusterTenant{
+								Name:      cluster.Name,
+								Namespace: cluster.Namespace,
+								Tenant:    tenantName,
+							}))
+						})
+					})
+
 					// Reconcile -> reconcileNormal -> r.reconcileCloudUsableNetwork
 					// No need to test since all the functions in reconcileCloudUsableNetwork are fake functions
 
@@ -694,6 +1225,108 @@ func intgTestAkoDeploymentConfigController() {
 
 				})
 
+				// Reconcile -> reconcileDelete -> r.reconcileNetworkSubnetsDelete, r.reconcileCloudUsableNetworkDelete
+				When("AKODeploymentConfig configured the Avi networks", func() {
+					var (
+						lock           sync.Mutex
+						network        *models.Network
+						usableNetworks []*models.IPAMUsableNetwork
+					)
+					networkName := staticAkoDeploymentConfig.Spec.DataNetwork.Name
+					subnetCount := func() int {
+						lock.Lock()
+						defer lock.Unlock()
+						return len(network.ConfiguredSubnets)
+					}
+					usableNetworkCount := func() int {
+						lock.Lock()
+						defer lock.Unlock()
+						return len(usableNetworks)
+					}
+					BeforeEach(func() {
+						network = &models.Network{
+							Name: ptr.To(networkName),
+							UUID: ptr.To("network-1"),
+							URL:  ptr.To("https://10.0.0.1/api/network/network-1#" + networkName),
+						}
+						usableNetworks = nil
+						ctx.AviClient.Network.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Network, error) {
+							lock.Lock()
+							defer lock.Unlock()
+							return copyNetwork(network), nil
+						})
+						ctx.AviClient.Network.SetUpdateFn(func(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+							lock.Lock()
+							defer lock.Unlock()
+							network = obj
+							return obj, nil
+						})
+						ctx.AviClient.IPAMDNSProviderProfile.SetGetIPAMFunc(func(uuid string, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error) {
+							lock.Lock()
+							defer lock.Unlock()
+							return &models.IPAMDNSProviderProfile{
+								InternalProfile: &models.IPAMDNSInternalProfile{
+									UsableNetworks: append([]*models.IPAMUsableNetwork{}, usableNetworks...),
+								},
+							}, nil
+						})
+						ctx.AviClient.IPAMDNSProviderProfile.SetUpdateIPAMFn(func(obj *models.IPAMDNSProviderProfile, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error) {
+							lock.Lock()
+							defer lock.Unlock()
+							usableNetworks = obj.InternalProfile.UsableNetworks
+							return obj, nil
+						})
+
+						Eventually(func() bool {
+							adc := &akoov1alpha1.AKODeploymentConfig{}
+							if err := ctx.Client.Get(ctx, client.ObjectKey{Name: akoDeploymentConfig.Name}, adc); err != nil {
+								return false
+							}
+							return len(adc.Status.Subnets) == 1 && len(adc.Status.StaticIPRanges) == 1 &&
+								len(adc.Status.UsableNetworks) == 1
+						}, "30s", "3s").Should(BeTrue())
+						Expect(subnetCount()).To(Equal(1))
+						Expect(usableNetworkCount()).To(Equal(1))
+					})
+
+					It("should remove the subnets, static ranges and usable networks it added once deleted", func() {
+						deleteObjects(akoDeploymentConfig)
+						Eventually(subnetCount, "30s", "3s").Should(Equal(0))
+						Eventually(usableNetworkCount, "30s", "3s").Should(Equal(0))
+					})
+
+					When("another AKODeploymentConfig uses the same network", func() {
+						var sibling *akoov1alpha1.AKODeploymentConfig
+						BeforeEach(func() {
+							sibling = &akoov1alpha1.AKODeploymentConfig{
+								ObjectMeta: metav1.ObjectMeta{Name: akoDeploymentConfig.Name + "-sibling"},
+								Spec:       *staticAkoDeploymentConfig.Spec.DeepCopy(),
+							}
+							sibling.Spec.ClusterSelector = metav1.LabelSelector{
+								MatchLabels: map[string]string{"sibling": "true"},
+							}
+							createObjects(sibling)
+							ensureAKODeploymentConfigFinalizerMatchExpectation(client.ObjectKey{Name: sibling.Name}, true)
+						})
+						AfterEach(func() {
+							Eventually(func() bool {
+								return ensureObjectsDeleted(sibling)
+							}, "60s", "5s").Should(BeTrue())
+						})
+						It("should leave the shared configuration in place once deleted", func() {
+							deleteObjects(akoDeploymentConfig)
+							ensureRuntimeObjectMatchExpectation(client.ObjectKey{Name: akoDeploymentConfig.Name}, &akoov1alpha1.AKODeploymentConfig{}, false)
+							Expect(subnetCount()).To(Equal(1))
+							Expect(usableNetworkCount()).To(Equal(1))
+
+							By("removing them along with the last AKODeploymentConfig using them")
+							deleteObjects(sibling)
+							Eventually(subnetCount, "30s", "3s").Should(Equal(0))
+							Eventually(usableNetworkCount, "30s", "3s").Should(Equal(0))
+						})
+					})
+				})
+
 				// Reconcile -> reconcileDelete
 				When("AKODeploymentConfig is being deleted", func() {
 					BeforeEach(func() {
@@ -716,7 +1349,7 @@ func intgTestAkoDeploymentConfigController() {
 								Namespace: cluster.Namespace,
 							}, false)
 						})
-						//Reconcile -> reconcileDelete -> reconcileClusters(normal phase) -> r.reconcileClustersDelete -> r.clusterReconciler.ReconcileAddonSecretDelete
+						//Reconcile -> reconcileDelete -> reconcileClusters(normal phase) -> r.reconcileClustersDelete -> r.clusterReconciler.ReconcileDeliveryDelete
 						It("should remove add-on secret", func() {
 							ensureRuntimeObjectMatchExpectation(client.ObjectKey{
 								Name:      cluster.Name + "-load-balancer-and-ingress-service-addon",
@@ -730,7 +1363,7 @@ func intgTestAkoDeploymentConfigController() {
 							deleteObjects(cluster)
 						})
 
-						//Reconcile -> reconcileDelete -> r.reconcileClustersDelete -> r.clusterReconciler.ReconcileAddonSecretDelete
+						//Reconcile -> reconcileDelete -> r.reconcileClustersDelete -> r.clusterReconciler.ReconcileDeliveryDelete
 						It("should remove Cluster Add-on Secret", func() {
 							ensureRuntimeObjectMatchExpectation(client.ObjectKey{
 								Name:      cluster.Name + "-load-balancer-and-ingress-service-addon",
@@ -0,0 +1,166 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-logr/logr"
+	"github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+// reconcileServiceEngineGroup provisions the Service Engine Group of an
+// AKODeploymentConfig with a ServiceEngineGroupTemplate: the group is created
+// when it doesn't exist, otherwise the fields set in the template are synced.
+// The Service Engine Group is never deleted by the operator, it may still host
+// Service Engines of other clusters.
+func (r *AKODeploymentConfigReconciler) reconcileServiceEngineGroup(
+	_ context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	template := obj.Spec.ServiceEngineGroupTemplate
+	if template == nil {
+		conditions.Delete(obj, akoov1alpha1.ServiceEngineGroupReadyCondition)
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("serviceEngineGroup", obj.Spec.ServiceEngineGroup)
+	log.Info("Start reconciling AVI service engine group")
+
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return ctrl.Result{}, errors.New("AVI client not initialized")
+	}
+
+	seg, err := aviClient.ServiceEngineGroupGetByName(obj.Spec.ServiceEngineGroup, obj.Spec.CloudName)
+	if err != nil {
+		if aviclient.IsAviObjectNonExistentError(err) {
+			return r.createServiceEngineGroup(log, aviClient, obj)
+		}
+		log.Error(err, "Failed to get service engine group")
+		conditions.MarkFalse(obj, akoov1alpha1.ServiceEngineGroupReadyCondition, akoov1alpha1.ServiceEngineGroupSyncFailedReason,
+			clusterv1.ConditionSeverityWarning, "Failed to get Service Engine Group %s: %v", obj.Spec.ServiceEngineGroup, err)
+		return ctrl.Result{}, err
+	}
+
+	if !ApplyServiceEngineGroupTemplate(seg, template) {
+		conditions.MarkTrue(obj, akoov1alpha1.ServiceEngineGroupReadyCondition)
+		return ctrl.Result{}, nil
+	}
+	log.Info("Service engine group drifted from the template, updating")
+	if _, err := aviClient.ServiceEngineGroupUpdate(seg); err != nil {
+		log.Error(err, "Failed to update service engine group")
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.ServiceEngineGroupFailedReason,
+			"Failed to update Service Engine Group %s: %v", obj.Spec.ServiceEngineGroup, err)
+		conditions.MarkFalse(obj, akoov1alpha1.ServiceEngineGroupReadyCondition, akoov1alpha1.ServiceEngineGroupSyncFailedReason,
+			clusterv1.ConditionSeverityWarning, "Failed to update Service Engine Group %s: %v", obj.Spec.ServiceEngineGroup, err)
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupUpdatedReason,
+		"Service Engine Group %s updated to match the template", obj.Spec.ServiceEngineGroup)
+	conditions.MarkTrue(obj, akoov1alpha1.ServiceEngineGroupReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+// createServiceEngineGroup creates the missing Service Engine Group, either
+// from scratch in the cloud of the AKODeploymentConfig or as a copy of the
+// Service Engine Group the template is cloned from
+func (r *AKODeploymentConfigReconciler) createServiceEngineGroup(
+	log logr.Logger,
+	aviClient aviclient.Client,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	template := obj.Spec.ServiceEngineGroupTemplate
+
+	var seg *models.ServiceEngineGroup
+	if template.CloneFrom != "" {
+		source, err := aviClient.ServiceEngineGroupGetByName(template.CloneFrom, obj.Spec.CloudName)
+		if err != nil {
+			log.Error(err, "Failed to get the service engine group to clone", "cloneFrom", template.CloneFrom)
+			conditions.MarkFalse(obj, akoov1alpha1.ServiceEngineGroupReadyCondition, akoov1alpha1.ServiceEngineGroupCloneSourceNotFoundReason,
+				clusterv1.ConditionSeverityError, "Failed to get Service Engine Group %s to clone: %v", template.CloneFrom, err)
+			return ctrl.Result{}, err
+		}
+		seg = CloneServiceEngineGroup(source, obj.Spec.ServiceEngineGroup)
+	} else {
+		cloud, err := aviClient.CloudGetByName(obj.Spec.CloudName)
+		if err != nil {
+			log.Error(err, "Failed to get cloud", "cloud", obj.Spec.CloudName)
+			conditions.MarkFalse(obj, akoov1alpha1.ServiceEngineGroupReadyCondition, akoov1alpha1.ServiceEngineGroupSyncFailedReason,
+				clusterv1.ConditionSeverityWarning, "Failed to get cloud %s: %v", obj.Spec.CloudName, err)
+			return ctrl.Result{}, err
+		}
+		seg = &models.ServiceEngineGroup{
+			Name:     ptr.To(obj.Spec.ServiceEngineGroup),
+			CloudRef: cloud.URL,
+		}
+	}
+	ApplyServiceEngineGroupTemplate(seg, template)
+
+	log.Info("Creating service engine group", "cloneFrom", template.CloneFrom)
+	if _, err := aviClient.ServiceEngineGroupCreate(seg); err != nil {
+		log.Error(err, "Failed to create service engine group")
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.ServiceEngineGroupFailedReason,
+			"Failed to create Service Engine Group %s: %v", obj.Spec.ServiceEngineGroup, err)
+		conditions.MarkFalse(obj, akoov1alpha1.ServiceEngineGroupReadyCondition, akoov1alpha1.ServiceEngineGroupSyncFailedReason,
+			clusterv1.ConditionSeverityWarning, "Failed to create Service Engine Group %s: %v", obj.Spec.ServiceEngineGroup, err)
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.ServiceEngineGroupCreatedReason,
+		"Service Engine Group %s created in cloud %s", obj.Spec.ServiceEngineGroup, obj.Spec.CloudName)
+	conditions.MarkTrue(obj, akoov1alpha1.ServiceEngineGroupReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+// CloneServiceEngineGroup returns a copy of the settings of source to be
+// created under a new name
+func CloneServiceEngineGroup(source *models.ServiceEngineGroup, name string) *models.ServiceEngineGroup {
+	seg := *source
+	seg.Name = ptr.To(name)
+	seg.UUID = nil
+	seg.URL = nil
+	seg.LastModified = nil
+	return &seg
+}
+
+// ApplyServiceEngineGroupTemplate sets the fields of the template on the
+// Service Engine Group and returns whether any of them changed
+func ApplyServiceEngineGroupTemplate(seg *models.ServiceEngineGroup, template *akoov1alpha1.ServiceEngineGroupTemplate) bool {
+	changed := false
+	if template.HAMode != "" {
+		changed = syncServiceEngineGroupField(&seg.HaMode, template.HAMode) || changed
+	}
+	if template.MaxSEs != nil {
+		changed = syncServiceEngineGroupField(&seg.MaxSe, *template.MaxSEs) || changed
+	}
+	if template.MaxVSPerSE != nil {
+		changed = syncServiceEngineGroupField(&seg.MaxVsPerSe, *template.MaxVSPerSE) || changed
+	}
+	if template.Flavor != "" {
+		changed = syncServiceEngineGroupField(&seg.InstanceFlavor, template.Flavor) || changed
+	}
+	if template.BufferSEs != nil {
+		changed = syncServiceEngineGroupField(&seg.BufferSe, *template.BufferSEs) || changed
+	}
+	return changed
+}
+
+func syncServiceEngineGroupField[T comparable](field **T, value T) bool {
+	if *field != nil && **field == value {
+		return false
+	}
+	*field = ptr.To(value)
+	return true
+}
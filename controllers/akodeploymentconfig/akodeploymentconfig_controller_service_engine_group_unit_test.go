@@ -0,0 +1,94 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
+)
+
+func unitTestServiceEngineGroups() {
+	Context("ApplyServiceEngineGroupTemplate", func() {
+		var seg *models.ServiceEngineGroup
+		BeforeEach(func() {
+			seg = &models.ServiceEngineGroup{
+				Name:           ptr.To("seg"),
+				HaMode:         ptr.To("HA_MODE_SHARED"),
+				MaxSe:          ptr.To(int32(10)),
+				MaxVsPerSe:     ptr.To(int32(10)),
+				InstanceFlavor: ptr.To("small"),
+			}
+		})
+		It("should set the fields of the template", func() {
+			Expect(akodeploymentconfig.ApplyServiceEngineGroupTemplate(seg, &akoov1alpha1.ServiceEngineGroupTemplate{
+				HAMode:     "HA_MODE_SHARED_PAIR",
+				MaxSEs:     ptr.To(int32(4)),
+				MaxVSPerSE: ptr.To(int32(10)),
+				Flavor:     "large",
+				BufferSEs:  ptr.To(int32(1)),
+			})).To(BeTrue())
+			Expect(seg).To(Equal(&models.ServiceEngineGroup{
+				Name:           ptr.To("seg"),
+				HaMode:         ptr.To("HA_MODE_SHARED_PAIR"),
+				MaxSe:          ptr.To(int32(4)),
+				MaxVsPerSe:     ptr.To(int32(10)),
+				InstanceFlavor: ptr.To("large"),
+				BufferSe:       ptr.To(int32(1)),
+			}))
+		})
+		It("should leave the fields unset in the template alone", func() {
+			Expect(akodeploymentconfig.ApplyServiceEngineGroupTemplate(seg, &akoov1alpha1.ServiceEngineGroupTemplate{
+				MaxSEs: ptr.To(int32(4)),
+			})).To(BeTrue())
+			Expect(*seg.HaMode).To(Equal("HA_MODE_SHARED"))
+			Expect(*seg.InstanceFlavor).To(Equal("small"))
+			Expect(seg.BufferSe).To(BeNil())
+		})
+		It("should report no change when the Service Engine Group matches", func() {
+			Expect(akodeploymentconfig.ApplyServiceEngineGroupTemplate(seg, &akoov1alpha1.ServiceEngineGroupTemplate{
+				HAMode: "HA_MODE_SHARED",
+				MaxSEs: ptr.To(int32(10)),
+				Flavor: "small",
+			})).To(BeFalse())
+			Expect(akodeploymentconfig.ApplyServiceEngineGroupTemplate(seg, &akoov1alpha1.ServiceEngineGroupTemplate{})).To(BeFalse())
+		})
+	})
+
+	Context("CloneServiceEngineGroup", func() {
+		It("should copy the settings under the new name", func() {
+			source := &models.ServiceEngineGroup{
+				Name:         ptr.To("Default-Group"),
+				UUID:         ptr.To("serviceenginegroup-1"),
+				URL:          ptr.To("https://10.0.0.x/api/serviceenginegroup/serviceenginegroup-1"),
+				LastModified: ptr.To("1700000000"),
+				CloudRef:     ptr.To("https://10.0.0.x/api/cloud/cloud-1"),
+				MaxSe:        ptr.To(int32(10)),
+			}
+			seg := akodeploymentconfig.CloneServiceEngineGroup(source, "seg")
+			Expect(seg).To(Equal(&models.ServiceEngineGroup{
+				Name:     ptr.To("seg"),
+				CloudRef: ptr.To("https://10.0.0.x/api/cloud/cloud-1"),
+				MaxSe:    ptr.To(int32(10)),
+			}))
+			Expect(*source.Name).To(Equal("Default-Group"))
+			Expect(*source.UUID).To(Equal("serviceenginegroup-1"))
+		})
+	})
+
+	Context("ClusterServiceEngineGroupName", func() {
+		It("should tell apart clusters whose namespace and name join the same way", func() {
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "prod"}}
+			other := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "a-prod"}}
+			Expect(akodeploymentconfig.ClusterServiceEngineGroupName(cluster)).To(Equal("team-a_prod-seg"))
+			Expect(akodeploymentconfig.ClusterServiceEngineGroupName(other)).To(Equal("team_a-prod-seg"))
+		})
+	})
+}
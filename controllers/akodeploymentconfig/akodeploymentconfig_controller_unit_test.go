@@ -158,13 +158,14 @@ func unitTestEnsureStaticRanges() {
 			expectedModified bool
 			subnet           *models.Subnet
 			ipPools          []akoov1alpha1.IPPool
+			owned            []akoov1alpha1.IPPool
 			addrType         string
 		)
 		BeforeEach(func() {
 			addrType = "V4"
 		})
 		JustBeforeEach(func() {
-			modified = akodeploymentconfig.EnsureStaticRanges(subnet, ipPools, addrType)
+			modified, _ = akodeploymentconfig.EnsureStaticRanges(subnet, ipPools, owned, addrType)
 		})
 		When("all the intervals are contiguous", func() {
 			BeforeEach(func() {
@@ -216,6 +217,8 @@ func unitTestEnsureStaticRanges() {
 						},
 					},
 				}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = true
 			})
 			It("should update to ip pools", func() {
@@ -269,6 +272,8 @@ func unitTestEnsureStaticRanges() {
 						},
 					},
 				}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = true
 			})
 			It("should update to ip pools", func() {
@@ -334,6 +339,8 @@ func unitTestEnsureStaticRanges() {
 						},
 					},
 				}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = true
 			})
 			It("should update to ip pools", func() {
@@ -387,6 +394,8 @@ func unitTestEnsureStaticRanges() {
 						},
 					},
 				}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = true
 			})
 			It("should update to ip pools", func() {
@@ -427,6 +436,8 @@ func unitTestEnsureStaticRanges() {
 						},
 					},
 				}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = true
 			})
 		})
@@ -450,6 +461,8 @@ func unitTestEnsureStaticRanges() {
 				}
 				ipPools = []akoov1alpha1.IPPool{}
 				expected = []*models.StaticIPRange{}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = true
 			})
 			It("should update to ip pools", func() {
@@ -500,6 +513,8 @@ func unitTestEnsureStaticRanges() {
 						Type:  addrType,
 					},
 				}
+				// the static ranges were added by the operator
+				owned = ipPoolsOf(subnet.StaticIPRanges, addrType)
 				expectedModified = false
 			})
 			It("should not change anything", func() {
@@ -510,12 +525,93 @@ func unitTestEnsureStaticRanges() {
 			})
 		})
 	})
+	Context("EnsureStaticRanges ownership", func() {
+		var (
+			subnet     *models.Subnet
+			ipPools    []akoov1alpha1.IPPool
+			owned      []akoov1alpha1.IPPool
+			addrType   string
+			modified   bool
+			stillOwned []akoov1alpha1.IPPool
+		)
+		adminPool := akoov1alpha1.IPPool{Start: "192.168.100.1", End: "192.168.100.9", Type: "V4"}
+		ownedPool := akoov1alpha1.IPPool{Start: "192.168.100.10", End: "192.168.100.19", Type: "V4"}
+		newPool := akoov1alpha1.IPPool{Start: "192.168.100.20", End: "192.168.100.29", Type: "V4"}
+		BeforeEach(func() {
+			subnet = &models.Subnet{
+				StaticIPRanges: akodeploymentconfig.CreateStaticRangeFromIPPools([]akoov1alpha1.IPPool{adminPool, ownedPool}),
+			}
+			owned = []akoov1alpha1.IPPool{ownedPool}
+			addrType = "V4"
+		})
+		JustBeforeEach(func() {
+			modified, stillOwned = akodeploymentconfig.EnsureStaticRanges(subnet, ipPools, owned, addrType)
+		})
+		When("an owned range leaves the spec", func() {
+			BeforeEach(func() {
+				ipPools = []akoov1alpha1.IPPool{newPool}
+			})
+			It("should remove it and keep the ranges created by hand", func() {
+				Expect(modified).To(BeTrue())
+				Expect(ipPoolsOf(subnet.StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{adminPool, newPool}))
+				Expect(stillOwned).To(Equal([]akoov1alpha1.IPPool{newPool}))
+			})
+		})
+		When("all the ranges leave the spec", func() {
+			BeforeEach(func() {
+				ipPools = nil
+			})
+			It("should only remove the owned ones", func() {
+				Expect(modified).To(BeTrue())
+				Expect(ipPoolsOf(subnet.StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{adminPool}))
+				Expect(stillOwned).To(BeEmpty())
+			})
+		})
+		When("an ip pool matches a range created by hand", func() {
+			BeforeEach(func() {
+				ipPools = []akoov1alpha1.IPPool{adminPool, ownedPool}
+			})
+			It("should not claim it nor add it again", func() {
+				Expect(modified).To(BeFalse())
+				Expect(ipPoolsOf(subnet.StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{adminPool, ownedPool}))
+				Expect(stillOwned).To(Equal([]akoov1alpha1.IPPool{ownedPool}))
+			})
+		})
+		When("an ip pool matching a range created by hand leaves the spec", func() {
+			BeforeEach(func() {
+				ipPools = []akoov1alpha1.IPPool{adminPool, ownedPool}
+			})
+			It("should keep the range", func() {
+				modified, stillOwned = akodeploymentconfig.EnsureStaticRanges(subnet, []akoov1alpha1.IPPool{ownedPool}, stillOwned, addrType)
+				Expect(modified).To(BeFalse())
+				Expect(ipPoolsOf(subnet.StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{adminPool, ownedPool}))
+				Expect(stillOwned).To(Equal([]akoov1alpha1.IPPool{ownedPool}))
+			})
+		})
+		When("an owned range is written in another notation", func() {
+			BeforeEach(func() {
+				subnet = &models.Subnet{
+					StaticIPRanges: akodeploymentconfig.CreateStaticRangeFromIPPools([]akoov1alpha1.IPPool{
+						{Start: "2002::0010", End: "2002::0020", Type: "V6"},
+					}),
+				}
+				owned = []akoov1alpha1.IPPool{{Start: "2002::10", End: "2002::20", Type: "V6"}}
+				ipPools = nil
+				addrType = "V6"
+			})
+			It("should still be removed", func() {
+				Expect(modified).To(BeTrue())
+				Expect(subnet.StaticIPRanges).To(BeEmpty())
+			})
+		})
+	})
 	Context("EnsureAviNetwork", func() {
 		var (
 			cidr     *net.IPNet
 			mask     int32
 			network  *models.Network
 			ipPools  []akoov1alpha1.IPPool
+			owned    []akoov1alpha1.IPPool
 			logger   logr.Logger
 			addrType string
 
@@ -531,7 +627,7 @@ func unitTestEnsureStaticRanges() {
 			logger = log.Log
 		})
 		JustBeforeEach(func() {
-			modified = akodeploymentconfig.EnsureAviNetwork(network, addrType, cidr, mask, ipPools, logger)
+			modified, _ = akodeploymentconfig.EnsureAviNetwork(network, addrType, cidr, mask, ipPools, owned, logger)
 			expected = akodeploymentconfig.CreateStaticRangeFromIPPools(ipPools)
 		})
 		When("update is needed", func() {
@@ -567,6 +663,7 @@ func unitTestEnsureStaticRanges() {
 							Type:  addrType,
 						},
 					}
+					owned = ipPoolsOf(network.ConfiguredSubnets[0].StaticIPRanges, addrType)
 					expectedModified = true
 				})
 				It("should update static range to ip pools", func() {
@@ -610,6 +707,7 @@ func unitTestEnsureStaticRanges() {
 							Type:  addrType,
 						},
 					}
+					owned = ipPoolsOf(network.ConfiguredSubnets[0].StaticIPRanges, addrType)
 					expectedModified = true
 				})
 				It("should update static range to ip pools", func() {
@@ -653,6 +751,7 @@ func unitTestEnsureStaticRanges() {
 							Type:  addrType,
 						},
 					}
+					owned = ipPoolsOf(network.ConfiguredSubnets[0].StaticIPRanges, addrType)
 					expectedModified = true
 				})
 				It("should update static range to ip pools", func() {
@@ -693,6 +792,7 @@ func unitTestEnsureStaticRanges() {
 						},
 					}
 					ipPools = nil
+					owned = nil
 					expectedModified = false
 				})
 				It("should not update anything", func() {
@@ -742,6 +842,7 @@ func unitTestEnsureStaticRanges() {
 							Type:  addrType,
 						},
 					}
+					owned = nil
 					expectedModified = false
 				})
 				It("should not update anything", func() {
@@ -750,4 +851,98 @@ func unitTestEnsureStaticRanges() {
 			})
 		})
 	})
+	Context("EnsureAviNetworkSubnets", func() {
+		var (
+			network    *models.Network
+			cidrs      []string
+			ipPools    []akoov1alpha1.IPPool
+			owned      []akoov1alpha1.IPPool
+			modified   bool
+			stillOwned []akoov1alpha1.IPPool
+			err        error
+		)
+		BeforeEach(func() {
+			log.SetLogger(zap.New())
+			ipPools = []akoov1alpha1.IPPool{
+				{Start: "192.168.100.1", End: "192.168.100.3", Type: "V4"},
+				{Start: "2002::10", End: "2002::20", Type: "V6"},
+			}
+			owned = nil
+		})
+		JustBeforeEach(func() {
+			modified, stillOwned, err = akodeploymentconfig.EnsureAviNetworkSubnets(network, cidrs, ipPools, owned, log.Log)
+		})
+		When("the network is dual-stack", func() {
+			BeforeEach(func() {
+				network = &models.Network{}
+				cidrs = []string{"192.168.100.0/24", "2002::/64"}
+			})
+			It("should add one subnet per cidr with the ip pools of its type", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(modified).To(BeTrue())
+				Expect(network.ConfiguredSubnets).To(HaveLen(2))
+
+				index, contains := akodeploymentconfig.AviNetworkContainsSubnet(network, "192.168.100.0", 24)
+				Expect(contains).To(BeTrue())
+				Expect(akodeploymentconfig.IsStaticIPRangeEqual(network.ConfiguredSubnets[index].StaticIPRanges,
+					akodeploymentconfig.CreateStaticRangeFromIPPools(ipPools[:1]))).To(BeTrue())
+
+				index, contains = akodeploymentconfig.AviNetworkContainsSubnet(network, "2002::", 64)
+				Expect(contains).To(BeTrue())
+				Expect(*network.ConfiguredSubnets[index].Prefix.IPAddr.Type).To(Equal("V6"))
+				Expect(akodeploymentconfig.IsStaticIPRangeEqual(network.ConfiguredSubnets[index].StaticIPRanges,
+					akodeploymentconfig.CreateStaticRangeFromIPPools(ipPools[1:]))).To(BeTrue())
+			})
+		})
+		When("an owned range is in a subnet out of the spec", func() {
+			BeforeEach(func() {
+				mask := int32(24)
+				network = &models.Network{
+					ConfiguredSubnets: []*models.Subnet{{
+						Prefix: &models.IPAddrPrefix{
+							IPAddr: akodeploymentconfig.GetAddr("10.0.0.0", "V4"),
+							Mask:   &mask,
+						},
+						StaticIPRanges: akodeploymentconfig.CreateStaticRangeFromIPPools([]akoov1alpha1.IPPool{
+							{Start: "10.0.0.1", End: "10.0.0.9", Type: "V4"},
+							{Start: "10.0.0.10", End: "10.0.0.19", Type: "V4"},
+						}),
+					}},
+				}
+				cidrs = []string{"192.168.100.0/24"}
+				owned = []akoov1alpha1.IPPool{{Start: "10.0.0.10", End: "10.0.0.19", Type: "V4"}}
+			})
+			It("should only remove the owned range from it", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(modified).To(BeTrue())
+				Expect(network.ConfiguredSubnets).To(HaveLen(2))
+				Expect(ipPoolsOf(network.ConfiguredSubnets[0].StaticIPRanges, "V4")).To(Equal([]akoov1alpha1.IPPool{
+					{Start: "10.0.0.1", End: "10.0.0.9", Type: "V4"},
+				}))
+				Expect(stillOwned).To(Equal(ipPools[:1]))
+			})
+		})
+		When("a cidr is not valid", func() {
+			BeforeEach(func() {
+				network = &models.Network{}
+				cidrs = []string{"192.168.100.0/24", "2002::/129"}
+			})
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+}
+
+// ipPoolsOf returns the static ranges as IP pools
+func ipPoolsOf(staticRanges []*models.StaticIPRange, addrType string) []akoov1alpha1.IPPool {
+	var res []akoov1alpha1.IPPool
+	for _, staticRange := range staticRanges {
+		res = append(res, akoov1alpha1.IPPool{
+			Start: *staticRange.Range.Begin.Addr,
+			End:   *staticRange.Range.End.Addr,
+			Type:  addrType,
+		})
+	}
+	return res
 }
@@ -0,0 +1,230 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+)
+
+// reconcileVIPPools reports the utilization of the static IP pools of the
+// data and VIP networks in the status and the metrics, and flags the pools
+// whose free IPs are under the threshold of the AKODeploymentConfig. Failing
+// to reach the network runtime doesn't fail the reconciliation, the pools are
+// refreshed again after VIPPoolSyncPeriod.
+func (r *AKODeploymentConfigReconciler) reconcileVIPPools(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	res := ctrl.Result{RequeueAfter: r.VIPPoolSyncPeriod}
+
+	log.Info("Start reconciling VIP pools utilization")
+
+	aviClient := r.AviClients.ClientFor(obj.Name)
+	if aviClient == nil {
+		log.Info("AVI client not initialized, requeue")
+		return ctrl.Result{}, errors.New("AVI client not initialized")
+	}
+
+	previous := obj.Status.VIPPools
+	var vipPools []akoov1alpha1.VIPPoolStatus
+	var unavailable []string
+	for _, vipNetwork := range vipPoolNetworks(obj) {
+		network, err := aviClient.NetworkGetByName(vipNetwork.NetworkName, obj.Spec.CloudName)
+		if err != nil {
+			log.Error(err, "Failed to get the Avi network", "network", vipNetwork.NetworkName)
+			unavailable = append(unavailable, vipNetwork.NetworkName)
+			continue
+		}
+		runtime, err := aviClient.NetworkRuntimeGet(ptr.Deref(network.UUID, ""))
+		if err != nil {
+			log.Error(err, "Failed to get the Avi network runtime", "network", vipNetwork.NetworkName)
+			unavailable = append(unavailable, vipNetwork.NetworkName)
+			continue
+		}
+		for _, ipPool := range vipNetwork.IPPools {
+			vipPools = append(vipPools, GetVIPPoolStatus(runtime, vipNetwork.NetworkName, ipPool))
+		}
+	}
+	// keep the last known utilization of the networks which couldn't be
+	// reached
+	for _, vipPool := range previous {
+		if slices.Contains(unavailable, vipPool.NetworkName) && containsVIPPool(vipPools, vipPool) == nil &&
+			vipPoolInSpec(obj, vipPool) {
+			vipPools = append(vipPools, vipPool)
+		}
+	}
+	obj.Status.VIPPools = vipPools
+
+	metrics.DeleteVIPPools(obj.Name)
+	for _, vipPool := range vipPools {
+		metrics.SetVIPPool(obj.Name, vipPool.NetworkName, vipPool.Start, vipPool.End, vipPool.Total, vipPool.Used, vipPool.Free)
+	}
+
+	threshold := obj.VIPPoolLowThreshold()
+	var low, crossed []string
+	for _, vipPool := range vipPools {
+		if !IsVIPPoolLow(vipPool, threshold) {
+			continue
+		}
+		desc := vipPool.NetworkName + "/" + vipPool.Start + "-" + vipPool.End
+		low = append(low, desc)
+		if old := containsVIPPool(previous, vipPool); old == nil || !IsVIPPoolLow(*old, threshold) {
+			crossed = append(crossed, desc)
+		}
+	}
+	if len(crossed) != 0 {
+		r.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.VIPPoolLowReason,
+			"Static IP pools %s have less than %d%% free IPs", strings.Join(crossed, ","), threshold)
+	}
+
+	switch {
+	case len(low) != 0:
+		conditions.Set(obj, &clusterv1.Condition{
+			Type:     akoov1alpha1.VIPPoolLowCondition,
+			Status:   corev1.ConditionTrue,
+			Severity: clusterv1.ConditionSeverityWarning,
+			Reason:   akoov1alpha1.VIPPoolLowConditionReason,
+			Message:  "Static IP pools " + strings.Join(low, ",") + " are running out of free IPs",
+		})
+	case len(unavailable) != 0:
+		conditions.MarkUnknown(obj, akoov1alpha1.VIPPoolLowCondition, akoov1alpha1.VIPPoolUtilizationUnknownReason,
+			"Failed to get the runtime of Avi networks %s", strings.Join(unavailable, ","))
+	default:
+		conditions.MarkFalse(obj, akoov1alpha1.VIPPoolLowCondition, akoov1alpha1.VIPPoolSufficientReason,
+			clusterv1.ConditionSeverityNone, "")
+	}
+	return res, nil
+}
+
+// vipPoolNetworks returns the data and VIP networks with static IP pools, a
+// network listed several times is returned once with the union of its pools
+func vipPoolNetworks(obj *akoov1alpha1.AKODeploymentConfig) []akoov1alpha1.VIPNetwork {
+	var res []akoov1alpha1.VIPNetwork
+	index := map[string]int{}
+	for _, vipNetwork := range aviNetworks(obj) {
+		if len(vipNetwork.IPPools) == 0 {
+			continue
+		}
+		i, ok := index[vipNetwork.NetworkName]
+		if !ok {
+			index[vipNetwork.NetworkName] = len(res)
+			res = append(res, akoov1alpha1.VIPNetwork{NetworkName: vipNetwork.NetworkName})
+			i = len(res) - 1
+		}
+		for _, ipPool := range vipNetwork.IPPools {
+			if !containsIPPool(res[i].IPPools, ipPool) {
+				res[i].IPPools = append(res[i].IPPools, ipPool)
+			}
+		}
+	}
+	return res
+}
+
+// vipPoolInSpec returns whether the pool is still a static IP pool of the
+// AKODeploymentConfig
+func vipPoolInSpec(obj *akoov1alpha1.AKODeploymentConfig, vipPool akoov1alpha1.VIPPoolStatus) bool {
+	for _, vipNetwork := range vipPoolNetworks(obj) {
+		if vipNetwork.NetworkName == vipPool.NetworkName && containsIPPool(vipNetwork.IPPools, vipPool.IPPool) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsVIPPool returns the status of the same pool in vipPools, nil when
+// there's none
+func containsVIPPool(vipPools []akoov1alpha1.VIPPoolStatus, vipPool akoov1alpha1.VIPPoolStatus) *akoov1alpha1.VIPPoolStatus {
+	for i := range vipPools {
+		if vipPools[i].NetworkName == vipPool.NetworkName && isSameIPPool(vipPools[i].IPPool, vipPool.IPPool) {
+			return &vipPools[i]
+		}
+	}
+	return nil
+}
+
+// IsVIPPoolLow returns whether less than threshold percent of the IPs of the
+// pool are free, a threshold of 0 never reports a pool low
+func IsVIPPoolLow(vipPool akoov1alpha1.VIPPoolStatus, threshold int32) bool {
+	if threshold <= 0 || vipPool.Total == 0 {
+		return false
+	}
+	return float64(vipPool.Free)*100 < float64(threshold)*float64(vipPool.Total)
+}
+
+// GetVIPPoolStatus counts the IPs of the pool and the ones allocated from it by
+// the Avi Controller
+func GetVIPPoolStatus(runtime *models.NetworkRuntime, networkName string, ipPool akoov1alpha1.IPPool) akoov1alpha1.VIPPoolStatus {
+	total := ipPoolSize(ipPool)
+	used := allocatedIPCount(runtime, ipPool)
+	if used > total {
+		used = total
+	}
+	return akoov1alpha1.VIPPoolStatus{
+		NetworkName: networkName,
+		IPPool:      ipPool,
+		Total:       total,
+		Used:        used,
+		Free:        total - used,
+	}
+}
+
+// ipPoolSize returns the number of IPs between the start and the end of the
+// pool, capped to the int64 range for large IPv6 pools
+func ipPoolSize(ipPool akoov1alpha1.IPPool) int64 {
+	start, end := net.ParseIP(ipPool.Start), net.ParseIP(ipPool.End)
+	if start == nil || end == nil {
+		return 0
+	}
+	size := new(big.Int).Sub(new(big.Int).SetBytes(end.To16()), new(big.Int).SetBytes(start.To16()))
+	if size.Sign() < 0 {
+		return 0
+	}
+	size.Add(size, big.NewInt(1))
+	if !size.IsInt64() {
+		return math.MaxInt64
+	}
+	return size.Int64()
+}
+
+// allocatedIPCount returns the number of distinct IPs of the pool allocated
+// in the network runtime
+func allocatedIPCount(runtime *models.NetworkRuntime, ipPool akoov1alpha1.IPPool) int64 {
+	start, end := net.ParseIP(ipPool.Start), net.ParseIP(ipPool.End)
+	if start == nil || end == nil {
+		return 0
+	}
+	allocated := map[string]struct{}{}
+	for _, subnetRuntime := range runtime.SubnetRuntime {
+		for _, rangeRuntime := range subnetRuntime.IPRangeRuntimes {
+			for _, allocatedIP := range rangeRuntime.AllocatedIps {
+				if allocatedIP.IP == nil || allocatedIP.IP.Addr == nil {
+					continue
+				}
+				ip := net.ParseIP(*allocatedIP.IP.Addr)
+				if ip != nil && ipInRange(ip, start, end) {
+					allocated[ip.String()] = struct{}{}
+				}
+			}
+		}
+	}
+	return int64(len(allocated))
+}
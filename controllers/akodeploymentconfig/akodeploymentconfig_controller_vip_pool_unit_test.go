@@ -0,0 +1,111 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package akodeploymentconfig_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	"k8s.io/utils/ptr"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
+)
+
+func unitTestVIPPools() {
+	allocatedIPs := func(addrType string, ips ...string) *models.NetworkRuntime {
+		rangeRuntime := &models.StaticIPRangeRuntime{}
+		for _, ip := range ips {
+			rangeRuntime.AllocatedIps = append(rangeRuntime.AllocatedIps, &models.StaticIPAllocInfo{
+				IP: &models.IPAddr{Addr: ptr.To(ip), Type: ptr.To(addrType)},
+			})
+		}
+		return &models.NetworkRuntime{
+			SubnetRuntime: []*models.SubnetRuntime{{
+				IPRangeRuntimes: []*models.StaticIPRangeRuntime{rangeRuntime},
+			}},
+		}
+	}
+
+	Context("GetVIPPoolStatus", func() {
+		var (
+			runtime *models.NetworkRuntime
+			ipPool  akoov1alpha1.IPPool
+			status  akoov1alpha1.VIPPoolStatus
+		)
+		JustBeforeEach(func() {
+			status = akodeploymentconfig.GetVIPPoolStatus(runtime, "vip", ipPool)
+		})
+
+		When("IPs are allocated in and out of the pool", func() {
+			BeforeEach(func() {
+				ipPool = akoov1alpha1.IPPool{Start: "10.0.0.10", End: "10.0.0.19", Type: "V4"}
+				runtime = allocatedIPs("V4", "10.0.0.9", "10.0.0.10", "10.0.0.15", "10.0.0.15", "10.0.0.19", "10.0.0.20")
+			})
+			It("should only count the distinct IPs of the pool", func() {
+				Expect(status).To(Equal(akoov1alpha1.VIPPoolStatus{
+					NetworkName: "vip",
+					IPPool:      ipPool,
+					Total:       10,
+					Used:        3,
+					Free:        7,
+				}))
+			})
+		})
+
+		When("the pool is IPv6", func() {
+			BeforeEach(func() {
+				ipPool = akoov1alpha1.IPPool{Start: "2001:db8::10", End: "2001:db8::1f", Type: "V6"}
+				runtime = allocatedIPs("V6", "2001:db8:0:0::11")
+			})
+			It("should count the allocated IPs whatever their notation", func() {
+				Expect(status.Total).To(Equal(int64(16)))
+				Expect(status.Used).To(Equal(int64(1)))
+				Expect(status.Free).To(Equal(int64(15)))
+			})
+		})
+
+		When("the pool is larger than the int64 range", func() {
+			BeforeEach(func() {
+				ipPool = akoov1alpha1.IPPool{Start: "2001:db8::", End: "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", Type: "V6"}
+				runtime = &models.NetworkRuntime{}
+			})
+			It("should cap the total", func() {
+				Expect(status.Total).To(Equal(int64(math.MaxInt64)))
+				Expect(status.Free).To(Equal(int64(math.MaxInt64)))
+			})
+		})
+
+		When("the pool is invalid", func() {
+			BeforeEach(func() {
+				ipPool = akoov1alpha1.IPPool{Start: "10.0.0.20", End: "10.0.0.10", Type: "V4"}
+				runtime = allocatedIPs("V4", "10.0.0.15")
+			})
+			It("should be empty", func() {
+				Expect(status.Total).To(BeZero())
+				Expect(status.Used).To(BeZero())
+				Expect(status.Free).To(BeZero())
+			})
+		})
+	})
+
+	Context("IsVIPPoolLow", func() {
+		vipPool := akoov1alpha1.VIPPoolStatus{Total: 100, Used: 91, Free: 9}
+
+		It("should report a pool under the threshold", func() {
+			Expect(akodeploymentconfig.IsVIPPoolLow(vipPool, 10)).To(BeTrue())
+		})
+		It("should not report a pool at the threshold", func() {
+			Expect(akodeploymentconfig.IsVIPPoolLow(vipPool, 9)).To(BeFalse())
+		})
+		It("should never report a pool when the threshold is 0", func() {
+			Expect(akodeploymentconfig.IsVIPPoolLow(akoov1alpha1.VIPPoolStatus{Total: 10, Used: 10}, 0)).To(BeFalse())
+		})
+		It("should not report an empty pool", func() {
+			Expect(akodeploymentconfig.IsVIPPoolLow(akoov1alpha1.VIPPoolStatus{}, 10)).To(BeFalse())
+		})
+	})
+}
@@ -5,15 +5,13 @@ package cluster
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -24,8 +22,7 @@ import (
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
-	akoo "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
-	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
 )
 
 const (
@@ -33,13 +30,16 @@ const (
 )
 
 // NewReconciler initializes a ClusterReconciler
-func NewReconciler(c client.Client, log logr.Logger, scheme *runtime.Scheme) *ClusterReconciler {
-	return &ClusterReconciler{
+func NewReconciler(c client.Client, log logr.Logger, scheme *runtime.Scheme, recorder record.EventRecorder) *ClusterReconciler {
+	r := &ClusterReconciler{
 		Client:          c,
 		Log:             log,
 		Scheme:          scheme,
+		Recorder:        recorder,
 		GetRemoteClient: remote.NewClusterClient,
 	}
+	r.Deliveries = newDeliveries(r)
+	return r
 }
 
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;create;update;patch;delete
@@ -51,7 +51,12 @@ type ClusterReconciler struct {
 	client.Client
 	Log             logr.Logger
 	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
 	GetRemoteClient remote.ClusterClientGetter
+	// Deliveries are the backends delivering AKO to the clusters
+	Deliveries map[akoov1alpha1.AKODelivery]Delivery
+	// ManifestOptions configures the AKO manifests rendered by the operator
+	ManifestOptions ako.ManifestOptions
 }
 
 // ReconcileDelete removes the finalizer on Cluster once AKO finishes its
@@ -60,16 +65,18 @@ func (r *ClusterReconciler) ReconcileDelete(
 	ctx context.Context,
 	log logr.Logger,
 	cluster *clusterv1.Cluster,
-	_ *akoov1alpha1.AKODeploymentConfig,
+	obj *akoov1alpha1.AKODeploymentConfig,
 ) (ctrl.Result, error) {
 	res := ctrl.Result{}
 
 	if ctrlutil.ContainsFinalizer(cluster, akoov1alpha1.ClusterFinalizer) {
 		log.Info("Handling deleted Cluster")
 
-		finished, err := r.cleanup(ctx, log, cluster)
+		finished, err := r.cleanup(ctx, log, cluster, obj)
 		if err != nil {
 			log.Error(err, "Error cleaning up")
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviResourceCleanupFailedReason,
+				"Failed to clean up the Avi resources: %v", err)
 			return res, err
 		}
 		// remove finalizer only after avi resources deleted && avi user deleted
@@ -93,6 +100,7 @@ func (r *ClusterReconciler) cleanup(
 	ctx context.Context,
 	log logr.Logger,
 	obj *clusterv1.Cluster,
+	adc *akoov1alpha1.AKODeploymentConfig,
 ) (bool, error) {
 	// Firstly we check if there is a cleanup condition in the Cluster
 	// status , if not, we update it. If cleanup condition is succeeded=true nothing left to do
@@ -100,12 +108,13 @@ func (r *ClusterReconciler) cleanup(
 	if !conditions.Has(obj, akoov1alpha1.AviResourceCleanupSucceededCondition) {
 		conditions.MarkFalse(obj, akoov1alpha1.AviResourceCleanupSucceededCondition, akoov1alpha1.AviResourceCleanupReason, clusterv1.ConditionSeverityInfo, "Cleaning up the AVI load balancing resources before deletion")
 		log.Info("Trigger the AKO cleanup in the target Cluster and set Cluster condition", "condition", akoov1alpha1.AviResourceCleanupSucceededCondition)
+		r.Recorder.Event(obj, corev1.EventTypeNormal, akoov1alpha1.AviResourceCleanupStartedReason,
+			"Cleaning up the Avi load balancing resources before deletion")
 	} else if conditions.IsTrue(obj, akoov1alpha1.AviResourceCleanupSucceededCondition) {
 		log.Info("Avi resource cleanup is finished")
 		return true, nil
 	}
 
-	akoAddonSecret := &corev1.Secret{}
 	remoteClient, err := r.GetRemoteClient(ctx, akoov1alpha1.AKODeploymentConfigControllerName, r.Client, client.ObjectKey{
 		Name:      obj.Name,
 		Namespace: obj.Namespace,
@@ -116,48 +125,19 @@ func (r *ClusterReconciler) cleanup(
 
 	}
 
-	// in legacy cluster
-	//   - secret is load-balancer-and-ingress-service-data-values
-	// in clusterclass cluster
-	//   - secret is <cluster-name>-load-balancer-and-ingress-service-data-values
-	secretName := r.akoAddonDataValueName()
-	if akoo.IsClusterClassBasedCluster(obj) {
-		secretName = utils.AKOAddonSecretNameForClusterClass(obj)
-	}
-	if err := remoteClient.Get(ctx, client.ObjectKey{
-		Name:      secretName,
-		Namespace: akoov1alpha1.TKGSystemNamespace,
-	}, akoAddonSecret); err != nil {
-		if apierrors.IsNotFound(err) {
-			log.Info(fmt.Sprintf("since secret %s/%s is not found, assume the ako resource deletion succeed", akoov1alpha1.TKGSystemNamespace, secretName))
-			conditions.MarkTrue(obj, akoov1alpha1.AviResourceCleanupSucceededCondition)
-			return true, nil
-		}
-		log.Error(err, "Failed to get AKO Addon Data Values, AKO clean up failed")
+	// AKO is told to clean up the same way it was delivered
+	delivery, err := r.delivery(clusterDeliveryMode(adc, obj))
+	if err != nil {
 		return false, err
 	}
-
-	akoAddonSecretData := akoAddonSecret.Data["values.yaml"]
-
-	values, err := ako.NewValuesFromBytes(akoAddonSecretData)
+	found, err := delivery.TriggerCleanup(ctx, log, remoteClient, obj)
 	if err != nil {
-		log.Error(err, "Failed to unmarshal values from ako add-on data")
 		return false, err
 	}
-
-	if values.LoadBalancerAndIngressService.Config.AKOSettings.DeleteConfig != "true" {
-		values.LoadBalancerAndIngressService.Config.AKOSettings.DeleteConfig = "true"
-		secretData, err := values.YttYaml(obj)
-		if err != nil {
-			return false, errors.Errorf("workload cluster %s ako add-on data values marshal error", obj.Name)
-		}
-		akoAddonSecret.Data["values.yaml"] = []byte(secretData)
-		if err := remoteClient.Update(ctx, akoAddonSecret); err != nil {
-			log.Error(err, "Failed to update AKO Addon Data Values, AKO clean up failed")
-			return false, err
-		}
-
-		log.Info("Updated `deleteConfig` field to true in AKO Addon Data Values, starting ako clean up")
+	if !found {
+		log.Info("since AKO is not found in the cluster, assume the ako resource deletion succeed")
+		r.cleanupSucceeded(obj)
+		return true, nil
 	}
 
 	cleanupFinished, err := ako.CleanupFinished(ctx, remoteClient, log)
@@ -168,12 +148,24 @@ func (r *ClusterReconciler) cleanup(
 
 	if cleanupFinished {
 		log.Info("AKO finished cleanup, updating Cluster condition")
-		conditions.MarkTrue(obj, akoov1alpha1.AviResourceCleanupSucceededCondition)
+		r.cleanupSucceeded(obj)
 		return true, nil
 	}
 	return false, nil
 }
 
+// cleanupSucceeded marks the cleanup as finished and records the time
+// elapsed since it was triggered, i.e. since the cleanup condition was set to
+// false
+func (r *ClusterReconciler) cleanupSucceeded(obj *clusterv1.Cluster) {
+	if started := conditions.GetLastTransitionTime(obj, akoov1alpha1.AviResourceCleanupSucceededCondition); started != nil {
+		metrics.CleanupDuration.Observe(time.Since(started.Time).Seconds())
+	}
+	conditions.MarkTrue(obj, akoov1alpha1.AviResourceCleanupSucceededCondition)
+	r.Recorder.Event(obj, corev1.EventTypeNormal, akoov1alpha1.AviResourceCleanupSucceededReason,
+		"Avi load balancing resources are cleaned up")
+}
+
 func GetFakeRemoteClient(_ context.Context, _ string, _ client.Client, _ client.ObjectKey) (client.Client, error) {
 	// return fake client
 	return fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), nil
@@ -5,6 +5,8 @@ package cluster
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"reflect"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/go-logr/logr"
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
 	akoo "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
@@ -19,9 +22,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/cluster-api/util/conditions"
 	clusterapipatchutil "sigs.k8s.io/cluster-api/util/patch"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	runv1alpha3 "github.com/vmware-tanzu/tanzu-framework/apis/run/v1alpha3"
@@ -34,136 +35,189 @@ const (
 	DualStackIPv4Primary = "V4,V6"
 )
 
-func (r *ClusterReconciler) ReconcileAddonSecret(
+// tkgAddonDelivery delivers AKO through a TKG add-on secret, the
+// addons-manager of tanzu-framework installs the AKO package with it
+type tkgAddonDelivery struct {
+	r *ClusterReconciler
+}
+
+func (d *tkgAddonDelivery) Deliver(
 	ctx context.Context,
 	log logr.Logger,
 	cluster *clusterv1.Cluster,
 	obj *akoov1alpha1.AKODeploymentConfig,
-) (ctrl.Result, error) {
-	log.Info("Starts reconciling add on secret")
-	res := ctrl.Result{}
-	aviSecret, err := r.getClusterAviUserSecret(cluster, ctx)
-	if err != nil {
-		log.Info("Failed to get cluster avi user secret, requeue")
-		return res, err
-	}
-
-	// when avi is ha provider and deploy ako in management cluster, need to wait for
-	// control plane load balancer type of service creating
-	isVIPProvider, err := akoo.IsControlPlaneVIPProvider(cluster)
-	if err != nil {
-		log.Error(err, "can't unmarshal cluster variables")
-		return res, err
-	}
-	if isVIPProvider && cluster.Namespace == akoov1alpha1.TKGSystemNamespace {
-		svc := &corev1.Service{}
-		if err = r.Get(ctx, client.ObjectKey{
-			Name:      cluster.Namespace + "-" + cluster.Name + "-" + akoov1alpha1.HAServiceName,
-			Namespace: akoov1alpha1.TKGSystemNamespace,
-		}, svc); err != nil {
-			log.Info("Failed to get cluster control plane load balancer type of service, requeue")
-			return res, err
-		}
-	}
-
-	//Stop reconciling if cluster ip family doesn't satisfy the condition
-	if err = ValidateClusterIpFamily(cluster, obj, isVIPProvider, log); err != nil {
-		errInfo := "cluster " + cluster.Namespace +
-			"-" + cluster.Name + "'s ip family is not allowed, stop deploying AKO into cluster " + cluster.Namespace + "-" + cluster.Name
-		log.Error(err, errInfo)
-		clusterCondition := &clusterv1.Condition{
-			Type:    akoov1alpha1.ClusterIpFamilyValidationSucceededCondition,
-			Status:  corev1.ConditionFalse,
-			Message: errInfo,
-		}
-		conditions.Set(cluster, clusterCondition)
-		return res, nil
-	}
-
-	newAddonSecret, err := r.createAKOAddonSecret(cluster, obj, aviSecret)
-	if err != nil {
-		log.Info("Failed to convert AKO Deployment Config to add-on secret, requeue the request")
-		return res, err
-	}
+	values *AKOValues,
+) error {
+	r := d.r
+	newAddonSecret := r.createAKOAddonSecret(cluster, values)
 	secret := &corev1.Secret{}
-	if err = r.Get(ctx, client.ObjectKey{
+	if err := r.Get(ctx, client.ObjectKey{
 		Name:      utils.AKOAddonSecretName(cluster),
 		Namespace: cluster.Namespace,
 	}, secret); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("AKO add on secret doesn't exist, start creating it")
-			return res, r.Create(ctx, newAddonSecret)
+			if err := r.Create(ctx, newAddonSecret); err != nil {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AKOAddonSecretFailedReason,
+					"Failed to create AKO add-on secret %s: %v", newAddonSecret.Name, err)
+				return err
+			}
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AKOAddonSecretCreatedReason,
+				"Created AKO add-on secret %s from AKODeploymentConfig %s", newAddonSecret.Name, obj.Name)
+			return nil
 		}
 		log.Error(err, "Failed to get AKO Deployment Secret, requeue")
-		return res, err
+		return err
+	}
+	// every update of the add-on secret makes kapp-controller reconcile the
+	// AKO package of the cluster, so it's skipped when nothing changed
+	if addonSecretUpToDate(secret, newAddonSecret, values.Hash) {
+		log.V(3).Info("AKO add-on secret is up to date, skip the update", "hash", values.Hash)
+		return nil
+	}
+	// the diff is only computed when it's logged
+	if log.V(3).Enabled() {
+		log.V(3).Info("AKO values changed", "previousHash", secret.Annotations[akoov1alpha1.AKOValuesHashAnnotation],
+			"hash", values.Hash, "diff", valuesDiff(addonSecretValues(secret), values.YAML))
 	}
 	secret = newAddonSecret.DeepCopy()
 	if err := r.Update(ctx, secret); err != nil {
 		log.Error(err, "Failed to update ako add on secret, requeue")
-		return res, err
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AKOAddonSecretFailedReason,
+			"Failed to update AKO add-on secret %s: %v", secret.Name, err)
+		return err
 	}
-
-	// patch cluster bootstrap when it is classy cluster and not in bootstrap cluster
-	if akoo.IsClusterClassBasedCluster(cluster) && !akoo.IsBootStrapCluster() {
-		log.Info("patching clusterbootstrap with ako packageRef")
-		if err := r.patchAkoPackageRefToClusterBootstrap(ctx, log, cluster); err != nil {
-			log.Error(err, "Failed to patch ako package ref to cluster bootstrap, requeue")
-			return res, err
-		}
+	if status := phases.GetClusterStatus(obj, cluster); status != nil && status.ValuesHash != "" && status.ValuesHash != values.Hash {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AKOAddonSecretUpdatedReason,
+			"Updated AKO add-on secret %s from AKODeploymentConfig %s", secret.Name, obj.Name)
 	}
-	return res, nil
+	return nil
 }
 
-func (r *ClusterReconciler) ReconcileAddonSecretDelete(
+func (d *tkgAddonDelivery) Remove(
 	ctx context.Context,
 	log logr.Logger,
 	cluster *clusterv1.Cluster,
-	_ *akoov1alpha1.AKODeploymentConfig,
-) (ctrl.Result, error) {
-	log.Info("Starts reconciling add on secret deletion")
-	res := ctrl.Result{}
-
+) (bool, error) {
 	secret := &corev1.Secret{}
-	if err := r.Get(ctx, client.ObjectKey{
+	if err := d.r.Get(ctx, client.ObjectKey{
 		Name:      utils.AKOAddonSecretName(cluster),
 		Namespace: cluster.Namespace,
 	}, secret); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("AKO add on secret already deleted")
-			return res, nil
+			return true, nil
 		}
 		log.Error(err, "Failed to get AKO Deployment Secret, requeue")
-		return res, err
+		return false, err
 	}
-	if err := r.Delete(ctx, secret); err != nil {
+	if err := d.r.Delete(ctx, secret); err != nil {
 		log.Error(err, "Failed to delete ako add on secret, requeue")
-		return res, err
+		return false, err
+	}
+	return true, nil
+}
+
+// TriggerCleanup sets deleteConfig in the data values secret the add-on
+// secret is rendered into in the cluster
+func (d *tkgAddonDelivery) TriggerCleanup(
+	ctx context.Context,
+	log logr.Logger,
+	remoteClient client.Client,
+	cluster *clusterv1.Cluster,
+) (bool, error) {
+	// in legacy cluster
+	//   - secret is load-balancer-and-ingress-service-data-values
+	// in clusterclass cluster
+	//   - secret is <cluster-name>-load-balancer-and-ingress-service-data-values
+	akoAddonSecret := &corev1.Secret{}
+	secretName := d.r.akoAddonDataValueName()
+	if akoo.IsClusterClassBasedCluster(cluster) {
+		secretName = utils.AKOAddonSecretNameForClusterClass(cluster)
 	}
+	if err := remoteClient.Get(ctx, client.ObjectKey{
+		Name:      secretName,
+		Namespace: akoov1alpha1.TKGSystemNamespace,
+	}, akoAddonSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info(fmt.Sprintf("secret %s/%s is not found", akoov1alpha1.TKGSystemNamespace, secretName))
+			return false, nil
+		}
+		log.Error(err, "Failed to get AKO Addon Data Values, AKO clean up failed")
+		return false, err
+	}
+
+	akoAddonSecretData := akoAddonSecret.Data["values.yaml"]
+
+	values, err := ako.NewValuesFromBytes(akoAddonSecretData)
+	if err != nil {
+		log.Error(err, "Failed to unmarshal values from ako add-on data")
+		return false, err
+	}
+
+	if values.LoadBalancerAndIngressService.Config.AKOSettings.DeleteConfig != "true" {
+		values.LoadBalancerAndIngressService.Config.AKOSettings.DeleteConfig = "true"
+		secretData, err := values.YttYaml(cluster)
+		if err != nil {
+			return false, fmt.Errorf("workload cluster %s ako add-on data values marshal error", cluster.Name)
+		}
+		akoAddonSecret.Data["values.yaml"] = []byte(secretData)
+		if err := remoteClient.Update(ctx, akoAddonSecret); err != nil {
+			log.Error(err, "Failed to update AKO Addon Data Values, AKO clean up failed")
+			return false, err
+		}
 
+		log.Info("Updated `deleteConfig` field to true in AKO Addon Data Values, starting ako clean up")
+	}
+	return true, nil
+}
+
+// clusterBootstrapDelivery delivers AKO through the TKG add-on secret as well,
+// referenced by the AKO package of the ClusterBootstrap of the cluster
+type clusterBootstrapDelivery struct {
+	tkgAddonDelivery
+}
+
+func (d *clusterBootstrapDelivery) Deliver(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	values *AKOValues,
+) error {
+	if err := d.tkgAddonDelivery.Deliver(ctx, log, cluster, obj, values); err != nil {
+		return err
+	}
+	log.Info("patching clusterbootstrap with ako packageRef")
+	if err := d.r.patchAkoPackageRefToClusterBootstrap(ctx, log, cluster); err != nil {
+		log.Error(err, "Failed to patch ako package ref to cluster bootstrap, requeue")
+		return err
+	}
+	return nil
+}
+
+func (d *clusterBootstrapDelivery) Remove(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+) (bool, error) {
 	// TODO: skip this step since ClusterBootstrap webhook doesn't aloow this yet
 	// Add back once this is supported
-	// if akoo.IsClusterClassBasedCluster(cluster) {
-
-	// 	// remove cluster bootstrap correspondingly
-	// 	if err := r.removeAkoPackageRefFromClusterBootstrap(ctx, cluster); err != nil {
-	// 		log.Error(err, "Failed to remove ako package ref from cluster bootstrap, requeue")
-	// 		return res, err
-	// 	}
+	// if err := d.r.removeAkoPackageRefFromClusterBootstrap(ctx, cluster); err != nil {
+	// 	log.Error(err, "Failed to remove ako package ref from cluster bootstrap, requeue")
+	// 	return err
 	// }
-	return res, nil
+	return d.tkgAddonDelivery.Remove(ctx, log, cluster)
 }
 
-func (r *ClusterReconciler) createAKOAddonSecret(cluster *clusterv1.Cluster, obj *akoov1alpha1.AKODeploymentConfig, aviUsersecret *corev1.Secret) (*corev1.Secret, error) {
-	secretStringData, err := AkoAddonSecretDataYaml(cluster, obj, aviUsersecret)
-	if err != nil {
-		return nil, err
-	}
+func (r *ClusterReconciler) createAKOAddonSecret(cluster *clusterv1.Cluster, values *AKOValues) *corev1.Secret {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      utils.AKOAddonSecretName(cluster),
 			Namespace: cluster.Namespace,
 			Annotations: map[string]string{
-				akoov1alpha1.TKGAddonAnnotationKey: "networking/load-balancer-and-ingress-service",
+				akoov1alpha1.TKGAddonAnnotationKey:   "networking/load-balancer-and-ingress-service",
+				akoov1alpha1.AKOValuesHashAnnotation: values.Hash,
 			},
 			Labels: map[string]string{
 				akoov1alpha1.TKGAddOnLabelAddonNameKey:   "load-balancer-and-ingress-service",
@@ -173,39 +227,83 @@ func (r *ClusterReconciler) createAKOAddonSecret(cluster *clusterv1.Cluster, obj
 		},
 		Type: akoov1alpha1.TKGAddOnSecretType,
 		StringData: map[string]string{
-			akoov1alpha1.TKGAddOnSecretDataKey: secretStringData,
+			akoov1alpha1.TKGAddOnSecretDataKey: values.YAML,
 		},
 	}
 
 	if akoo.IsClusterClassBasedCluster(cluster) {
 		secret.Type = akoov1alpha1.TKGClusterClassAddOnSecretType
 	}
-	return secret, nil
+	return secret
 }
 
-func AkoAddonSecretDataYaml(cluster *clusterv1.Cluster, obj *akoov1alpha1.AKODeploymentConfig, aviUsersecret *corev1.Secret) (string, error) {
-	secret, err := ako.NewValues(obj, cluster.Namespace+"-"+cluster.Name)
-	if err != nil {
-		return "", err
+func valuesHash(values []byte) string {
+	sum := sha256.Sum256(values)
+	return hex.EncodeToString(sum[:])
+}
+
+// addonSecretUpToDate returns whether the add-on secret already carries the
+// values of the given hash along with the desired labels and annotations.
+// The hash of the stored values is checked as well so that a secret edited
+// by hand is still overwritten.
+func addonSecretUpToDate(current, desired *corev1.Secret, hash string) bool {
+	if current.Annotations[akoov1alpha1.AKOValuesHashAnnotation] != hash ||
+		valuesHash([]byte(addonSecretValues(current))) != hash ||
+		current.Type != desired.Type {
+		return false
+	}
+	for k, v := range desired.Labels {
+		if current.Labels[k] != v {
+			return false
+		}
+	}
+	for k, v := range desired.Annotations {
+		if current.Annotations[k] != v {
+			return false
+		}
 	}
+	return true
+}
 
-	//Pass cluster role information to ako
-	//Avoid setting DeleteConfig for management cluster
-	if cluster.Namespace == akoov1alpha1.TKGSystemNamespace {
-		secret.LoadBalancerAndIngressService.Config.TkgClusterRole = "management"
-	} else {
-		secret.LoadBalancerAndIngressService.Config.TkgClusterRole = "workload"
-		if deleteConfig, exists := cluster.Labels[akoov1alpha1.AviClusterDeleteConfigLabel]; exists {
-			if deleteConfig == "true" {
-				secret.LoadBalancerAndIngressService.Config.AKOSettings.DeleteConfig = "true"
-			}
+// addonSecretValues returns the AKO values stored in the add-on secret,
+// StringData is only found in secrets which didn't go through the API server
+func addonSecretValues(secret *corev1.Secret) string {
+	if values, ok := secret.StringData[akoov1alpha1.TKGAddOnSecretDataKey]; ok {
+		return values
+	}
+	return string(secret.Data[akoov1alpha1.TKGAddOnSecretDataKey])
+}
+
+// valuesDiff returns the lines of the AKO values which were removed and
+// added, the credentials are redacted
+func valuesDiff(previous, current string) []string {
+	count := map[string]int{}
+	for _, line := range strings.Split(previous, "\n") {
+		count[line]++
+	}
+	var added []string
+	for _, line := range strings.Split(current, "\n") {
+		if count[line] > 0 {
+			count[line]--
+			continue
+		}
+		added = append(added, "+"+redactValuesLine(line))
+	}
+	var diff []string
+	for _, line := range strings.Split(previous, "\n") {
+		if count[line] > 0 {
+			count[line]--
+			diff = append(diff, "-"+redactValuesLine(line))
 		}
 	}
+	return append(diff, added...)
+}
 
-	secret.LoadBalancerAndIngressService.Config.Avicredentials.Username = string(aviUsersecret.Data["username"][:])
-	secret.LoadBalancerAndIngressService.Config.Avicredentials.Password = string(aviUsersecret.Data["password"][:])
-	secret.LoadBalancerAndIngressService.Config.Avicredentials.CertificateAuthorityData = string(aviUsersecret.Data[akoov1alpha1.AviCertificateKey][:])
-	return secret.YttYaml(cluster)
+func redactValuesLine(line string) string {
+	if key, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && key == "password" {
+		return line[:strings.Index(line, ":")+1] + " <redacted>"
+	}
+	return line
 }
 
 func (r *ClusterReconciler) getClusterAviUserSecret(cluster *clusterv1.Cluster, ctx context.Context) (*corev1.Secret, error) {
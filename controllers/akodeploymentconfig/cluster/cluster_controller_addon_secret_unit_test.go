@@ -0,0 +1,116 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/cluster"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+func unitTestAddonSecretUpdate() {
+	var (
+		ctx         context.Context
+		kclient     client.Client
+		r           *cluster.ClusterReconciler
+		obj         *akoov1alpha1.AKODeploymentConfig
+		capiCluster *clusterv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+		capiCluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				ClusterNetwork: &clusterv1.ClusterNetwork{
+					Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				},
+			},
+		}
+		aviUserSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: utils.AVIUserSecretName(capiCluster), Namespace: "default"},
+			Data: map[string][]byte{
+				"username": []byte("test-cluster-default-ako-user"),
+				"password": []byte("Admin!23"),
+			},
+		}
+		kclient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(capiCluster, aviUserSecret).Build()
+		r = cluster.NewReconciler(kclient, ctrl.Log, scheme, record.NewFakeRecorder(10))
+		obj = &akoov1alpha1.AKODeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-adc"},
+			Spec: akoov1alpha1.AKODeploymentConfigSpec{
+				CloudName:          "test-cloud",
+				Controller:         "10.23.122.1",
+				ServiceEngineGroup: "Default-SEG",
+				DataNetwork:        akoov1alpha1.DataNetwork{Name: "test-akdc", CIDR: "10.0.0.0/24"},
+			},
+		}
+	})
+
+	addonSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(kclient.Get(ctx, client.ObjectKey{
+			Name:      utils.AKOAddonSecretName(capiCluster),
+			Namespace: "default",
+		}, secret)).To(Succeed())
+		return secret
+	}
+
+	reconcile := func() {
+		_, err := r.ReconcileDelivery(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should only update the add-on secret when the values change", func() {
+		reconcile()
+		secret := addonSecret()
+		hash := secret.Annotations[akoov1alpha1.AKOValuesHashAnnotation]
+		Expect(hash).NotTo(BeEmpty())
+		Expect(phases.GetClusterStatus(obj, capiCluster).ValuesHash).To(Equal(hash))
+
+		reconcile()
+		Expect(addonSecret().ResourceVersion).To(Equal(secret.ResourceVersion))
+		Expect(phases.GetClusterStatus(obj, capiCluster).ValuesHashHistory).To(BeEmpty())
+
+		obj.Spec.ServiceEngineGroup = "other-SEG"
+		reconcile()
+		updated := addonSecret()
+		Expect(updated.ResourceVersion).NotTo(Equal(secret.ResourceVersion))
+		Expect(updated.Annotations[akoov1alpha1.AKOValuesHashAnnotation]).NotTo(Equal(hash))
+		status := phases.GetClusterStatus(obj, capiCluster)
+		Expect(status.ValuesHash).To(Equal(updated.Annotations[akoov1alpha1.AKOValuesHashAnnotation]))
+		Expect(status.ValuesHashHistory).To(HaveLen(1))
+		Expect(status.ValuesHashHistory[0].Hash).To(Equal(hash))
+	})
+
+	It("should overwrite an add-on secret edited by hand", func() {
+		reconcile()
+		// the fake client doesn't merge StringData into Data
+		secret := addonSecret()
+		values := secret.StringData[akoov1alpha1.TKGAddOnSecretDataKey]
+		secret.StringData[akoov1alpha1.TKGAddOnSecretDataKey] = "edited"
+		Expect(kclient.Update(ctx, secret)).To(Succeed())
+
+		reconcile()
+		Expect(addonSecret().StringData[akoov1alpha1.TKGAddOnSecretDataKey]).To(Equal(values))
+	})
+}
@@ -174,7 +174,7 @@ func unitTestAKODeploymentYaml() {
 			})
 
 			It("should populate correct values in crs yaml", func() {
-				_, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+				_, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 			})
 
@@ -185,14 +185,14 @@ func unitTestAKODeploymentYaml() {
 			})
 
 			It("should generates exact values in crs yaml with the string template approach", func() {
-				secretYaml, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+				values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 				Expect(err).ShouldNot(HaveOccurred())
-				Expect(secretYaml).Should(Equal(expectedSecretYaml))
+				Expect(values.YAML).Should(Equal(expectedSecretYaml))
 			})
 
 			It("should throw error if template not match", func() {
 				akoDeploymentConfig.Spec.DataNetwork.CIDR = "test"
-				_, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+				_, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 				Expect(err).Should(HaveOccurred())
 				akoDeploymentConfig.Spec.DataNetwork.CIDR = "10.0.0.0/24"
 			})
@@ -206,35 +206,126 @@ func unitTestAKODeploymentYaml() {
 				Expect(secretData).Should(ContainSubstring("delete_config: \"true\""))
 			})
 
+			When("the cluster has a dedicated Service Engine Group", func() {
+				BeforeEach(func() {
+					akoDeploymentConfig.Spec.ServiceEngineGroupPerCluster = true
+				})
+				AfterEach(func() {
+					akoDeploymentConfig.Spec.ServiceEngineGroupPerCluster = false
+				})
+
+				It("should render the Service Engine Group of the cluster", func() {
+					capicluster.Annotations = map[string]string{akoov1alpha1.AviClusterServiceEngineGroupAnnotation: "default-cluster-seg"}
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(values.YAML).Should(ContainSubstring("service_engine_group_name: default-cluster-seg"))
+				})
+
+				It("should wait for the Service Engine Group of the cluster", func() {
+					_, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).Should(HaveOccurred())
+				})
+
+				It("should render the shared Service Engine Group for the management cluster", func() {
+					capicluster.Namespace = akoov1alpha1.TKGSystemNamespace
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(values.YAML).Should(ContainSubstring("service_engine_group_name: " + akoDeploymentConfig.Spec.ServiceEngineGroup))
+				})
+			})
+
+			When("the cluster has a dedicated Avi tenant", func() {
+				BeforeEach(func() {
+					akoDeploymentConfig.Spec.Tenant.PerCluster = &akoov1alpha1.AVIClusterTenant{}
+				})
+				AfterEach(func() {
+					akoDeploymentConfig.Spec.Tenant.PerCluster = nil
+				})
+
+				It("should render the tenant of the cluster", func() {
+					capicluster.Annotations = map[string]string{akoov1alpha1.AviClusterTenantAnnotation: "default-cluster"}
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(values.YAML).Should(ContainSubstring("tenant_name: default-cluster"))
+				})
+
+				It("should wait for the tenant of the cluster", func() {
+					_, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).Should(HaveOccurred())
+				})
+
+				It("should render the shared tenant for the management cluster", func() {
+					capicluster.Namespace = akoov1alpha1.TKGSystemNamespace
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(values.YAML).ShouldNot(ContainSubstring("tenant_name: default-cluster"))
+				})
+			})
+
+			When("the cluster has a dedicated VIP sub-pool", func() {
+				BeforeEach(func() {
+					akoDeploymentConfig.Spec.DataNetwork.IPPools = []akoov1alpha1.IPPool{{Start: "10.0.0.1", End: "10.0.0.100", Type: "V4"}}
+					akoDeploymentConfig.Spec.DataNetwork.ClusterIPPoolSize = 4
+				})
+				AfterEach(func() {
+					akoDeploymentConfig.Spec.DataNetwork.IPPools = nil
+					akoDeploymentConfig.Spec.DataNetwork.ClusterIPPoolSize = 0
+				})
+
+				It("should scope the VIP network to the subnet of the sub-pool", func() {
+					capicluster.Annotations = map[string]string{akoov1alpha1.AviClusterIPPoolAnnotation: "10.0.0.8-10.0.0.11"}
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(values.LoadBalancerAndIngressService.Config.NetworkSettings.VIPNetworkList).To(Equal([]akoov1alpha1.VIPNetwork{
+						{NetworkName: "test-akdc", CIDR: "10.0.0.8/30"},
+					}))
+					Expect(values.YAML).Should(ContainSubstring(`vip_network_list: '[{"networkName":"test-akdc","cidr":"10.0.0.8/30"}]'`))
+				})
+
+				It("should wait for the VIP sub-pool of the cluster", func() {
+					_, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).Should(HaveOccurred())
+				})
+
+				It("should render the whole data network for the management cluster", func() {
+					capicluster.Namespace = akoov1alpha1.TKGSystemNamespace
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(values.LoadBalancerAndIngressService.Config.NetworkSettings.VIPNetworkList).To(Equal([]akoov1alpha1.VIPNetwork{
+						{NetworkName: "test-akdc", CIDR: "10.0.0.0/24"},
+					}))
+				})
+			})
+
 			When("cluster has avi_delete_config label", func() {
 				BeforeEach(func() {
 					capicluster.Labels[akoov1alpha1.AviClusterDeleteConfigLabel] = "true"
 				})
 
 				It("deleteConfig True in add_on_secret when cluster has avi_delete_config label set to true", func() {
-					secretData, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 					Expect(err).ShouldNot(HaveOccurred())
-					Expect(secretData).Should(ContainSubstring("delete_config: \"true\""))
+					Expect(values.YAML).Should(ContainSubstring("delete_config: \"true\""))
 				})
 
 				It("deleteConfig False in add_on_secret when cluster has avi_delete_config label set to false", func() {
 					capicluster.Labels[akoov1alpha1.AviClusterDeleteConfigLabel] = "false"
-					secretData, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 					Expect(err).ShouldNot(HaveOccurred())
-					Expect(secretData).Should(ContainSubstring("delete_config: \"false\""))
+					Expect(values.YAML).Should(ContainSubstring("delete_config: \"false\""))
 				})
 
 				It("deleteConfig True in add_on_secret when cluster has avi_delete_config label set to true", func() {
-					secretData, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 					Expect(err).ShouldNot(HaveOccurred())
-					Expect(secretData).Should(ContainSubstring("delete_config: \"true\""))
+					Expect(values.YAML).Should(ContainSubstring("delete_config: \"true\""))
 				})
 
 				It("deleteConfig False in add_on_secret when cluster has avi_delete_config label set to false", func() {
 					delete(capicluster.Labels, akoov1alpha1.AviClusterDeleteConfigLabel)
-					secretData, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+					values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 					Expect(err).ShouldNot(HaveOccurred())
-					Expect(secretData).Should(ContainSubstring("delete_config: \"false\""))
+					Expect(values.YAML).Should(ContainSubstring("delete_config: \"false\""))
 				})
 
 				When("management cluster has avi_delete_config label", func() {
@@ -243,14 +334,14 @@ func unitTestAKODeploymentYaml() {
 					})
 
 					It("deleteConfig always False in add_on_secret", func() {
-						secretData, err := cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+						values, err := cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 						Expect(err).ShouldNot(HaveOccurred())
-						Expect(secretData).Should(ContainSubstring("delete_config: \"false\""))
+						Expect(values.YAML).Should(ContainSubstring("delete_config: \"false\""))
 
 						delete(capicluster.Labels, akoov1alpha1.AviClusterDeleteConfigLabel)
-						secretData, err = cluster.AkoAddonSecretDataYaml(capicluster, akoDeploymentConfig, aviUserSecret)
+						values, err = cluster.NewAKOValues(capicluster, akoDeploymentConfig, aviUserSecret, nil)
 						Expect(err).ShouldNot(HaveOccurred())
-						Expect(secretData).Should(ContainSubstring("delete_config: \"false\""))
+						Expect(values.YAML).Should(ContainSubstring("delete_config: \"false\""))
 					})
 				})
 			})
@@ -0,0 +1,431 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-logr/logr"
+	runv1alpha3 "github.com/vmware-tanzu/tanzu-framework/apis/run/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
+	akoo "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
+)
+
+// Delivery is a backend delivering AKO to the clusters, each
+// AKODeploymentConfig selects one with its Delivery field
+type Delivery interface {
+	// Deliver creates or updates what installs AKO in the cluster with the
+	// values
+	Deliver(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster, obj *akoov1alpha1.AKODeploymentConfig, values *AKOValues) error
+
+	// Remove deletes what Deliver created. It returns false while the
+	// removal is in progress, e.g. while AKO deletes its Avi objects before
+	// it's removed from the cluster.
+	Remove(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster) (bool, error)
+
+	// TriggerCleanup sets deleteConfig in the AKO of the cluster so that it
+	// deletes its Avi objects. It returns false when AKO isn't found in the
+	// cluster.
+	TriggerCleanup(ctx context.Context, log logr.Logger, remoteClient client.Client, cluster *clusterv1.Cluster) (bool, error)
+}
+
+// AKOValues are the AKO values rendered for a cluster
+type AKOValues struct {
+	*ako.Values
+
+	// YAML is the rendering of the values for the TKG add-on
+	YAML string
+
+	// Hash is the sha256 hash of YAML, it's recorded in the status of the
+	// cluster whichever backend delivers AKO
+	Hash string
+
+	// Unsupported are the features asked by the AKODeploymentConfig which
+	// the AKO version of the cluster lacks, they're left out of the values
+	Unsupported []string
+}
+
+// NewAKOValues renders the AKO values of the cluster from the
+// AKODeploymentConfig and the Avi user secret of the cluster. The values only
+// have the settings the AKO version supports, all of them when it's nil.
+func NewAKOValues(cluster *clusterv1.Cluster, obj *akoov1alpha1.AKODeploymentConfig, aviUsersecret *corev1.Secret, version *semver.Version) (*AKOValues, error) {
+	values, err := ako.NewValues(obj, cluster.Namespace+"-"+cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// AKO of a workload cluster with a dedicated Service Engine Group can't
+	// be deployed before the group exists, it would place its Virtual
+	// Services in the shared one
+	if obj.Spec.ServiceEngineGroupPerCluster && cluster.Namespace != akoov1alpha1.TKGSystemNamespace {
+		seg := cluster.Annotations[akoov1alpha1.AviClusterServiceEngineGroupAnnotation]
+		if seg == "" {
+			return nil, errors.New("the Service Engine Group of the cluster is not created yet")
+		}
+		values.LoadBalancerAndIngressService.Config.ControllerSettings.ServiceEngineGroupName = seg
+	}
+
+	// the same goes for a dedicated tenant, AKO would fall back to admin
+	if obj.Spec.Tenant.PerCluster != nil && cluster.Namespace != akoov1alpha1.TKGSystemNamespace {
+		tenant := cluster.Annotations[akoov1alpha1.AviClusterTenantAnnotation]
+		if tenant == "" {
+			return nil, errors.New("the Avi tenant of the cluster is not created yet")
+		}
+		values.LoadBalancerAndIngressService.Config.ControllerSettings.TenantName = tenant
+	}
+
+	// and for a dedicated VIP sub-pool, AKO would allocate the VIPs from the
+	// whole data network
+	if obj.Spec.DataNetwork.ClusterIPPoolSize != 0 && cluster.Namespace != akoov1alpha1.TKGSystemNamespace {
+		ipPool, ok := akoov1alpha1.ParseIPPool(cluster.Annotations[akoov1alpha1.AviClusterIPPoolAnnotation])
+		if !ok {
+			return nil, errors.New("the VIP sub-pool of the cluster is not allocated yet")
+		}
+		if err := values.LoadBalancerAndIngressService.Config.NetworkSettings.ScopeVIPNetwork(obj.Spec.DataNetwork.Name, ipPool); err != nil {
+			return nil, err
+		}
+	}
+
+	//Pass cluster role information to ako
+	//Avoid setting DeleteConfig for management cluster
+	if cluster.Namespace == akoov1alpha1.TKGSystemNamespace {
+		values.LoadBalancerAndIngressService.Config.TkgClusterRole = "management"
+	} else {
+		values.LoadBalancerAndIngressService.Config.TkgClusterRole = "workload"
+		if deleteConfig, exists := cluster.Labels[akoov1alpha1.AviClusterDeleteConfigLabel]; exists {
+			if deleteConfig == "true" {
+				values.LoadBalancerAndIngressService.Config.AKOSettings.DeleteConfig = "true"
+			}
+		}
+	}
+
+	values.LoadBalancerAndIngressService.Config.Avicredentials.Username = string(aviUsersecret.Data["username"][:])
+	values.LoadBalancerAndIngressService.Config.Avicredentials.Password = string(aviUsersecret.Data["password"][:])
+	values.LoadBalancerAndIngressService.Config.Avicredentials.CertificateAuthorityData = string(aviUsersecret.Data[akoov1alpha1.AviCertificateKey][:])
+
+	var unsupported []string
+	if version != nil {
+		unsupported = values.ForVersion(*version)
+	}
+
+	yaml, err := values.YttYaml(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return &AKOValues{Values: values, YAML: yaml, Hash: valuesHash([]byte(yaml)), Unsupported: unsupported}, nil
+}
+
+// akoVersion returns the version of the AKO the delivery backend installs in
+// the cluster: the AKO package of the TKR of the cluster for the TKG add-on
+// secret, the image the manifests deploy otherwise. It returns nil when it
+// can't be told.
+func (r *ClusterReconciler) akoVersion(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	mode akoov1alpha1.AKODelivery,
+) *semver.Version {
+	if mode == akoov1alpha1.AKODeliveryClusterResourceSet || mode == akoov1alpha1.AKODeliveryDirect {
+		version, err := r.ManifestOptions.Version()
+		if err != nil {
+			log.V(3).Info("Can't tell the AKO version from its image", "error", err.Error())
+			return nil
+		}
+		return &version
+	}
+
+	tkrName := cluster.Labels[runv1alpha3.LabelTKR]
+	if bootstrap, err := r.getClusterBootstrap(ctx, cluster); err == nil && bootstrap.Status.ResolvedTKR != "" {
+		tkrName = bootstrap.Status.ResolvedTKR
+	}
+	if tkrName == "" {
+		log.V(3).Info("Can't tell the TKR of the cluster, rendering the values of the latest AKO")
+		return nil
+	}
+	tkr := &runv1alpha3.TanzuKubernetesRelease{}
+	if err := r.Get(ctx, client.ObjectKey{Name: tkrName}, tkr); err != nil {
+		log.V(3).Info("Can't get the TKR of the cluster, rendering the values of the latest AKO", "tkr", tkrName, "error", err.Error())
+		return nil
+	}
+	refName, err := r.GetAKOPackageRefNameFromTKR(log, tkr)
+	if err != nil {
+		log.V(3).Info("Can't tell the AKO package of the TKR, rendering the values of the latest AKO", "tkr", tkrName, "error", err.Error())
+		return nil
+	}
+	version, err := ako.ParsePackageVersion(refName)
+	if err != nil {
+		log.V(3).Info("Can't tell the AKO version of the package", "package", refName, "error", err.Error())
+		return nil
+	}
+	return &version
+}
+
+// newDeliveries returns the built-in delivery backends
+func newDeliveries(r *ClusterReconciler) map[akoov1alpha1.AKODelivery]Delivery {
+	tkgAddon := tkgAddonDelivery{r: r}
+	return map[akoov1alpha1.AKODelivery]Delivery{
+		akoov1alpha1.AKODeliveryTKGAddon:           &tkgAddon,
+		akoov1alpha1.AKODeliveryClusterBootstrap:   &clusterBootstrapDelivery{tkgAddonDelivery: tkgAddon},
+		akoov1alpha1.AKODeliveryClusterResourceSet: &clusterResourceSetDelivery{r: r},
+		akoov1alpha1.AKODeliveryDirect:             &directDelivery{r: r},
+	}
+}
+
+// deliveryMode returns the delivery backend selected by the
+// AKODeploymentConfig for the cluster. By default the ClusterClass based
+// clusters get AKO through their ClusterBootstrap, unless they live in the
+// bootstrap cluster, and the others through a TKG add-on secret.
+func deliveryMode(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) akoov1alpha1.AKODelivery {
+	if obj.Spec.Delivery != "" {
+		return obj.Spec.Delivery
+	}
+	if akoo.IsClusterClassBasedCluster(cluster) && !akoo.IsBootStrapCluster() {
+		return akoov1alpha1.AKODeliveryClusterBootstrap
+	}
+	return akoov1alpha1.AKODeliveryTKGAddon
+}
+
+// clusterDeliveryMode returns the delivery backend AKO was delivered to the
+// cluster with, or the one selected by the AKODeploymentConfig when it isn't
+// recorded
+func clusterDeliveryMode(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) akoov1alpha1.AKODelivery {
+	if status := phases.GetClusterStatus(obj, cluster); status != nil && status.Delivery != "" {
+		return status.Delivery
+	}
+	return deliveryMode(obj, cluster)
+}
+
+// sharesAddonSecret returns whether both delivery backends deliver AKO with
+// the TKG add-on secret, switching between them mustn't delete it
+func sharesAddonSecret(a, b akoov1alpha1.AKODelivery) bool {
+	addon := func(mode akoov1alpha1.AKODelivery) bool {
+		return mode == akoov1alpha1.AKODeliveryTKGAddon || mode == akoov1alpha1.AKODeliveryClusterBootstrap
+	}
+	return addon(a) && addon(b)
+}
+
+func (r *ClusterReconciler) delivery(mode akoov1alpha1.AKODelivery) (Delivery, error) {
+	delivery, ok := r.Deliveries[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown AKO delivery %q", mode)
+	}
+	return delivery, nil
+}
+
+// ReconcileDelivery renders the AKO values of the cluster and delivers them
+// with the backend selected by the AKODeploymentConfig
+func (r *ClusterReconciler) ReconcileDelivery(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	mode := deliveryMode(obj, cluster)
+	log = log.WithValues("delivery", mode)
+	log.Info("Starts reconciling AKO delivery")
+	res := ctrl.Result{}
+	aviSecret, err := r.getClusterAviUserSecret(cluster, ctx)
+	if err != nil {
+		log.Info("Failed to get cluster avi user secret, requeue")
+		return res, err
+	}
+
+	// when avi is ha provider and deploy ako in management cluster, need to wait for
+	// control plane load balancer type of service creating
+	isVIPProvider, err := akoo.IsControlPlaneVIPProvider(cluster)
+	if err != nil {
+		log.Error(err, "can't unmarshal cluster variables")
+		return res, err
+	}
+	if isVIPProvider && cluster.Namespace == akoov1alpha1.TKGSystemNamespace {
+		svc := &corev1.Service{}
+		if err = r.Get(ctx, client.ObjectKey{
+			Name:      cluster.Namespace + "-" + cluster.Name + "-" + akoov1alpha1.HAServiceName,
+			Namespace: akoov1alpha1.TKGSystemNamespace,
+		}, svc); err != nil {
+			log.Info("Failed to get cluster control plane load balancer type of service, requeue")
+			return res, err
+		}
+	}
+
+	//Stop reconciling if cluster ip family doesn't satisfy the condition
+	if err = ValidateClusterIpFamily(cluster, obj, isVIPProvider, log); err != nil {
+		errInfo := "cluster " + cluster.Namespace +
+			"-" + cluster.Name + "'s ip family is not allowed, stop deploying AKO into cluster " + cluster.Namespace + "-" + cluster.Name
+		log.Error(err, errInfo)
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.ClusterIpFamilyInvalidReason, "%s: %v", errInfo, err)
+		clusterCondition := &clusterv1.Condition{
+			Type:    akoov1alpha1.ClusterIpFamilyValidationSucceededCondition,
+			Status:  corev1.ConditionFalse,
+			Message: errInfo,
+		}
+		conditions.Set(cluster, clusterCondition)
+		return res, nil
+	}
+
+	delivery, err := r.delivery(mode)
+	if err != nil {
+		return res, err
+	}
+	version := r.akoVersion(ctx, log, cluster, mode)
+	values, err := NewAKOValues(cluster, obj, aviSecret, version)
+	if err != nil {
+		log.Info("Failed to render the AKO values from AKO Deployment Config, requeue the request")
+		return res, err
+	}
+	if len(values.Unsupported) > 0 {
+		log.Info("AKO of the cluster doesn't support some features, leaving them out", "version", version.String(), "features", values.Unsupported)
+		conditions.MarkFalse(cluster, akoov1alpha1.AKOFeaturesSupportedCondition, akoov1alpha1.AKOFeatureUnsupportedReason,
+			clusterv1.ConditionSeverityWarning, "AKO %s doesn't support %s, they're left out", version, strings.Join(values.Unsupported, ", "))
+	} else if version != nil {
+		conditions.MarkTrue(cluster, akoov1alpha1.AKOFeaturesSupportedCondition)
+	} else {
+		conditions.MarkUnknown(cluster, akoov1alpha1.AKOFeaturesSupportedCondition, akoov1alpha1.AKOVersionUnknownReason,
+			"Can't tell the AKO version of the cluster, the values of the latest AKO are rendered")
+	}
+
+	if previousMode := clusterDeliveryMode(obj, cluster); previousMode != mode && !sharesAddonSecret(previousMode, mode) {
+		previous, err := r.delivery(previousMode)
+		if err != nil {
+			return res, err
+		}
+		log.Info("AKO delivery changed, removing the previous one", "previousDelivery", previousMode)
+		removed, err := previous.Remove(ctx, log, cluster)
+		if err != nil {
+			log.Error(err, "Failed to remove the previous AKO delivery, requeue")
+			return res, err
+		}
+		if !removed {
+			log.Info("The previous AKO delivery is being removed, requeue", "after", requeueAfterForAKODeletion.String())
+			return ctrl.Result{Requeue: true, RequeueAfter: requeueAfterForAKODeletion}, nil
+		}
+	}
+
+	if err := delivery.Deliver(ctx, log, cluster, obj, values); err != nil {
+		return res, err
+	}
+	phases.SetClusterValuesHash(obj, cluster, values.Hash)
+	phases.SetClusterDelivery(obj, cluster, mode)
+	return res, nil
+}
+
+// ReconcileDeliveryDelete removes what the delivery backend of the cluster
+// created, it requeues until the removal finishes
+func (r *ClusterReconciler) ReconcileDeliveryDelete(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	mode := clusterDeliveryMode(obj, cluster)
+	log = log.WithValues("delivery", mode)
+	log.Info("Starts reconciling AKO delivery deletion")
+	res := ctrl.Result{}
+
+	delivery, err := r.delivery(mode)
+	if err != nil {
+		return res, err
+	}
+	removed, err := delivery.Remove(ctx, log, cluster)
+	if err != nil || removed {
+		return res, err
+	}
+	log.Info("AKO delivery removal is in progress, requeue", "after", requeueAfterForAKODeletion.String())
+	return ctrl.Result{Requeue: true, RequeueAfter: requeueAfterForAKODeletion}, nil
+}
+
+// removeAKO removes AKO from the cluster, which stays: AKO is told to delete
+// the Avi objects of the cluster first, then the objects applied to install
+// it are deleted, except the CRDs. It returns false while AKO cleans up,
+// AviResourceCleanupSucceededCondition is false meanwhile so that the cluster
+// keeps its finalizer. A deleted cluster is left to ReconcileDelete.
+func (r *ClusterReconciler) removeAKO(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster) (bool, error) {
+	if !cluster.GetDeletionTimestamp().IsZero() {
+		return true, nil
+	}
+	remoteClient, err := r.GetRemoteClient(ctx, akoov1alpha1.AKODeploymentConfigControllerName, r.Client, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		log.Info("Failed to create remote client for cluster, requeue the request")
+		return false, err
+	}
+
+	found, err := triggerConfigMapCleanup(ctx, log, remoteClient)
+	if err != nil {
+		return false, err
+	}
+	if found {
+		if !conditions.Has(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition) {
+			conditions.MarkFalse(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition, akoov1alpha1.AviResourceCleanupReason,
+				clusterv1.ConditionSeverityInfo, "Cleaning up the AVI load balancing resources before removing AKO")
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, akoov1alpha1.AviResourceCleanupStartedReason,
+				"Cleaning up the Avi load balancing resources before removing AKO")
+		}
+		finished, err := ako.CleanupFinished(ctx, remoteClient, log)
+		if err != nil || !finished {
+			return false, err
+		}
+	}
+
+	removed := false
+	for _, o := range ako.RemovableObjects() {
+		if err := remoteClient.Delete(ctx, o); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "Failed to delete AKO object, requeue", "name", o.GetName())
+			return false, err
+		}
+		removed = true
+	}
+	// AKO may be delivered again, the cleanup of the deleted cluster
+	// mustn't be skipped
+	conditions.Delete(cluster, akoov1alpha1.AviResourceCleanupSucceededCondition)
+	if removed {
+		log.Info("Removed AKO from the cluster")
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, akoov1alpha1.AKORemovedReason,
+			"Removed AKO from the cluster")
+	}
+	return true, nil
+}
+
+// triggerConfigMapCleanup sets deleteConfig in the avi-k8s-config ConfigMap of
+// the cluster, AKO watches it
+func triggerConfigMapCleanup(ctx context.Context, log logr.Logger, remoteClient client.Client) (bool, error) {
+	cm := &corev1.ConfigMap{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{
+		Name:      ako.ConfigMapName,
+		Namespace: akoov1alpha1.AviNamespace,
+	}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		log.Error(err, "Failed to get AKO ConfigMap, AKO clean up failed")
+		return false, err
+	}
+	if cm.Data[ako.DeleteConfigKey] != "true" {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[ako.DeleteConfigKey] = "true"
+		if err := remoteClient.Update(ctx, cm); err != nil {
+			log.Error(err, "Failed to update AKO ConfigMap, AKO clean up failed")
+			return false, err
+		}
+		log.Info("Updated `deleteConfig` field to true in AKO ConfigMap, starting ako clean up")
+	}
+	return true, nil
+}
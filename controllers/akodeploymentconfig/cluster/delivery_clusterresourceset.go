@@ -0,0 +1,137 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+// clusterResourceSetDataKey is the key of the manifests in the Secret of the
+// ClusterResourceSet
+const clusterResourceSetDataKey = "ako.yaml"
+
+// clusterResourceSetDelivery delivers AKO through a Cluster API
+// ClusterResourceSet selecting the cluster by the AKOClusterResourceSetLabel.
+// It uses the Reconcile strategy so that the manifests are applied again
+// whenever the values change.
+type clusterResourceSetDelivery struct {
+	r *ClusterReconciler
+}
+
+func (d *clusterResourceSetDelivery) Deliver(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	values *AKOValues,
+) error {
+	r := d.r
+	manifests, err := values.ManifestsYaml(r.ManifestOptions)
+	if err != nil {
+		return err
+	}
+
+	name := utils.AKOClusterResourceSetName(cluster)
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}}
+	op, err := ctrlutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Annotations[akoov1alpha1.AKOValuesHashAnnotation] = values.Hash
+		secret.Labels[clusterv1.ClusterNameLabel] = cluster.Name
+		secret.Type = addonsv1.ClusterResourceSetSecretType
+		// the ClusterResourceSet applies the manifests again whenever their
+		// hash changes, so they're only written when they differ
+		if !bytes.Equal(secret.Data[clusterResourceSetDataKey], manifests) {
+			secret.Data = map[string][]byte{clusterResourceSetDataKey: manifests}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to create or update the ClusterResourceSet secret, requeue")
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AKODeliveryFailedReason,
+			"Failed to deliver AKO with ClusterResourceSet %s: %v", name, err)
+		return err
+	}
+
+	crs := &addonsv1.ClusterResourceSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}}
+	if _, err := ctrlutil.CreateOrUpdate(ctx, r.Client, crs, func() error {
+		crs.Spec.ClusterSelector = metav1.LabelSelector{
+			MatchLabels: map[string]string{akoov1alpha1.AKOClusterResourceSetLabel: cluster.Name},
+		}
+		crs.Spec.Resources = []addonsv1.ResourceRef{{
+			Name: name,
+			Kind: string(addonsv1.SecretClusterResourceSetResourceKind),
+		}}
+		crs.Spec.Strategy = string(addonsv1.ClusterResourceSetStrategyReconcile)
+		return nil
+	}); err != nil {
+		log.Error(err, "Failed to create or update the ClusterResourceSet, requeue")
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AKODeliveryFailedReason,
+			"Failed to deliver AKO with ClusterResourceSet %s: %v", name, err)
+		return err
+	}
+
+	// the cluster is patched once its phases ran
+	if cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+	cluster.Labels[akoov1alpha1.AKOClusterResourceSetLabel] = cluster.Name
+
+	if op != ctrlutil.OperationResultNone {
+		log.Info("AKO ClusterResourceSet secret "+string(op), "hash", values.Hash)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AKODeliveredReason,
+			"Delivered AKO with ClusterResourceSet %s from AKODeploymentConfig %s", name, obj.Name)
+	}
+	return nil
+}
+
+// Remove deletes the ClusterResourceSet, which leaves what it applied in the
+// cluster, then removes AKO from the cluster
+func (d *clusterResourceSetDelivery) Remove(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+) (bool, error) {
+	delete(cluster.Labels, akoov1alpha1.AKOClusterResourceSetLabel)
+
+	name := utils.AKOClusterResourceSetName(cluster)
+	for _, o := range []client.Object{
+		&addonsv1.ClusterResourceSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}},
+	} {
+		if err := d.r.Delete(ctx, o); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete the AKO ClusterResourceSet, requeue")
+			return false, err
+		}
+	}
+	return d.r.removeAKO(ctx, log, cluster)
+}
+
+// TriggerCleanup sets deleteConfig in the AKO ConfigMap applied in the
+// cluster, the ClusterResourceSet is deleted by then so it doesn't revert it
+func (d *clusterResourceSetDelivery) TriggerCleanup(
+	ctx context.Context,
+	log logr.Logger,
+	remoteClient client.Client,
+	_ *clusterv1.Cluster,
+) (bool, error) {
+	return triggerConfigMapCleanup(ctx, log, remoteClient)
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
+
+// directDeliveryFieldOwner is the field manager of the AKO manifests applied
+// by the Direct delivery
+const directDeliveryFieldOwner = "load-balancer-operator"
+
+// directDelivery server-side applies the AKO manifests in the cluster through
+// its remote client, nothing is created in the management cluster. The
+// manifests are applied on every reconcile so that any change made to them in
+// the cluster is reverted.
+type directDelivery struct {
+	r *ClusterReconciler
+}
+
+func (d *directDelivery) Deliver(
+	ctx context.Context,
+	log logr.Logger,
+	cluster *clusterv1.Cluster,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	values *AKOValues,
+) error {
+	r := d.r
+	remoteClient, err := r.GetRemoteClient(ctx, akoov1alpha1.AKODeploymentConfigControllerName, r.Client, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		log.Info("Failed to create remote client for cluster, requeue the request")
+		return err
+	}
+
+	manifests, err := values.Manifests(r.ManifestOptions)
+	if err != nil {
+		return err
+	}
+	applied := false
+	for _, o := range manifests {
+		kind := o.GetObjectKind().GroupVersionKind().Kind
+		written, drifted, err := applyManifest(ctx, remoteClient, o, values.Hash)
+		if err != nil {
+			log.Error(err, "Failed to apply AKO manifest, requeue", "kind", kind, "name", o.GetName())
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AKODeliveryFailedReason,
+				"Failed to apply AKO %s %s in the cluster: %v", kind, o.GetName(), err)
+			return err
+		}
+		if drifted {
+			log.Info("Corrected the drift of AKO manifest", "kind", kind, "name", o.GetName())
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AKODriftCorrectedReason,
+				"Reverted the changes made to AKO %s %s in the cluster", kind, o.GetName())
+		}
+		applied = applied || written
+	}
+	if applied {
+		log.Info("Applied AKO manifests in the cluster", "hash", values.Hash)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AKODeliveredReason,
+			"Applied AKO in the cluster from AKODeploymentConfig %s", obj.Name)
+	}
+	return nil
+}
+
+// Remove removes AKO from the cluster, nothing was created in the management
+// cluster
+func (d *directDelivery) Remove(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster) (bool, error) {
+	return d.r.removeAKO(ctx, log, cluster)
+}
+
+func (d *directDelivery) TriggerCleanup(
+	ctx context.Context,
+	log logr.Logger,
+	remoteClient client.Client,
+	_ *clusterv1.Cluster,
+) (bool, error) {
+	return triggerConfigMapCleanup(ctx, log, remoteClient)
+}
+
+// applyManifest server-side applies the object in the cluster, annotated with
+// the hash of the values. It returns whether the object was created or its
+// values changed, and whether it drifted: the apply changed an object which
+// already had the current values, so someone else modified it.
+func applyManifest(ctx context.Context, c client.Client, obj client.Object, hash string) (bool, bool, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, false, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	// the server fills those, applying them would claim their ownership
+	unstructured.RemoveNestedField(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[akoov1alpha1.AKOValuesHashAnnotation] = hash
+	u.SetAnnotations(annotations)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, false, err
+		}
+		existing = nil
+	}
+
+	if err := c.Patch(ctx, u, client.Apply, client.FieldOwner(directDeliveryFieldOwner), client.ForceOwnership); err != nil {
+		return false, false, err
+	}
+	if existing == nil {
+		return true, false, nil
+	}
+	if existing.GetAnnotations()[akoov1alpha1.AKOValuesHashAnnotation] != hash {
+		return true, false, nil
+	}
+	return false, existing.GetResourceVersion() != u.GetResourceVersion(), nil
+}
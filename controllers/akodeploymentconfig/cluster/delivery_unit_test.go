@@ -0,0 +1,323 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	runv1alpha3 "github.com/vmware-tanzu/tanzu-framework/apis/run/v1alpha3"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/cluster"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+// applyPatch emulates server-side apply, which the fake client doesn't
+// support: the applied object is created, or updated when it changes the
+// object in the cluster
+func applyPatch(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Patch(ctx, obj, patch, opts...)
+	}
+	applied := obj.(*unstructured.Unstructured)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(applied.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(applied), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return c.Create(ctx, applied)
+	}
+	// decode the numbers the way the stored object has them
+	data, err := applied.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	desired := &unstructured.Unstructured{}
+	if err := desired.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepDerivative(desired.Object, existing.Object) {
+		existing.DeepCopyInto(applied)
+		return nil
+	}
+	applied.SetResourceVersion(existing.GetResourceVersion())
+	return c.Update(ctx, applied)
+}
+
+func unitTestDelivery() {
+	var (
+		ctx          context.Context
+		kclient      client.Client
+		remoteClient client.Client
+		r            *cluster.ClusterReconciler
+		obj          *akoov1alpha1.AKODeploymentConfig
+		capiCluster  *clusterv1.Cluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+		Expect(addonsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(runv1alpha3.AddToScheme(scheme)).To(Succeed())
+		capiCluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				ClusterNetwork: &clusterv1.ClusterNetwork{
+					Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				},
+			},
+		}
+		aviUserSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: utils.AVIUserSecretName(capiCluster), Namespace: "default"},
+			Data: map[string][]byte{
+				"username": []byte("test-cluster-default-ako-user"),
+				"password": []byte("Admin!23"),
+			},
+		}
+		kclient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(capiCluster, aviUserSecret).Build()
+		remoteClient = fake.NewClientBuilder().WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{Patch: applyPatch}).Build()
+		r = cluster.NewReconciler(kclient, ctrl.Log, scheme, record.NewFakeRecorder(10))
+		r.GetRemoteClient = func(context.Context, string, client.Client, client.ObjectKey) (client.Client, error) {
+			return remoteClient, nil
+		}
+		obj = &akoov1alpha1.AKODeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-adc"},
+			Spec: akoov1alpha1.AKODeploymentConfigSpec{
+				CloudName:          "test-cloud",
+				Controller:         "10.23.122.1",
+				ServiceEngineGroup: "Default-SEG",
+				DataNetwork:        akoov1alpha1.DataNetwork{Name: "test-akdc", CIDR: "10.0.0.0/24"},
+			},
+		}
+	})
+
+	reconcile := func() {
+		_, err := r.ReconcileDelivery(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	recordedEvents := func() []string {
+		var events []string
+		for {
+			select {
+			case e := <-r.Recorder.(*record.FakeRecorder).Events:
+				events = append(events, e)
+			default:
+				return events
+			}
+		}
+	}
+
+	exists := func(c client.Client, o client.Object, name, namespace string) bool {
+		return c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, o) == nil
+	}
+
+	It("should deliver with the TKG add-on secret by default", func() {
+		reconcile()
+		Expect(exists(kclient, &corev1.Secret{}, utils.AKOAddonSecretName(capiCluster), "default")).To(BeTrue())
+		Expect(phases.GetClusterStatus(obj, capiCluster).Delivery).To(Equal(akoov1alpha1.AKODeliveryTKGAddon))
+	})
+
+	It("should deliver with a ClusterResourceSet", func() {
+		obj.Spec.Delivery = akoov1alpha1.AKODeliveryClusterResourceSet
+		reconcile()
+		name := utils.AKOClusterResourceSetName(capiCluster)
+
+		crs := &addonsv1.ClusterResourceSet{}
+		Expect(exists(kclient, crs, name, "default")).To(BeTrue())
+		Expect(crs.Spec.Strategy).To(Equal(string(addonsv1.ClusterResourceSetStrategyReconcile)))
+		Expect(crs.Spec.ClusterSelector.MatchLabels).To(HaveKeyWithValue(akoov1alpha1.AKOClusterResourceSetLabel, "test-cluster"))
+		Expect(capiCluster.Labels).To(HaveKeyWithValue(akoov1alpha1.AKOClusterResourceSetLabel, "test-cluster"))
+
+		secret := &corev1.Secret{}
+		Expect(exists(kclient, secret, name, "default")).To(BeTrue())
+		Expect(secret.Type).To(Equal(addonsv1.ClusterResourceSetSecretType))
+		Expect(string(secret.Data["ako.yaml"])).To(ContainSubstring("name: " + ako.ConfigMapName))
+		Expect(phases.GetClusterStatus(obj, capiCluster).ValuesHash).To(Equal(secret.Annotations[akoov1alpha1.AKOValuesHashAnnotation]))
+
+		reconcile()
+		Expect(exists(kclient, &corev1.Secret{}, name, "default")).To(BeTrue())
+
+		By("switching to the TKG add-on secret")
+		obj.Spec.Delivery = akoov1alpha1.AKODeliveryTKGAddon
+		reconcile()
+		Expect(exists(kclient, &addonsv1.ClusterResourceSet{}, name, "default")).To(BeFalse())
+		Expect(exists(kclient, &corev1.Secret{}, name, "default")).To(BeFalse())
+		Expect(capiCluster.Labels).NotTo(HaveKey(akoov1alpha1.AKOClusterResourceSetLabel))
+		Expect(exists(kclient, &corev1.Secret{}, utils.AKOAddonSecretName(capiCluster), "default")).To(BeTrue())
+		Expect(phases.GetClusterStatus(obj, capiCluster).Delivery).To(Equal(akoov1alpha1.AKODeliveryTKGAddon))
+	})
+
+	It("should apply AKO in the cluster and clean it up the same way", func() {
+		obj.Spec.Delivery = akoov1alpha1.AKODeliveryDirect
+		reconcile()
+		Expect(exists(kclient, &corev1.Secret{}, utils.AKOAddonSecretName(capiCluster), "default")).To(BeFalse())
+		Expect(exists(remoteClient, &corev1.Namespace{}, akoov1alpha1.AviNamespace, "")).To(BeTrue())
+		aviSecret := &corev1.Secret{}
+		Expect(exists(remoteClient, aviSecret, ako.SecretName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(string(aviSecret.Data["username"])).To(Equal("test-cluster-default-ako-user"))
+		cm := &corev1.ConfigMap{}
+		Expect(exists(remoteClient, cm, ako.ConfigMapName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(cm.Data).To(HaveKeyWithValue("clusterName", "default-test-cluster"))
+		Expect(cm.Data).To(HaveKeyWithValue(ako.DeleteConfigKey, "false"))
+
+		reconcile()
+		unchanged := &corev1.ConfigMap{}
+		Expect(exists(remoteClient, unchanged, ako.ConfigMapName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(unchanged.ResourceVersion).To(Equal(cm.ResourceVersion))
+		sts := &appsv1.StatefulSet{}
+		Expect(exists(remoteClient, sts, ako.StatefulSetName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(sts.Spec.Template.Spec.Containers[0].Image).To(Equal(ako.DefaultImage))
+		Expect(exists(remoteClient, &networkingv1.IngressClass{}, "avi-lb", "")).To(BeTrue())
+		Expect(exists(remoteClient, &apiextensionsv1.CustomResourceDefinition{}, "hostrules.ako.vmware.com", "")).To(BeTrue())
+
+		By("reverting the changes made in the cluster")
+		unchanged.Data["clusterName"] = "changed"
+		Expect(remoteClient.Update(ctx, unchanged)).To(Succeed())
+		reconcile()
+		Expect(exists(remoteClient, cm, ako.ConfigMapName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(cm.Data).To(HaveKeyWithValue("clusterName", "default-test-cluster"))
+		Expect(recordedEvents()).To(ConsistOf(
+			ContainSubstring(akoov1alpha1.AKODeliveredReason),
+			ContainSubstring(akoov1alpha1.AKODriftCorrectedReason+" Reverted the changes made to AKO ConfigMap "+ako.ConfigMapName),
+		))
+
+		By("deleting the cluster")
+		ctrlutil.AddFinalizer(capiCluster, akoov1alpha1.ClusterFinalizer)
+		capiCluster.DeletionTimestamp = ptr.To(metav1.Now())
+		res, err := r.ReconcileDeliveryDelete(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.IsZero()).To(BeTrue())
+		_, err = r.ReconcileDelete(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists(remoteClient, cm, ako.ConfigMapName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(cm.Data).To(HaveKeyWithValue(ako.DeleteConfigKey, "true"))
+		Expect(conditions.IsTrue(capiCluster, akoov1alpha1.AviResourceCleanupSucceededCondition)).To(BeFalse())
+
+		By("waiting for AKO to finish the cleanup")
+		Expect(exists(remoteClient, sts, ako.StatefulSetName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		sts.Annotations["AviObjectDeletionStatus"] = "Done"
+		Expect(remoteClient.Update(ctx, sts)).To(Succeed())
+		_, err = r.ReconcileDelete(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conditions.IsTrue(capiCluster, akoov1alpha1.AviResourceCleanupSucceededCondition)).To(BeTrue())
+	})
+
+	It("should clean up and remove AKO from a cluster which stays", func() {
+		obj.Spec.Delivery = akoov1alpha1.AKODeliveryDirect
+		reconcile()
+		recordedEvents()
+
+		res, err := r.ReconcileDeliveryDelete(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Requeue).To(BeTrue())
+		cm := &corev1.ConfigMap{}
+		Expect(exists(remoteClient, cm, ako.ConfigMapName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(cm.Data).To(HaveKeyWithValue(ako.DeleteConfigKey, "true"))
+		Expect(conditions.IsFalse(capiCluster, akoov1alpha1.AviResourceCleanupSucceededCondition)).To(BeTrue())
+
+		By("waiting for AKO to finish the cleanup")
+		sts := &appsv1.StatefulSet{}
+		Expect(exists(remoteClient, sts, ako.StatefulSetName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		sts.Annotations["AviObjectDeletionStatus"] = "Done"
+		Expect(remoteClient.Update(ctx, sts)).To(Succeed())
+		res, err = r.ReconcileDeliveryDelete(ctx, ctrl.Log, capiCluster, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(exists(remoteClient, &corev1.Namespace{}, akoov1alpha1.AviNamespace, "")).To(BeFalse())
+		Expect(exists(remoteClient, &networkingv1.IngressClass{}, "avi-lb", "")).To(BeFalse())
+		Expect(exists(remoteClient, &apiextensionsv1.CustomResourceDefinition{}, "hostrules.ako.vmware.com", "")).To(BeTrue())
+		Expect(conditions.Has(capiCluster, akoov1alpha1.AviResourceCleanupSucceededCondition)).To(BeFalse())
+		Expect(recordedEvents()).To(ConsistOf(
+			ContainSubstring(akoov1alpha1.AviResourceCleanupStartedReason),
+			ContainSubstring(akoov1alpha1.AKORemovedReason),
+		))
+	})
+
+	It("should report the features as unknown when the AKO version can't be told", func() {
+		obj.Spec.ExtraConfigs.FeatureGates.GatewayAPI = ptr.To(true)
+		reconcile()
+
+		secret := &corev1.Secret{}
+		Expect(exists(kclient, secret, utils.AKOAddonSecretName(capiCluster), "default")).To(BeTrue())
+		Expect(secret.StringData[akoov1alpha1.TKGAddOnSecretDataKey]).To(ContainSubstring("feature_gates"))
+		Expect(conditions.IsUnknown(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(BeTrue())
+		Expect(conditions.GetReason(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(Equal(akoov1alpha1.AKOVersionUnknownReason))
+	})
+
+	It("should render the values of the AKO package of the TKR", func() {
+		Expect(kclient.Create(ctx, &runv1alpha3.TanzuKubernetesRelease{
+			ObjectMeta: metav1.ObjectMeta{Name: "v1.26.5---vmware.2-tkg.1"},
+			Spec: runv1alpha3.TanzuKubernetesReleaseSpec{
+				BootstrapPackages: []corev1.LocalObjectReference{
+					{Name: "load-balancer-and-ingress-service.tanzu.vmware.com.1.9.3+vmware.1-tkg.1"},
+				},
+			},
+		})).To(Succeed())
+		capiCluster.Labels = map[string]string{runv1alpha3.LabelTKR: "v1.26.5---vmware.2-tkg.1"}
+		obj.Spec.ExtraConfigs.FeatureGates.GatewayAPI = ptr.To(true)
+		reconcile()
+
+		secret := &corev1.Secret{}
+		Expect(exists(kclient, secret, utils.AKOAddonSecretName(capiCluster), "default")).To(BeTrue())
+		Expect(secret.StringData).To(HaveKeyWithValue(akoov1alpha1.TKGAddOnSecretDataKey, ContainSubstring("ako_settings")))
+		Expect(secret.StringData[akoov1alpha1.TKGAddOnSecretDataKey]).NotTo(ContainSubstring("feature_gates"))
+		Expect(conditions.IsFalse(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(BeTrue())
+		Expect(conditions.GetReason(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(Equal(akoov1alpha1.AKOFeatureUnsupportedReason))
+		Expect(conditions.GetMessage(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(ContainSubstring("featureGates.GatewayAPI"))
+
+		By("not asking for Gateway API anymore")
+		obj.Spec.ExtraConfigs.FeatureGates.GatewayAPI = nil
+		reconcile()
+		Expect(conditions.IsTrue(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(BeTrue())
+	})
+
+	It("should render the values of the AKO image applied in the cluster", func() {
+		obj.Spec.Delivery = akoov1alpha1.AKODeliveryDirect
+		obj.Spec.ExtraConfigs.FeatureGates.GatewayAPI = ptr.To(true)
+		r.ManifestOptions.Image = "registry.local/ako/ako:1.10.1"
+		reconcile()
+		sts := &appsv1.StatefulSet{}
+		Expect(exists(remoteClient, sts, ako.StatefulSetName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(conditions.IsFalse(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(BeTrue())
+
+		r.ManifestOptions.Image = ""
+		reconcile()
+		Expect(exists(remoteClient, sts, ako.StatefulSetName, akoov1alpha1.AviNamespace)).To(BeTrue())
+		Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(2))
+		Expect(conditions.IsTrue(capiCluster, akoov1alpha1.AKOFeaturesSupportedCondition)).To(BeTrue())
+	})
+}
@@ -37,4 +37,6 @@ func intgTests() {
 func unitTests() {
 	Describe("AKO Deployment Spec generation", unitTestAKODeploymentYaml)
 	Describe("Cluster ip family Validation", unitTestValidateClusterIpFamily)
+	Describe("AKO add-on secret update", unitTestAddonSecretUpdate)
+	Describe("AKO delivery", unitTestDelivery)
 }
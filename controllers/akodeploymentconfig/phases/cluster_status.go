@@ -0,0 +1,132 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package phases
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+)
+
+// GetClusterStatus returns the status entry of the cluster in the
+// AKODeploymentConfig, or nil if the cluster isn't listed yet
+func GetClusterStatus(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) *akoov1alpha1.ClusterStatus {
+	for i := range obj.Status.Clusters {
+		if obj.Status.Clusters[i].Name == cluster.Name && obj.Status.Clusters[i].Namespace == cluster.Namespace {
+			return &obj.Status.Clusters[i]
+		}
+	}
+	return nil
+}
+
+// valuesHashHistoryLength is how many previous hashes of the AKO values are
+// kept in the status of a cluster
+const valuesHashHistoryLength = 5
+
+// SetClusterValuesHash records the hash of the AKO values rendered for the
+// cluster. It's called by the cluster phases that render AKO values. The
+// hash it replaces is pushed to the history.
+func SetClusterValuesHash(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, hash string) {
+	status := getOrAddClusterStatus(obj, cluster)
+	if status.ValuesHash != "" && status.ValuesHash != hash {
+		history := append([]akoov1alpha1.ValuesHashRecord{{
+			Hash:         status.ValuesHash,
+			ReplacedTime: metav1.Now(),
+		}}, status.ValuesHashHistory...)
+		if len(history) > valuesHashHistoryLength {
+			history = history[:valuesHashHistoryLength]
+		}
+		status.ValuesHashHistory = history
+	}
+	status.ValuesHash = hash
+}
+
+// SetClusterDelivery records the backend AKO was delivered to the cluster
+// with
+func SetClusterDelivery(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, delivery akoov1alpha1.AKODelivery) {
+	getOrAddClusterStatus(obj, cluster).Delivery = delivery
+}
+
+// SetClusterPasswordRotationTime records when the password of the Avi user of
+// the cluster was last changed
+func SetClusterPasswordRotationTime(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, t metav1.Time) {
+	getOrAddClusterStatus(obj, cluster).LastPasswordRotationTime = &t
+}
+
+func getOrAddClusterStatus(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) *akoov1alpha1.ClusterStatus {
+	if status := GetClusterStatus(obj, cluster); status != nil {
+		return status
+	}
+	obj.Status.Clusters = append(obj.Status.Clusters, akoov1alpha1.ClusterStatus{
+		Name:               cluster.Name,
+		Namespace:          cluster.Namespace,
+		Phase:              akoov1alpha1.ClusterPhasePending,
+		LastTransitionTime: metav1.Now(),
+	})
+	return &obj.Status.Clusters[len(obj.Status.Clusters)-1]
+}
+
+// setClusterPhase updates the phase of a cluster status entry, the transition
+// time only moves when the phase actually changes
+func setClusterPhase(status *akoov1alpha1.ClusterStatus, phase akoov1alpha1.ClusterPhase, lastErr error) {
+	if status.Phase != phase {
+		status.Phase = phase
+		status.LastTransitionTime = metav1.Now()
+	}
+	status.LastError = ""
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+	}
+}
+
+// clusterPhase computes the phase of a selected cluster from the outcome of
+// running its phases
+func clusterPhase(
+	obj *akoov1alpha1.AKODeploymentConfig,
+	cluster *clusterv1.Cluster,
+	status *akoov1alpha1.ClusterStatus,
+	previousHash string,
+	requeue bool,
+	err error,
+) akoov1alpha1.ClusterPhase {
+	switch {
+	case err != nil:
+		return akoov1alpha1.ClusterPhaseFailed
+	case !cluster.GetDeletionTimestamp().IsZero() || !obj.GetDeletionTimestamp().IsZero():
+		return akoov1alpha1.ClusterPhaseDeleting
+	case status.ValuesHash == "":
+		return akoov1alpha1.ClusterPhasePending
+	case requeue || status.ValuesHash != previousHash:
+		return akoov1alpha1.ClusterPhaseDeploying
+	default:
+		return akoov1alpha1.ClusterPhaseReady
+	}
+}
+
+// pruneClusterStatus drops the status entries of clusters which are no longer
+// selected and refreshes the summary counters and the selected clusters
+// gauge
+func pruneClusterStatus(obj *akoov1alpha1.AKODeploymentConfig, selected map[string]bool) {
+	var clusters []akoov1alpha1.ClusterStatus
+	var ready, failed int32
+	for _, status := range obj.Status.Clusters {
+		if !selected[status.Namespace+"/"+status.Name] {
+			continue
+		}
+		clusters = append(clusters, status)
+		switch status.Phase {
+		case akoov1alpha1.ClusterPhaseReady:
+			ready++
+		case akoov1alpha1.ClusterPhaseFailed:
+			failed++
+		}
+	}
+	obj.Status.Clusters = clusters
+	obj.Status.SelectedClusters = int32(len(clusters))
+	obj.Status.ReadyClusters = ready
+	obj.Status.FailedClusters = failed
+	metrics.SelectedClusters.WithLabelValues(obj.Name).Set(float64(len(clusters)))
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package phases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
+
+func ClusterStatusUnitTest() {
+	var (
+		ctx     context.Context
+		log     logr.Logger
+		kclient client.Client
+		obj     *akoov1alpha1.AKODeploymentConfig
+		cluster *clusterv1.Cluster
+
+		hashPhase = func(hash string) ReconcileClusterPhase {
+			return func(_ context.Context, _ logr.Logger, c *clusterv1.Cluster, o *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+				SetClusterValuesHash(o, c, hash)
+				return ctrl.Result{}, nil
+			}
+		}
+		errPhase = func(_ context.Context, _ logr.Logger, _ *clusterv1.Cluster, _ *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+			return ctrl.Result{}, errors.New("add-on secret failure")
+		}
+		requeuePhase = func(_ context.Context, _ logr.Logger, _ *clusterv1.Cluster, _ *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+		reconcile = func(phases ...ReconcileClusterPhase) error {
+			_, err := ReconcileClustersPhases(ctx, kclient, log, obj, phases, nil)
+			return err
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		log = ctrl.Log.WithName("test")
+		scheme := runtime.NewScheme()
+		Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+		Expect(akoov1alpha1.AddToScheme(scheme)).To(Succeed())
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "default",
+				Labels:    map[string]string{"test": "true"},
+			},
+		}
+		kclient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+		obj = &akoov1alpha1.AKODeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-adc"},
+			Spec: akoov1alpha1.AKODeploymentConfigSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"test": "true"},
+				},
+			},
+		}
+	})
+
+	When("the values are rendered", func() {
+		It("should move the cluster from Deploying to Ready", func() {
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(obj.Status.Clusters).To(HaveLen(1))
+			Expect(obj.Status.Clusters[0].Name).To(Equal("test-cluster"))
+			Expect(obj.Status.Clusters[0].Namespace).To(Equal("default"))
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseDeploying))
+			Expect(obj.Status.Clusters[0].ValuesHash).To(Equal("hash-1"))
+			Expect(obj.Status.SelectedClusters).To(Equal(int32(1)))
+			Expect(obj.Status.ReadyClusters).To(Equal(int32(0)))
+
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseReady))
+			Expect(obj.Status.ReadyClusters).To(Equal(int32(1)))
+			transitionTime := obj.Status.Clusters[0].LastTransitionTime
+
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(obj.Status.Clusters[0].LastTransitionTime).To(Equal(transitionTime))
+
+			Expect(reconcile(hashPhase("hash-2"))).To(Succeed())
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseDeploying))
+		})
+
+		It("should keep a short history of the previous hashes", func() {
+			Expect(reconcile(hashPhase("hash-0"))).To(Succeed())
+			Expect(reconcile(hashPhase("hash-0"))).To(Succeed())
+			Expect(obj.Status.Clusters[0].ValuesHashHistory).To(BeEmpty())
+			for _, hash := range []string{"hash-1", "hash-2", "hash-3", "hash-4", "hash-5", "hash-6"} {
+				Expect(reconcile(hashPhase(hash))).To(Succeed())
+			}
+			var history []string
+			for _, record := range obj.Status.Clusters[0].ValuesHashHistory {
+				Expect(record.ReplacedTime.IsZero()).To(BeFalse())
+				history = append(history, record.Hash)
+			}
+			Expect(history).To(Equal([]string{"hash-5", "hash-4", "hash-3", "hash-2", "hash-1"}))
+		})
+	})
+
+	When("no values are rendered", func() {
+		It("should keep the cluster Pending", func() {
+			Expect(reconcile()).To(Succeed())
+			Expect(obj.Status.Clusters).To(HaveLen(1))
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhasePending))
+		})
+	})
+
+	When("a phase requests a requeue", func() {
+		It("should report the cluster as Deploying", func() {
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(reconcile(hashPhase("hash-1"), requeuePhase)).To(Succeed())
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseDeploying))
+		})
+	})
+
+	When("a phase fails", func() {
+		It("should record the error until the cluster recovers", func() {
+			Expect(reconcile(hashPhase("hash-1"), errPhase)).NotTo(Succeed())
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseFailed))
+			Expect(obj.Status.Clusters[0].LastError).To(ContainSubstring("add-on secret failure"))
+			Expect(obj.Status.FailedClusters).To(Equal(int32(1)))
+
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseReady))
+			Expect(obj.Status.Clusters[0].LastError).To(BeEmpty())
+			Expect(obj.Status.FailedClusters).To(Equal(int32(0)))
+		})
+	})
+
+	When("the AKODeploymentConfig is being deleted", func() {
+		It("should report the cluster as Deleting", func() {
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			Expect(reconcile()).To(Succeed())
+			Expect(obj.Status.Clusters[0].Phase).To(Equal(akoov1alpha1.ClusterPhaseDeleting))
+		})
+	})
+
+	When("the cluster is no longer selected", func() {
+		It("should drop the cluster from the status", func() {
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(obj.Status.Clusters).To(HaveLen(1))
+
+			obj.Spec.ClusterSelector.MatchLabels = map[string]string{"test": "false"}
+			Expect(reconcile(hashPhase("hash-1"))).To(Succeed())
+			Expect(obj.Status.Clusters).To(BeEmpty())
+			Expect(obj.Status.SelectedClusters).To(Equal(int32(0)))
+		})
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package phases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+)
+
+func NamedPhaseUnitTest() {
+	var (
+		ctx = context.Background()
+		log = ctrl.Log.WithName("test")
+		obj = &akoov1alpha1.AKODeploymentConfig{}
+	)
+
+	It("should record the errors of a phase under its name", func() {
+		phase := Named("testNamedPhase", func(_ context.Context, _ logr.Logger, _ *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+			return ctrl.Result{}, errors.New("avi failure")
+		})
+		_, err := ReconcilePhases(ctx, log, obj, []ReconcilePhase{phase})
+		Expect(err).To(HaveOccurred())
+		Expect(testutil.ToFloat64(metrics.ReconcilePhaseErrors.WithLabelValues("testNamedPhase"))).To(Equal(float64(1)))
+	})
+
+	It("should record the runs of a cluster phase under its name", func() {
+		phase := NamedCluster("testNamedClusterPhase", func(_ context.Context, _ logr.Logger, _ *clusterv1.Cluster, _ *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+			return ctrl.Result{}, nil
+		})
+		_, err := phase(ctx, log, &clusterv1.Cluster{}, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.CollectAndCount(metrics.ReconcilePhaseDuration, "ako_operator_reconcile_phase_duration_seconds")).To(BeNumerically(">=", 1))
+		Expect(testutil.ToFloat64(metrics.ReconcilePhaseErrors.WithLabelValues("testNamedClusterPhase"))).To(Equal(float64(0)))
+	})
+}
@@ -6,6 +6,7 @@ package phases
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -19,6 +20,7 @@ import (
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
 )
 
 // ReconcilePhase defines a function that reconciles one aspect of
@@ -75,9 +77,13 @@ func ReconcileClustersPhases(
 
 	if len(clusters.Items) == 0 {
 		log.Info("No cluster matches the selector, skip")
+		pruneClusterStatus(obj, nil)
 		return res, nil
 	}
 
+	// selected records the clusters whose status should be kept
+	selected := map[string]bool{}
+
 	var allErrs []error
 	// For each cluster managed by the AKODeploymentConfig, run each phase
 	// function
@@ -90,6 +96,8 @@ func ReconcileClustersPhases(
 		if isLBProvider, err := ako_operator.IsLoadBalancerProvider(&cluster); err != nil {
 			log.Error(err, "can't unmarshal cluster variables")
 			allErrs = append(allErrs, err)
+			selected[cluster.Namespace+"/"+cluster.Name] = true
+			setClusterPhase(getOrAddClusterStatus(obj, &cluster), akoov1alpha1.ClusterPhaseFailed, err)
 			continue
 		} else if !isLBProvider {
 			log.Info(fmt.Sprintf("cluster uses kube-vip to provide load balancer type of service, skip reconciling for cluster %s/%s", cluster.Namespace, cluster.Name))
@@ -106,10 +114,14 @@ func ReconcileClustersPhases(
 		// update cluster avi label before run any phase functions
 		ako_operator.ApplyClusterLabel(log, &cluster, obj)
 
+		selected[cluster.Namespace+"/"+cluster.Name] = true
+		previousHash := getOrAddClusterStatus(obj, &cluster).ValuesHash
+
 		phases := normalPhases
 		if !cluster.GetDeletionTimestamp().IsZero() {
 			phases = deletePhases
 		}
+		clusterRes := ctrl.Result{}
 		for _, phase := range phases {
 			// Call the inner reconciliation methods regardless of
 			// the error status
@@ -120,8 +132,9 @@ func ReconcileClustersPhases(
 			if len(errs) > 0 {
 				continue
 			}
-			res = util.LowestNonZeroResult(res, phaseResult)
+			clusterRes = util.LowestNonZeroResult(clusterRes, phaseResult)
 		}
+		res = util.LowestNonZeroResult(res, clusterRes)
 
 		clusterErr := kerrors.NewAggregate(errs)
 		patchOpts := []patch.Option{}
@@ -137,7 +150,42 @@ func ReconcileClustersPhases(
 				log.Error(clusterErr, "patch failed")
 			}
 		}
+
+		// phases may append to the status list, so look the entry up again
+		status := GetClusterStatus(obj, &cluster)
+		setClusterPhase(status, clusterPhase(obj, &cluster, status, previousHash, !clusterRes.IsZero(), clusterErr), clusterErr)
 	}
 
+	pruneClusterStatus(obj, selected)
 	return res, kerrors.NewAggregate(allErrs)
 }
+
+// Named returns phase, its runs are recorded by the reconcile phase metrics
+// under name
+func Named(name string, phase ReconcilePhase) ReconcilePhase {
+	return func(ctx context.Context, log logr.Logger, obj *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+		start := time.Now()
+		res, err := phase(ctx, log, obj)
+		observePhase(name, start, err)
+		return res, err
+	}
+}
+
+// NamedCluster returns phase, its runs are recorded by the reconcile phase
+// metrics under name
+func NamedCluster(name string, phase ReconcileClusterPhase) ReconcileClusterPhase {
+	return func(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster, obj *akoov1alpha1.AKODeploymentConfig) (ctrl.Result, error) {
+		start := time.Now()
+		res, err := phase(ctx, log, cluster, obj)
+		observePhase(name, start, err)
+		return res, err
+	}
+}
+
+// observePhase records the duration and the error of one phase run
+func observePhase(name string, start time.Time, err error) {
+	metrics.ReconcilePhaseDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.ReconcilePhaseErrors.WithLabelValues(name).Inc()
+	}
+}
@@ -51,4 +51,6 @@ func intgTests() {
 }
 
 func unitTests() {
+	Describe("AKODeploymentConfig cluster status", ClusterStatusUnitTest)
+	Describe("Named reconcile phases", NamedPhaseUnitTest)
 }
@@ -36,4 +36,8 @@ func intgTests() {
 
 func unitTests() {
 	Describe("Ensure static ranges Test", unitTestEnsureStaticRanges)
+	Describe("VIP pools utilization Test", unitTestVIPPools)
+	Describe("Cluster IP pools Test", unitTestClusterIPPools)
+	Describe("Service Engine Groups Test", unitTestServiceEngineGroups)
+	Describe("Cluster tenants Test", unitTestClusterTenants)
 }
@@ -4,77 +4,15 @@
 package user
 
 import (
+	"sort"
+
 	"github.com/go-logr/logr"
 	"github.com/vmware/alb-sdk/go/models"
 	"golang.org/x/mod/semver"
 	"k8s.io/utils/ptr"
-)
-
-var AkoRolePermissionMap = map[string]string{
-	"PERMISSION_VIRTUALSERVICE":                "WRITE_ACCESS",
-	"PERMISSION_POOL":                          "WRITE_ACCESS",
-	"PERMISSION_POOLGROUP":                     "WRITE_ACCESS",
-	"PERMISSION_HTTPPOLICYSET":                 "WRITE_ACCESS",
-	"PERMISSION_NETWORKSECURITYPOLICY":         "WRITE_ACCESS",
-	"PERMISSION_AUTOSCALE":                     "WRITE_ACCESS",
-	"PERMISSION_DNSPOLICY":                     "WRITE_ACCESS",
-	"PERMISSION_NETWORKPROFILE":                "WRITE_ACCESS",
-	"PERMISSION_APPLICATIONPROFILE":            "WRITE_ACCESS",
-	"PERMISSION_APPLICATIONPERSISTENCEPROFILE": "WRITE_ACCESS",
-	"PERMISSION_HEALTHMONITOR":                 "WRITE_ACCESS",
-	"PERMISSION_ANALYTICSPROFILE":              "WRITE_ACCESS",
-	"PERMISSION_IPAMDNSPROVIDERPROFILE":        "WRITE_ACCESS",
-	"PERMISSION_CUSTOMIPAMDNSPROFILE":          "WRITE_ACCESS",
-	"PERMISSION_TRAFFICCLONEPROFILE":           "WRITE_ACCESS",
-	"PERMISSION_VSDATASCRIPTSET":               "WRITE_ACCESS",
-	"PERMISSION_PKIPROFILE":                    "WRITE_ACCESS",
-	"PERMISSION_SSLKEYANDCERTIFICATE":          "WRITE_ACCESS",
-	"PERMISSION_SERVICEENGINEGROUP":            "WRITE_ACCESS",
-	"PERMISSION_NETWORK":                       "WRITE_ACCESS",
-	"PERMISSION_VRFCONTEXT":                    "WRITE_ACCESS",
-	"PERMISSION_L4POLICYSET":                   "WRITE_ACCESS",
-
-	"PERMISSION_IPADDRGROUP":                  "READ_ACCESS",
-	"PERMISSION_STRINGGROUP":                  "READ_ACCESS",
-	"PERMISSION_PROTOCOLPARSER":               "READ_ACCESS",
-	"PERMISSION_SSLPROFILE":                   "READ_ACCESS",
-	"PERMISSION_AUTHPROFILE":                  "READ_ACCESS",
-	"PERMISSION_PINGACCESSAGENT":              "READ_ACCESS",
-	"PERMISSION_CERTIFICATEMANAGEMENTPROFILE": "READ_ACCESS",
-	"PERMISSION_HARDWARESECURITYMODULEGROUP":  "READ_ACCESS",
-	"PERMISSION_SSOPOLICY":                    "READ_ACCESS",
-	"PERMISSION_WAFPROFILE":                   "READ_ACCESS",
-	"PERMISSION_WAFPOLICY":                    "READ_ACCESS",
-	"PERMISSION_CLOUD":                        "READ_ACCESS",
-	"PERMISSION_SYSTEMCONFIGURATION":          "READ_ACCESS",
-	"PERMISSION_CONTROLLER":                   "READ_ACCESS",
-	"PERMISSION_TENANT":                       "READ_ACCESS",
 
-	"PERMISSION_NATPOLICY":         "NO_ACCESS",
-	"PERMISSION_WAFPOLICYPSMGROUP": "NO_ACCESS",
-	"PERMISSION_ERRORPAGEPROFILE":  "NO_ACCESS",
-	"PERMISSION_ERRORPAGEBODY":     "NO_ACCESS",
-	"PERMISSION_ALERTCONFIG":       "NO_ACCESS",
-	"PERMISSION_ALERT":             "NO_ACCESS",
-	"PERMISSION_ACTIONGROUPCONFIG": "NO_ACCESS",
-	"PERMISSION_ALERTSYSLOGCONFIG": "NO_ACCESS",
-	"PERMISSION_ALERTEMAILCONFIG":  "NO_ACCESS",
-	"PERMISSION_SNMPTRAPPROFILE":   "NO_ACCESS",
-	"PERMISSION_TRAFFIC_CAPTURE":   "NO_ACCESS",
-	"PERMISSION_SERVICEENGINE":     "NO_ACCESS",
-	"PERMISSION_USER_CREDENTIAL":   "NO_ACCESS",
-	"PERMISSION_REBOOT":            "NO_ACCESS",
-	"PERMISSION_UPGRADE":           "NO_ACCESS",
-	"PERMISSION_TECHSUPPORT":       "NO_ACCESS",
-	"PERMISSION_INTERNAL":          "NO_ACCESS",
-	"PERMISSION_CONTROLLERSITE":    "NO_ACCESS",
-	"PERMISSION_IMAGE":             "NO_ACCESS",
-	"PERMISSION_USER":              "NO_ACCESS",
-	"PERMISSION_ROLE":              "NO_ACCESS",
-	"PERMISSION_GSLB":              "NO_ACCESS",
-	"PERMISSION_GSLBSERVICE":       "NO_ACCESS",
-	"PERMISSION_GSLBGEODBPROFILE":  "NO_ACCESS",
-}
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
 
 var AkoRolePermission = []*models.Permission{
 	{
@@ -341,3 +279,41 @@ func filterAkoRolePermissionByVersion(log logr.Logger, permissions []*models.Per
 	}
 	return filtered
 }
+
+// RoleProfileRoleName returns the name of the Avi role bound to the Avi users
+// of the clusters using the role profile. It holds the hash of the profile so
+// that different profiles with the same name don't share a role.
+func RoleProfileRoleName(profile *akoov1alpha1.AviRoleProfile) string {
+	if profile == nil {
+		return akoov1alpha1.AkoUserRoleName
+	}
+	return akoov1alpha1.AkoUserRoleName + "-" + profile.Name + "-" + profile.RoleProfile().Hash()
+}
+
+// RoleProfilePermissionMap returns the permissions of the Avi role of the
+// role profile, see utils.RoleProfile.PermissionMap
+func RoleProfilePermissionMap(profile *akoov1alpha1.AviRoleProfile) (map[string]string, error) {
+	return profile.RoleProfile().PermissionMap()
+}
+
+// rolePermissions returns the permissions of the Avi role of the role
+// profile sorted by resource, the default role keeps the order of
+// AkoRolePermission
+func rolePermissions(profile *akoov1alpha1.AviRoleProfile, permissionMap map[string]string) []*models.Permission {
+	if profile == nil {
+		return AkoRolePermission
+	}
+	resources := make([]string, 0, len(permissionMap))
+	for resource := range permissionMap {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	permissions := make([]*models.Permission, 0, len(resources))
+	for _, resource := range resources {
+		permissions = append(permissions, &models.Permission{
+			Resource: ptr.To(resource),
+			Type:     ptr.To(permissionMap[resource]),
+		})
+	}
+	return permissions
+}
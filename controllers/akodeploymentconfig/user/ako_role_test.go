@@ -4,23 +4,105 @@
 package user
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 )
 
 func TestRolePermissionAndMapMatch(t *testing.T) {
-	if len(AkoRolePermission) != len(AkoRolePermissionMap) {
-		t.Errorf("len(AkoRolePermission) == %d, len(AkoRolePermissionMap) == %d", len(AkoRolePermission), len(AkoRolePermissionMap))
+	if len(AkoRolePermission) != len(utils.AkoRolePermissionMap) {
+		t.Errorf("len(AkoRolePermission) == %d, len(utils.AkoRolePermissionMap) == %d", len(AkoRolePermission), len(utils.AkoRolePermissionMap))
 	}
 
 	allMatch := true
 	for _, permission := range AkoRolePermission {
-		if *permission.Type != AkoRolePermissionMap[*permission.Resource] {
+		if *permission.Type != utils.AkoRolePermissionMap[*permission.Resource] {
 			allMatch = false
-			t.Logf("AkoRolePermission[%s] == %s, AkoRolePermissionMap[%s] == %s", *permission.Resource, *permission.Type, *permission.Resource, AkoRolePermissionMap[*permission.Resource])
+			t.Logf("AkoRolePermission[%s] == %s, utils.AkoRolePermissionMap[%s] == %s", *permission.Resource, *permission.Type, *permission.Resource, utils.AkoRolePermissionMap[*permission.Resource])
 		}
 	}
 
 	if !allMatch {
-		t.Error("Not all entries in AkoRolePermission and AkoRolePermissionMap match")
+		t.Error("Not all entries in AkoRolePermission and utils.AkoRolePermissionMap match")
+	}
+}
+
+func TestRoleProfilePermissionMap(t *testing.T) {
+	permissions, err := RoleProfilePermissionMap(nil)
+	if err != nil {
+		t.Fatalf("RoleProfilePermissionMap(nil) returned %v", err)
+	}
+	if !reflect.DeepEqual(permissions, utils.AkoRolePermissionMap) {
+		t.Error("RoleProfilePermissionMap(nil) differs from utils.AkoRolePermissionMap")
+	}
+
+	profile := &akoov1alpha1.AviRoleProfile{
+		Name: "l4",
+		Permissions: []akoov1alpha1.AviPermission{
+			{Resource: "PERMISSION_HTTPPOLICYSET", Type: "READ_ACCESS"},
+			{Resource: "PERMISSION_SECURITYPOLICY", Type: "READ_ACCESS"},
+		},
+		RemovedPermissions: []string{"PERMISSION_PKIPROFILE"},
+	}
+	permissions, err = RoleProfilePermissionMap(profile)
+	if err != nil {
+		t.Fatalf("RoleProfilePermissionMap(%s) returned %v", profile.Name, err)
+	}
+	for resource, expected := range map[string]string{
+		"PERMISSION_HTTPPOLICYSET":  "READ_ACCESS",
+		"PERMISSION_SECURITYPOLICY": "READ_ACCESS",
+		"PERMISSION_PKIPROFILE":     "NO_ACCESS",
+		"PERMISSION_VIRTUALSERVICE": "WRITE_ACCESS",
+	} {
+		if permissions[resource] != expected {
+			t.Errorf("permissions[%s] == %s, expected %s", resource, permissions[resource], expected)
+		}
+	}
+	if utils.AkoRolePermissionMap["PERMISSION_PKIPROFILE"] != "WRITE_ACCESS" {
+		t.Error("RoleProfilePermissionMap modified utils.AkoRolePermissionMap")
+	}
+	name := RoleProfileRoleName(profile)
+	if !strings.HasPrefix(name, "ako-essential-role-l4-") {
+		t.Errorf("RoleProfileRoleName(%s) == %s", profile.Name, name)
+	}
+	other := profile.DeepCopy()
+	other.RemovedPermissions = nil
+	if RoleProfileRoleName(other) == name {
+		t.Errorf("RoleProfileRoleName(%s) is the same for different profiles", profile.Name)
+	}
+	if RoleProfileRoleName(profile.DeepCopy()) != name {
+		t.Errorf("RoleProfileRoleName(%s) differs for the same profile", profile.Name)
+	}
+}
+
+func TestRoleProfilePermissionMapRejectsInvalidProfiles(t *testing.T) {
+	for _, profile := range []*akoov1alpha1.AviRoleProfile{
+		{
+			Name:        "upgrade",
+			Permissions: []akoov1alpha1.AviPermission{{Resource: "PERMISSION_USER", Type: "WRITE_ACCESS"}},
+		},
+		{
+			Name:        "downgrade",
+			Permissions: []akoov1alpha1.AviPermission{{Resource: "PERMISSION_VIRTUALSERVICE", Type: "NO_ACCESS"}},
+		},
+		{
+			Name:               "required",
+			RemovedPermissions: []string{"PERMISSION_VIRTUALSERVICE"},
+		},
+		{
+			Name:               "unknown-removed",
+			RemovedPermissions: []string{"PERMISSION_UNKNOWN"},
+		},
+		{
+			Name:        "unknown",
+			Permissions: []akoov1alpha1.AviPermission{{Resource: "VIRTUALSERVICE", Type: "READ_ACCESS"}},
+		},
+	} {
+		if _, err := RoleProfilePermissionMap(profile); err == nil {
+			t.Errorf("RoleProfilePermissionMap(%s) should return an error", profile.Name)
+		}
 	}
 }
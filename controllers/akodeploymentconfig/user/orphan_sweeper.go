@@ -0,0 +1,263 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package user
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+const (
+	orphanKindUser   = "user"
+	orphanKindSecret = "secret"
+)
+
+// OrphanSweeper deletes the Avi users and the avi-credentials Secrets of the
+// clusters which are gone without going through the user cleanup, e.g. because
+// they were force-deleted or their finalizer was removed by hand. The users
+// are recognized by the full name the operator of the management cluster
+// gives them, so the users of the other management clusters sharing the Avi
+// Controller are never swept, and the Secrets by their cluster label and
+// their AKODeploymentConfig owner.
+type OrphanSweeper struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+	// ManagementClusterID is the marker of the Avi users of this management
+	// cluster
+	ManagementClusterID string
+
+	// Period is how often the Avi Controller of an AKODeploymentConfig is
+	// swept
+	Period time.Duration
+	// GracePeriod is how long a user or a Secret has to be orphaned before
+	// it's deleted
+	GracePeriod time.Duration
+	// DryRun only reports the orphans through events and metrics
+	DryRun bool
+
+	mu sync.Mutex
+	// lastSweep is when the Avi Controller of each AKODeploymentConfig was
+	// last swept
+	lastSweep map[string]time.Time
+	// orphanedSince is when each orphan was first seen
+	orphanedSince map[string]time.Time
+	now           func() time.Time
+}
+
+// NewOrphanSweeper returns an OrphanSweeper
+func NewOrphanSweeper(c client.Client, recorder record.EventRecorder, managementClusterID string, period, gracePeriod time.Duration, dryRun bool) *OrphanSweeper {
+	return &OrphanSweeper{
+		Client:              c,
+		Recorder:            recorder,
+		ManagementClusterID: managementClusterID,
+		Period:              period,
+		GracePeriod:         gracePeriod,
+		DryRun:              dryRun,
+		lastSweep:           map[string]time.Time{},
+		orphanedSince:       map[string]time.Time{},
+		now:                 time.Now,
+	}
+}
+
+// Sweep sweeps the Avi Controller of the AKODeploymentConfig when it's due and
+// returns when the next sweep is
+func (s *OrphanSweeper) Sweep(
+	ctx context.Context,
+	log logr.Logger,
+	aviClient aviclient.Client,
+	obj *akoov1alpha1.AKODeploymentConfig,
+) (ctrl.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if last, ok := s.lastSweep[obj.Name]; ok && now.Sub(last) < s.Period {
+		return ctrl.Result{RequeueAfter: last.Add(s.Period).Sub(now)}, nil
+	}
+	res := ctrl.Result{RequeueAfter: s.Period}
+
+	clusters := &clusterv1.ClusterList{}
+	if err := s.Client.List(ctx, clusters); err != nil {
+		log.Error(err, "Failed to list clusters, skip the orphan sweep")
+		return res, err
+	}
+	liveUsers := sets.New[string]()
+	liveClusters := sets.New[string]()
+	for i := range clusters.Items {
+		liveUsers.Insert(utils.AviUserName(&clusters.Items[i]), utils.AviAlternateUserName(&clusters.Items[i]))
+		liveClusters.Insert(clusters.Items[i].Namespace + "/" + clusters.Items[i].Name)
+	}
+
+	log = log.WithValues("dryRun", s.DryRun)
+	errs := []error{
+		s.sweepUsers(log, aviClient, obj, liveUsers, now),
+		s.sweepSecrets(ctx, log, obj, liveClusters, now),
+	}
+	s.lastSweep[obj.Name] = now
+	return res, kerrors.NewAggregate(errs)
+}
+
+// Forget drops the state and the metrics of a deleted AKODeploymentConfig
+func (s *OrphanSweeper) Forget(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lastSweep, name)
+	prefix := orphanKindSecret + "/" + name + "/"
+	for key := range s.orphanedSince {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.orphanedSince, key)
+		}
+	}
+	metrics.OrphanedResources.DeletePartialMatch(map[string]string{"akodeploymentconfig": name})
+}
+
+// sweepUsers deletes the Avi users created by the operator of this management
+// cluster whose cluster is gone, the other users are never touched
+func (s *OrphanSweeper) sweepUsers(
+	log logr.Logger,
+	aviClient aviclient.Client,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	liveUsers sets.Set[string],
+	now time.Time,
+) error {
+	users, err := aviClient.UserList()
+	if err != nil {
+		log.Error(err, "Failed to list Avi users, skip the orphan sweep")
+		return err
+	}
+	fullName := aviUserFullName(s.ManagementClusterID)
+	var orphans []string
+	for _, user := range users {
+		name := ptr.Deref(user.Name, "")
+		if ptr.Deref(user.FullName, "") == fullName && !liveUsers.Has(name) {
+			orphans = append(orphans, name)
+		}
+	}
+	metrics.OrphanedResources.WithLabelValues(obj.Name, orphanKindUser).Set(float64(len(orphans)))
+
+	var errs []error
+	for _, name := range s.expired(obj, orphanKindUser, obj.Spec.Controller, orphans, now) {
+		if err := aviClient.UserDeleteByName(name); err != nil && !aviclient.IsAviUserNonExistentError(err) {
+			log.Error(err, "Failed to delete orphaned Avi user", "user", name)
+			s.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.OrphanDeleteFailedReason,
+				"Failed to delete orphaned Avi user %s: %v", name, err)
+			errs = append(errs, err)
+			continue
+		}
+		log.Info("Deleted orphaned Avi user", "user", name)
+		s.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.OrphanDeletedReason,
+			"Deleted orphaned Avi user %s", name)
+		metrics.OrphanedResourcesDeleted.WithLabelValues(orphanKindUser).Inc()
+		s.forget(orphanKindUser, obj.Spec.Controller, name)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// sweepSecrets deletes the avi-credentials Secrets of the workload clusters of
+// the AKODeploymentConfig which are gone, they're listed by the label holding
+// the name of their cluster and each is swept under the AKODeploymentConfig
+// owning it only
+func (s *OrphanSweeper) sweepSecrets(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	liveClusters sets.Set[string],
+	now time.Time,
+) error {
+	secrets := &corev1.SecretList{}
+	if err := s.Client.List(ctx, secrets, client.HasLabels{akoov1alpha1.AviUserSecretClusterLabel}); err != nil {
+		log.Error(err, "Failed to list avi-credentials secrets, skip the orphan sweep")
+		return err
+	}
+	var orphans []string
+	for _, secret := range secrets.Items {
+		if secret.Type != akoov1alpha1.AviClusterSecretType || !metav1.IsControlledBy(&secret, obj) {
+			continue
+		}
+		clusterName := secret.Labels[akoov1alpha1.AviUserSecretClusterLabel]
+		if !liveClusters.Has(secret.Namespace + "/" + clusterName) {
+			orphans = append(orphans, secret.Namespace+"/"+secret.Name)
+		}
+	}
+	metrics.OrphanedResources.WithLabelValues(obj.Name, orphanKindSecret).Set(float64(len(orphans)))
+
+	var errs []error
+	for _, key := range s.expired(obj, orphanKindSecret, obj.Name, orphans, now) {
+		namespace, name, _ := strings.Cut(key, "/")
+		secret := &corev1.Secret{}
+		secret.Namespace, secret.Name = namespace, name
+		if err := s.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete orphaned secret", "secret", key)
+			s.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.OrphanDeleteFailedReason,
+				"Failed to delete orphaned Secret %s: %v", key, err)
+			errs = append(errs, err)
+			continue
+		}
+		log.Info("Deleted orphaned secret", "secret", key)
+		s.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.OrphanDeletedReason,
+			"Deleted orphaned Secret %s", key)
+		metrics.OrphanedResourcesDeleted.WithLabelValues(orphanKindSecret).Inc()
+		s.forget(orphanKindSecret, obj.Name, key)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// expired records when the orphans were first seen, reports the new ones and
+// returns the ones orphaned for longer than the grace period, none in dry-run
+// mode. The orphans of the scope which are no longer orphaned are forgotten.
+func (s *OrphanSweeper) expired(obj *akoov1alpha1.AKODeploymentConfig, kind, scope string, orphans []string, now time.Time) []string {
+	prefix := kind + "/" + scope + "/"
+	current := sets.New[string]()
+	var expired []string
+	for _, orphan := range orphans {
+		key := prefix + orphan
+		current.Insert(key)
+		since, ok := s.orphanedSince[key]
+		if !ok {
+			since = now
+			s.orphanedSince[key] = now
+			s.Recorder.Eventf(obj, corev1.EventTypeWarning, akoov1alpha1.OrphanFoundReason,
+				"Found orphaned %s %s, its cluster is gone%s", kind, orphan, s.reportSuffix())
+		}
+		if !s.DryRun && now.Sub(since) >= s.GracePeriod {
+			expired = append(expired, orphan)
+		}
+	}
+	for key := range s.orphanedSince {
+		if strings.HasPrefix(key, prefix) && !current.Has(key) {
+			delete(s.orphanedSince, key)
+		}
+	}
+	return expired
+}
+
+func (s *OrphanSweeper) reportSuffix() string {
+	if s.DryRun {
+		return ", dry-run: it won't be deleted"
+	}
+	return ", it will be deleted after " + s.GracePeriod.String()
+}
+
+func (s *OrphanSweeper) forget(kind, scope, orphan string) {
+	delete(s.orphanedSince, kind+"/"+scope+"/"+orphan)
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package user
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+)
+
+func OrphanSweeperTest() {
+	var (
+		ctx          context.Context
+		now          time.Time
+		adc          *akoov1alpha1.AKODeploymentConfig
+		aviClient    *aviclient.FakeAviClient
+		recorder     *record.FakeRecorder
+		sweeper      *OrphanSweeper
+		deletedUsers []string
+	)
+
+	// secret returns an avi-credentials Secret of the AKODeploymentConfig,
+	// labeled with its cluster unless it's empty
+	secret := func(name, cluster, adcName, adcUID string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: akoov1alpha1.AkoDeploymentConfigVersion,
+					Kind:       akoov1alpha1.AkoDeploymentConfigKind,
+					Name:       adcName,
+					UID:        types.UID(adcUID),
+					Controller: ptr.To(true),
+				}},
+			},
+			Type: akoov1alpha1.AviClusterSecretType,
+		}
+		if cluster != "" {
+			s.Labels = map[string]string{akoov1alpha1.AviUserSecretClusterLabel: cluster}
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		adc = &akoov1alpha1.AKODeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-adc", UID: "test-adc-uid"},
+			Spec:       akoov1alpha1.AKODeploymentConfigSpec{Controller: "10.0.0.1"},
+		}
+		deletedUsers = nil
+		aviClient = aviclient.NewFakeAviClient()
+		aviClient.User.SetGetAllUserFunc(func(...session.ApiOptionsParams) ([]*models.User, error) {
+			return []*models.User{
+				{Name: ptr.To("live-default-ako-user"), FullName: ptr.To(aviUserFullName("mgmt-uid"))},
+				{Name: ptr.To("gone-default-ako-user"), FullName: ptr.To(aviUserFullName("mgmt-uid"))},
+				{Name: ptr.To("other-default-ako-user"), FullName: ptr.To(aviUserFullName("other-mgmt-uid"))},
+				{Name: ptr.To("legacy-default-ako-user"), FullName: ptr.To(akoov1alpha1.AkoUserFullName)},
+				{Name: ptr.To("unmanaged-default-ako-user")},
+				{Name: ptr.To("admin")},
+			}, nil
+		})
+		aviClient.User.SetDeleteByNameUserFunc(func(name string, _ ...session.ApiOptionsParams) error {
+			deletedUsers = append(deletedUsers, name)
+			return nil
+		})
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default"}},
+			secret("live-avi-credentials", "live", "test-adc", "test-adc-uid"),
+			secret("gone-avi-credentials", "gone", "test-adc", "test-adc-uid"),
+			secret("mgmt-avi-credentials", "", "test-adc", "test-adc-uid"),
+			secret("other-avi-credentials", "other", "other-adc", "other-adc-uid"),
+		).Build()
+		recorder = record.NewFakeRecorder(10)
+		sweeper = NewOrphanSweeper(c, recorder, "mgmt-uid", 10*time.Minute, time.Hour, false)
+		sweeper.now = func() time.Time { return now }
+	})
+
+	secretExists := func(name string) bool {
+		err := sweeper.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, &corev1.Secret{})
+		return err == nil
+	}
+
+	It("should delete the orphans once the grace period elapsed", func() {
+		res, err := sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(10 * time.Minute))
+		Expect(deletedUsers).To(BeEmpty())
+		Expect(secretExists("gone-avi-credentials")).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(2))
+
+		now = now.Add(30 * time.Minute)
+		_, err = sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletedUsers).To(BeEmpty())
+
+		now = now.Add(30 * time.Minute)
+		_, err = sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletedUsers).To(ConsistOf("gone-default-ako-user"))
+		Expect(secretExists("gone-avi-credentials")).To(BeFalse())
+		Expect(secretExists("live-avi-credentials")).To(BeTrue())
+		Expect(secretExists("mgmt-avi-credentials")).To(BeTrue())
+		Expect(secretExists("other-avi-credentials")).To(BeTrue())
+	})
+
+	It("should only sweep the Secrets of the clusters of the AKODeploymentConfig", func() {
+		other := &akoov1alpha1.AKODeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-adc", UID: "other-adc-uid"},
+			Spec:       adc.Spec,
+		}
+		_, err := sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(metrics.OrphanedResources.WithLabelValues(adc.Name, orphanKindSecret))).To(Equal(float64(1)))
+		_, err = sweeper.Sweep(ctx, ctrl.Log, aviClient, other)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(metrics.OrphanedResources.WithLabelValues(other.Name, orphanKindSecret))).To(Equal(float64(1)))
+		// the orphaned user was reported by the first sweep, each Secret
+		// once by its own AKODeploymentConfig
+		Expect(recorder.Events).To(HaveLen(3))
+
+		now = now.Add(2 * time.Hour)
+		_, err = sweeper.Sweep(ctx, ctrl.Log, aviClient, other)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secretExists("other-avi-credentials")).To(BeFalse())
+		Expect(secretExists("gone-avi-credentials")).To(BeTrue())
+		sweeper.Forget(other.Name)
+	})
+
+	It("should not sweep before the period elapsed", func() {
+		_, err := sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		now = now.Add(2 * time.Hour)
+		sweeper.lastSweep[adc.Name] = now.Add(-time.Minute)
+		res, err := sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(9 * time.Minute))
+		Expect(deletedUsers).To(BeEmpty())
+	})
+
+	It("should only report the orphans in dry-run mode", func() {
+		sweeper.DryRun = true
+		_, err := sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		now = now.Add(2 * time.Hour)
+		_, err = sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletedUsers).To(BeEmpty())
+		Expect(secretExists("gone-avi-credentials")).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(2))
+		Expect(<-recorder.Events).To(ContainSubstring("dry-run"))
+	})
+
+	It("should restart the grace period of an orphan which came back", func() {
+		_, err := sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sweeper.Client.Create(ctx, &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "gone", Namespace: "default"},
+		})).To(Succeed())
+		now = now.Add(30 * time.Minute)
+		_, err = sweeper.Sweep(ctx, ctrl.Log, aviClient, adc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sweeper.orphanedSince).To(BeEmpty())
+	})
+}
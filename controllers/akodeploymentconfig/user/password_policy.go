@@ -0,0 +1,43 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package user
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+// generatePassword generates a password for the Avi user of the Cluster with
+// the password policy of the AKODeploymentConfig. The policy is checked
+// against the password complexity settings of the Avi Controller first, the
+// controller would refuse the user otherwise.
+func (r *AkoUserReconciler) generatePassword(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) (string, error) {
+	policy := obj.Spec.AviUserPasswordPolicy.PasswordPolicy().Merge(r.PasswordPolicy)
+	password, err := r.generatePasswordWithPolicy(policy)
+	if err != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviUserSyncFailedReason,
+			"Failed to generate the Avi user password: %v", err)
+		return "", err
+	}
+	return password, nil
+}
+
+func (r *AkoUserReconciler) generatePasswordWithPolicy(policy utils.PasswordPolicy) (string, error) {
+	sysConfig, err := r.aviClient.SystemConfigurationGet()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the password complexity settings of the Avi Controller")
+	}
+	if portal := sysConfig.PortalConfiguration; portal != nil {
+		minLength := int(ptr.Deref(portal.MinimumPasswordLength, 0))
+		if err := policy.CheckComplexity(minLength, ptr.Deref(portal.PasswordStrengthCheck, false)); err != nil {
+			return "", errors.Wrap(err, "the password policy doesn't meet the Avi Controller settings")
+		}
+	}
+	return utils.GeneratePassword(policy)
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package user
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+func PasswordPolicyTest() {
+	var (
+		adc       *akoov1alpha1.AKODeploymentConfig
+		cluster   *clusterv1.Cluster
+		aviClient *aviclient.FakeAviClient
+		recorder  *record.FakeRecorder
+		r         *AkoUserReconciler
+	)
+
+	BeforeEach(func() {
+		adc = &akoov1alpha1.AKODeploymentConfig{}
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		}
+		aviClient = aviclient.NewFakeAviClient()
+		recorder = record.NewFakeRecorder(10)
+		r = NewProvider(fake.NewClientBuilder().Build(), aviClient, ctrl.Log, nil, recorder)
+		r.PasswordPolicy = utils.PasswordPolicy{Length: 20, ExcludedCharacters: "|"}
+	})
+
+	It("should use the policy of the operator by default", func() {
+		password, err := r.generatePassword(adc, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password).To(HaveLen(20))
+		Expect(password).NotTo(ContainSubstring("|"))
+	})
+
+	It("should let the AKODeploymentConfig override the policy of the operator", func() {
+		adc.Spec.AviUserPasswordPolicy = &akoov1alpha1.AviPasswordPolicy{
+			Length:           12,
+			CharacterClasses: []akoov1alpha1.PasswordCharacterClass{"Digit"},
+		}
+		password, err := r.generatePassword(adc, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password).To(HaveLen(12))
+		Expect(strings.Trim(password, "0123456789")).To(BeEmpty())
+	})
+
+	It("should refuse a policy the Avi Controller doesn't accept", func() {
+		aviClient.SystemConfiguration.PortalConfiguration = &models.PortalConfiguration{
+			MinimumPasswordLength: ptr.To[uint32](24),
+			PasswordStrengthCheck: ptr.To(true),
+		}
+		_, err := r.generatePassword(adc, cluster)
+		Expect(err).To(MatchError(ContainSubstring("the Avi Controller requires 24")))
+		Expect(<-recorder.Events).To(ContainSubstring(akoov1alpha1.AviUserSyncFailedReason))
+
+		adc.Spec.AviUserPasswordPolicy = &akoov1alpha1.AviPasswordPolicy{Length: 24}
+		_, err = r.generatePassword(adc, cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
+)
+
+// A password rotation alternates between two Avi users, so that AKO always
+// holds valid credentials. It goes through the Secret in the management
+// cluster:
+//
+//  1. the other user and its new password are stored under pendingUsername
+//     and pendingPassword, next to the current ones which are still rendered
+//     into the AKO values
+//  2. the other user is created or updated with the pending password, the
+//     current user is left alone
+//  3. the pending user replaces the current one, which is recorded under
+//     previousUsername. The AKO values are rendered from it by the Cluster
+//     phases which run after the Avi phases.
+//  4. once the Cluster is ready with the new values, the previous user is
+//     deleted
+//
+// A rotation interrupted at any step resumes with the same pending password,
+// so the Avi users never end up with a password that isn't recorded in the
+// management cluster.
+
+// previousUserGracePeriod is how long the previous Avi user is kept after the
+// AKO values switched to the new one, the delivery backend may take a while to
+// roll them out
+const previousUserGracePeriod = 15 * time.Minute
+
+// passwordRotationDue returns whether the password of the Avi user of the
+// Cluster should be rotated now
+func passwordRotationDue(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, mcSecret *corev1.Secret, now time.Time) bool {
+	if _, ok := cluster.Annotations[akoov1alpha1.AviClusterRotatePasswordAnnotation]; ok {
+		return true
+	}
+	interval := obj.Spec.AviUserPasswordRotationInterval
+	if interval == nil {
+		return false
+	}
+	return !now.Before(lastPasswordRotationTime(obj, cluster, mcSecret).Add(interval.Duration))
+}
+
+// lastPasswordRotationTime returns when the password of the Avi user of the
+// Cluster last changed, the Secret in the management cluster was created
+// along with the first password when the status doesn't tell
+func lastPasswordRotationTime(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, mcSecret *corev1.Secret) time.Time {
+	if status := phases.GetClusterStatus(obj, cluster); status != nil && status.LastPasswordRotationTime != nil {
+		return status.LastPasswordRotationTime.Time
+	}
+	return mcSecret.CreationTimestamp.Time
+}
+
+// recordLastPasswordRotationTime records when the password of the Avi user of
+// the Cluster last changed in the status, the clusters created by older
+// releases don't have it so their rotations are scheduled as well
+func recordLastPasswordRotationTime(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, mcSecret *corev1.Secret) {
+	if status := phases.GetClusterStatus(obj, cluster); status == nil || status.LastPasswordRotationTime == nil {
+		phases.SetClusterPasswordRotationTime(obj, cluster, metav1.NewTime(lastPasswordRotationTime(obj, cluster, mcSecret)))
+	}
+}
+
+// startPasswordRotation records the other Avi user of the Cluster with a new
+// pending password in the Secret, a rotation which is already in progress is
+// left alone
+func (r *AkoUserReconciler) startPasswordRotation(
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	cluster *clusterv1.Cluster,
+	mcSecret *corev1.Secret,
+) error {
+	if _, ok := mcSecret.Data[akoov1alpha1.AviPendingPasswordKey]; ok {
+		// rotations started by older releases only recorded the password
+		if _, ok := mcSecret.Data[akoov1alpha1.AviPendingUsernameKey]; !ok {
+			mcSecret.Data[akoov1alpha1.AviPendingUsernameKey] = []byte(alternateAviUserName(cluster, mcSecret))
+		}
+		return nil
+	}
+	log.Info("Rotating the AVI user password")
+	password, err := r.generatePassword(obj, cluster)
+	if err != nil {
+		return err
+	}
+	pendingUsername := alternateAviUserName(cluster, mcSecret)
+	mcSecret.Data[akoov1alpha1.AviPendingUsernameKey] = []byte(pendingUsername)
+	mcSecret.Data[akoov1alpha1.AviPendingPasswordKey] = []byte(password)
+	// the previous user is reused when it isn't deleted yet
+	if string(mcSecret.Data[akoov1alpha1.AviPreviousUsernameKey]) == pendingUsername {
+		delete(mcSecret.Data, akoov1alpha1.AviPreviousUsernameKey)
+	}
+	return nil
+}
+
+// completePasswordRotation makes the pending user, which already uses the
+// pending password, the current one. The current user becomes the previous
+// one, which AKO keeps using until the new values are rolled out.
+func (r *AkoUserReconciler) completePasswordRotation(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	cluster *clusterv1.Cluster,
+	mcSecret *corev1.Secret,
+) error {
+	mcSecret.Data[akoov1alpha1.AviPreviousUsernameKey] = mcSecret.Data["username"]
+	mcSecret.Data["username"] = mcSecret.Data[akoov1alpha1.AviPendingUsernameKey]
+	mcSecret.Data["password"] = mcSecret.Data[akoov1alpha1.AviPendingPasswordKey]
+	delete(mcSecret.Data, akoov1alpha1.AviPendingUsernameKey)
+	delete(mcSecret.Data, akoov1alpha1.AviPendingPasswordKey)
+	if err := r.Client.Update(ctx, mcSecret); err != nil {
+		log.Error(err, "Failed to update avi-credentials secret with the rotated password, requeue")
+		return err
+	}
+	phases.SetClusterPasswordRotationTime(obj, cluster, metav1.Now())
+	delete(cluster.Annotations, akoov1alpha1.AviClusterRotatePasswordAnnotation)
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserPasswordRotatedReason,
+		"Rotated the credentials of the cluster to Avi user %s", string(mcSecret.Data["username"]))
+	return nil
+}
+
+// retirePreviousAviUser deletes the Avi user the Cluster used before the last
+// rotation once AKO was rolled out with the new one
+func (r *AkoUserReconciler) retirePreviousAviUser(
+	ctx context.Context,
+	log logr.Logger,
+	obj *akoov1alpha1.AKODeploymentConfig,
+	cluster *clusterv1.Cluster,
+	mcSecret *corev1.Secret,
+	now time.Time,
+) error {
+	previous := string(mcSecret.Data[akoov1alpha1.AviPreviousUsernameKey])
+	if previous == "" || !previousAviUserUnused(obj, cluster, now) {
+		return nil
+	}
+	if err := r.aviClient.UserDeleteByName(previous); err != nil && !aviclient.IsAviUserNonExistentError(err) {
+		log.Error(err, "Failed to delete the previous avi user, requeue", "user", previous)
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviUserDeleteFailedReason,
+			"Failed to delete Avi user %s: %v", previous, err)
+		return err
+	}
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserDeletedReason, "Deleted Avi user %s", previous)
+	delete(mcSecret.Data, akoov1alpha1.AviPreviousUsernameKey)
+	return nil
+}
+
+// previousAviUserUnused returns whether AKO was rolled out with the values
+// rendered after the last rotation, for long enough
+func previousAviUserUnused(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster, now time.Time) bool {
+	status := phases.GetClusterStatus(obj, cluster)
+	if status == nil || status.LastPasswordRotationTime == nil || status.Phase != akoov1alpha1.ClusterPhaseReady ||
+		len(status.ValuesHashHistory) == 0 {
+		return false
+	}
+	rotated := status.LastPasswordRotationTime.Time
+	return !status.ValuesHashHistory[0].ReplacedTime.Time.Before(rotated) &&
+		!now.Before(rotated.Add(previousUserGracePeriod))
+}
+
+// alternateAviUserName returns the Avi user of the Cluster which isn't the
+// current one
+func alternateAviUserName(cluster *clusterv1.Cluster, mcSecret *corev1.Secret) string {
+	if string(mcSecret.Data["username"]) == utils.AviAlternateUserName(cluster) {
+		return utils.AviUserName(cluster)
+	}
+	return utils.AviAlternateUserName(cluster)
+}
+
+// PasswordRotationRequeueAfter returns how long until the next scheduled
+// password rotation of the Clusters of the AKODeploymentConfig, or until the
+// previous Avi user of a rotation can be deleted, zero when none is scheduled
+func PasswordRotationRequeueAfter(obj *akoov1alpha1.AKODeploymentConfig, now time.Time) time.Duration {
+	if obj.Spec.WorkloadCredentialRef != nil {
+		return 0
+	}
+	interval := obj.Spec.AviUserPasswordRotationInterval
+	var next time.Duration
+	schedule := func(at time.Time) {
+		after := at.Sub(now)
+		if after <= 0 {
+			after = time.Second
+		}
+		if next == 0 || after < next {
+			next = after
+		}
+	}
+	for _, status := range obj.Status.Clusters {
+		// the user phase records it for every workload cluster
+		if status.LastPasswordRotationTime == nil {
+			continue
+		}
+		if retireAt := status.LastPasswordRotationTime.Add(previousUserGracePeriod); retireAt.After(now) {
+			schedule(retireAt)
+		}
+		if interval != nil {
+			schedule(status.LastPasswordRotationTime.Add(interval.Duration))
+		}
+	}
+	return next
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package user
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/session"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+func PasswordRotationTest() {
+	var (
+		now      time.Time
+		adc      *akoov1alpha1.AKODeploymentConfig
+		cluster  *clusterv1.Cluster
+		mcSecret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		adc = &akoov1alpha1.AKODeploymentConfig{}
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		}
+		mcSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-cluster-avi-credentials",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+			},
+			Data: map[string][]byte{
+				"username": []byte("test-cluster-default-ako-user"),
+				"password": []byte("current"),
+			},
+		}
+	})
+
+	Context("passwordRotationDue", func() {
+		It("should not rotate without an interval", func() {
+			Expect(passwordRotationDue(adc, cluster, mcSecret, now)).To(BeFalse())
+		})
+		It("should rotate when forced by the annotation", func() {
+			cluster.Annotations = map[string]string{akoov1alpha1.AviClusterRotatePasswordAnnotation: ""}
+			Expect(passwordRotationDue(adc, cluster, mcSecret, now)).To(BeTrue())
+		})
+		It("should rotate once the interval elapsed since the last rotation", func() {
+			adc.Spec.AviUserPasswordRotationInterval = &metav1.Duration{Duration: 24 * time.Hour}
+			adc.Status.Clusters = []akoov1alpha1.ClusterStatus{{
+				Name:                     cluster.Name,
+				Namespace:                cluster.Namespace,
+				LastPasswordRotationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			}}
+			Expect(passwordRotationDue(adc, cluster, mcSecret, now)).To(BeFalse())
+			Expect(passwordRotationDue(adc, cluster, mcSecret, now.Add(23*time.Hour))).To(BeTrue())
+		})
+		It("should fall back to the creation of the secret", func() {
+			adc.Spec.AviUserPasswordRotationInterval = &metav1.Duration{Duration: 24 * time.Hour}
+			Expect(passwordRotationDue(adc, cluster, mcSecret, now)).To(BeTrue())
+		})
+	})
+
+	Context("PasswordRotationRequeueAfter", func() {
+		It("should schedule the next rotation", func() {
+			adc.Spec.AviUserPasswordRotationInterval = &metav1.Duration{Duration: 24 * time.Hour}
+			adc.Status.Clusters = []akoov1alpha1.ClusterStatus{
+				{Name: "a", LastPasswordRotationTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+				{Name: "b", LastPasswordRotationTime: &metav1.Time{Time: now.Add(-2 * time.Hour)}},
+				{Name: "c"},
+			}
+			Expect(PasswordRotationRequeueAfter(adc, now)).To(Equal(22 * time.Hour))
+		})
+		It("should schedule the deletion of the previous user", func() {
+			adc.Spec.AviUserPasswordRotationInterval = &metav1.Duration{Duration: 24 * time.Hour}
+			adc.Status.Clusters = []akoov1alpha1.ClusterStatus{
+				{Name: "a", LastPasswordRotationTime: &metav1.Time{Time: now.Add(-5 * time.Minute)}},
+			}
+			Expect(PasswordRotationRequeueAfter(adc, now)).To(Equal(10 * time.Minute))
+		})
+		It("should not schedule anything without an interval", func() {
+			adc.Status.Clusters = []akoov1alpha1.ClusterStatus{
+				{Name: "a", LastPasswordRotationTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			}
+			Expect(PasswordRotationRequeueAfter(adc, now)).To(BeZero())
+		})
+	})
+
+	Context("recordLastPasswordRotationTime", func() {
+		It("should record the creation of the secret for the clusters of older releases", func() {
+			recordLastPasswordRotationTime(adc, cluster, mcSecret)
+			Expect(adc.Status.Clusters).To(HaveLen(1))
+			Expect(adc.Status.Clusters[0].LastPasswordRotationTime.Time).To(Equal(mcSecret.CreationTimestamp.Time))
+
+			adc.Spec.AviUserPasswordRotationInterval = &metav1.Duration{Duration: 72 * time.Hour}
+			Expect(PasswordRotationRequeueAfter(adc, now)).To(Equal(24 * time.Hour))
+		})
+	})
+
+	Context("completePasswordRotation", func() {
+		var (
+			r          *AkoUserReconciler
+			aviClient  *aviclient.FakeAviClient
+			deleted    []string
+			rotateTime metav1.Time
+		)
+
+		BeforeEach(func() {
+			deleted = nil
+			aviClient = aviclient.NewFakeAviClient()
+			aviClient.User.SetDeleteByNameUserFunc(func(name string, options ...session.ApiOptionsParams) error {
+				deleted = append(deleted, name)
+				return nil
+			})
+			r = NewProvider(fake.NewClientBuilder().WithObjects(mcSecret).Build(), aviClient, ctrl.Log, nil, record.NewFakeRecorder(10))
+			Expect(r.startPasswordRotation(ctrl.Log, adc, cluster, mcSecret)).To(Succeed())
+		})
+
+		It("should rotate to the other user", func() {
+			Expect(string(mcSecret.Data[akoov1alpha1.AviPendingUsernameKey])).To(Equal("test-cluster-default-ako-user-alt"))
+			Expect(string(mcSecret.Data["username"])).To(Equal("test-cluster-default-ako-user"))
+			Expect(string(mcSecret.Data["password"])).To(Equal("current"))
+		})
+
+		It("should keep the pending password of a rotation in progress", func() {
+			pending := string(mcSecret.Data[akoov1alpha1.AviPendingPasswordKey])
+			Expect(pending).NotTo(BeEmpty())
+			Expect(pending).NotTo(Equal("current"))
+			Expect(r.startPasswordRotation(ctrl.Log, adc, cluster, mcSecret)).To(Succeed())
+			Expect(string(mcSecret.Data[akoov1alpha1.AviPendingPasswordKey])).To(Equal(pending))
+		})
+
+		It("should promote the pending password", func() {
+			pending := mcSecret.Data[akoov1alpha1.AviPendingPasswordKey]
+			cluster.Annotations = map[string]string{akoov1alpha1.AviClusterRotatePasswordAnnotation: ""}
+			Expect(r.completePasswordRotation(context.Background(), ctrl.Log, adc, cluster, mcSecret)).To(Succeed())
+
+			secret := &corev1.Secret{}
+			Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(mcSecret), secret)).To(Succeed())
+			Expect(secret.Data["password"]).To(Equal(pending))
+			Expect(string(secret.Data["username"])).To(Equal("test-cluster-default-ako-user-alt"))
+			Expect(string(secret.Data[akoov1alpha1.AviPreviousUsernameKey])).To(Equal("test-cluster-default-ako-user"))
+			Expect(secret.Data).NotTo(HaveKey(akoov1alpha1.AviPendingPasswordKey))
+			Expect(secret.Data).NotTo(HaveKey(akoov1alpha1.AviPendingUsernameKey))
+			Expect(cluster.Annotations).NotTo(HaveKey(akoov1alpha1.AviClusterRotatePasswordAnnotation))
+			Expect(adc.Status.Clusters).To(HaveLen(1))
+			Expect(adc.Status.Clusters[0].LastPasswordRotationTime).NotTo(BeNil())
+		})
+
+		It("should only delete the previous user once AKO got the new one", func() {
+			Expect(r.completePasswordRotation(context.Background(), ctrl.Log, adc, cluster, mcSecret)).To(Succeed())
+			rotateTime = *adc.Status.Clusters[0].LastPasswordRotationTime
+			later := rotateTime.Add(previousUserGracePeriod)
+
+			// the values rendered from the new user are still rolled out
+			adc.Status.Clusters[0].Phase = akoov1alpha1.ClusterPhaseDeploying
+			adc.Status.Clusters[0].ValuesHashHistory = []akoov1alpha1.ValuesHashRecord{{Hash: "old", ReplacedTime: rotateTime}}
+			Expect(r.retirePreviousAviUser(context.Background(), ctrl.Log, adc, cluster, mcSecret, later)).To(Succeed())
+			Expect(deleted).To(BeEmpty())
+
+			// within the grace period
+			adc.Status.Clusters[0].Phase = akoov1alpha1.ClusterPhaseReady
+			Expect(r.retirePreviousAviUser(context.Background(), ctrl.Log, adc, cluster, mcSecret, rotateTime.Time)).To(Succeed())
+			Expect(deleted).To(BeEmpty())
+
+			Expect(r.retirePreviousAviUser(context.Background(), ctrl.Log, adc, cluster, mcSecret, later)).To(Succeed())
+			Expect(deleted).To(Equal([]string{"test-cluster-default-ako-user"}))
+			Expect(mcSecret.Data).NotTo(HaveKey(akoov1alpha1.AviPreviousUsernameKey))
+		})
+
+		It("should reuse the previous user when rotating again", func() {
+			Expect(r.completePasswordRotation(context.Background(), ctrl.Log, adc, cluster, mcSecret)).To(Succeed())
+			Expect(r.startPasswordRotation(ctrl.Log, adc, cluster, mcSecret)).To(Succeed())
+			Expect(string(mcSecret.Data[akoov1alpha1.AviPendingUsernameKey])).To(Equal("test-cluster-default-ako-user"))
+			Expect(mcSecret.Data).NotTo(HaveKey(akoov1alpha1.AviPreviousUsernameKey))
+		})
+	})
+}
@@ -55,4 +55,7 @@ func intgTests() {
 
 func unitTests() {
 	Describe("AKO user reconciler unit tests", SyncAkoUserRoleTest)
+	Describe("Avi user password rotation unit tests", PasswordRotationTest)
+	Describe("Avi user password policy unit tests", PasswordPolicyTest)
+	Describe("Orphaned Avi user sweeper unit tests", OrphanSweeperTest)
 }
@@ -5,16 +5,22 @@ package user
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
 	"golang.org/x/mod/semver"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -22,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/phases"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 )
@@ -32,6 +39,14 @@ type AkoUserReconciler struct {
 	aviClient aviclient.Client
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+
+	// PasswordPolicy is the password policy of the operator, the
+	// AKODeploymentConfigs override it field by field
+	PasswordPolicy utils.PasswordPolicy
+	// ManagementClusterID tells apart the Avi users of this management
+	// cluster from the ones of the others sharing the Avi Controller
+	ManagementClusterID string
 }
 
 // NewProvider returns AKOUserReconciler object.
@@ -39,12 +54,14 @@ func NewProvider(client client.Client,
 	aviClient aviclient.Client,
 	logger logr.Logger,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 ) *AkoUserReconciler {
 	return &AkoUserReconciler{
 		Client:    client,
 		aviClient: aviClient,
 		Log:       logger,
 		Scheme:    scheme,
+		Recorder:  recorder,
 	}
 }
 
@@ -136,10 +153,41 @@ func (r *AkoUserReconciler) reconcileAviUserDelete(
 		}
 	}
 
-	if err := r.aviClient.UserDeleteByName(string(secret.Data["username"])); err != nil {
+	username := string(secret.Data["username"])
+	if err := r.aviClient.UserDeleteByName(username); err != nil {
 		log.Error(err, "Failed to delete avi user account in avi controller, requeue")
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviUserDeleteFailedReason,
+			"Failed to delete Avi user %s: %v", username, err)
 		return res, err
 	}
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserDeletedReason, "Deleted Avi user %s", username)
+	// so is the other user of a password rotation
+	for _, key := range []string{akoov1alpha1.AviPendingUsernameKey, akoov1alpha1.AviPreviousUsernameKey} {
+		other := string(secret.Data[key])
+		if other == "" {
+			continue
+		}
+		if err := r.aviClient.UserDeleteByName(other); err != nil && !aviclient.IsAviUserNonExistentError(err) {
+			log.Error(err, "Failed to delete avi user account in avi controller, requeue", "user", other)
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviUserDeleteFailedReason,
+				"Failed to delete Avi user %s: %v", other, err)
+			return res, err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserDeletedReason, "Deleted Avi user %s", other)
+	}
+	// so is the role of the role profile once no user is bound to it, the
+	// role of a per-cluster tenant goes with the tenant
+	if obj.Spec.RoleProfile != nil && obj.Spec.Tenant.PerCluster == nil {
+		role, err := r.aviClient.RoleGetByName(RoleProfileRoleName(obj.Spec.RoleProfile))
+		if err == nil {
+			err = r.deleteUnusedRole(log, cluster, ptr.Deref(role.UUID, ""))
+		}
+		if err != nil && !aviclient.IsAviRoleNonExistentError(err) {
+			log.Error(err, "Failed to delete unused Avi role", "role", RoleProfileRoleName(obj.Spec.RoleProfile))
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviRoleDeleteFailedReason,
+				"Failed to delete unused Avi role %s: %v", RoleProfileRoleName(obj.Spec.RoleProfile), err)
+		}
+	}
 
 	if err := r.Client.Delete(ctx, secret); err != nil {
 		if !apierrors.IsGone(err) {
@@ -205,10 +253,14 @@ func (r *AkoUserReconciler) reconcileAviUserNormal(
 		}, mcSecret); err != nil {
 			if apierrors.IsNotFound(err) {
 
-				aviUsername := cluster.Name + "-" + cluster.Namespace + "-ako-user"
+				aviUsername := utils.AviUserName(cluster)
 				// This can only happen once no matter how many times we
 				// enter the reconciliation
-				aviPassword := utils.GenereatePassword(10, true, true, true, true)
+				aviPassword, err := r.generatePassword(obj, cluster)
+				if err != nil {
+					log.Error(err, "Failed to generate the AVI user password, requeue")
+					return res, err
+				}
 
 				mcSecret = r.createAviUserSecret(
 					mcSecretName,
@@ -219,40 +271,100 @@ func (r *AkoUserReconciler) reconcileAviUserNormal(
 					obj,
 					false,
 				)
+				// the orphan sweeper lists the Secrets by this label
+				metav1.SetMetaDataLabel(&mcSecret.ObjectMeta, akoov1alpha1.AviUserSecretClusterLabel, cluster.Name)
 				log.Info("No AVI Secret found for cluster in the management cluster, start the creation")
 				if err := r.Client.Create(ctx, mcSecret); err != nil {
 					log.Error(err, "Failed to create AVI secret for Cluster in the management cluster, requeue")
 					return res, err
 				}
+				phases.SetClusterPasswordRotationTime(obj, cluster, metav1.Now())
 			} else {
 				log.Error(err, "Failed to get cluster avi user secret, requeue")
 				return res, err
 			}
 		} else {
+			now := time.Now()
+			recordLastPasswordRotationTime(obj, cluster, mcSecret)
 			// controller certificate can be updated by the user
 			mcSecret.Data[akoov1alpha1.AviCertificateKey] = []byte(aviCA)
+			// the Secrets created before they were labeled get the label
+			// the orphan sweeper lists them by
+			metav1.SetMetaDataLabel(&mcSecret.ObjectMeta, akoov1alpha1.AviUserSecretClusterLabel, cluster.Name)
+			if err := r.retirePreviousAviUser(ctx, log, obj, cluster, mcSecret, now); err != nil {
+				return res, err
+			}
+			if passwordRotationDue(obj, cluster, mcSecret, now) {
+				if err := r.startPasswordRotation(log, obj, cluster, mcSecret); err != nil {
+					log.Error(err, "Failed to generate the rotated AVI user password, requeue")
+					return res, err
+				}
+			}
 			if err := r.Client.Update(ctx, mcSecret); err != nil {
 				log.Error(err, "Failed to update avi-credentials secret, requeue")
 				return res, err
 			}
 		}
 
-		// Use the value from the management cluster
-		aviUsername := string(mcSecret.Data["username"][:])
-		aviPassword := string(mcSecret.Data["password"][:])
-
-		// ensures the AVI User exists and matches the mc secret
-		if err = r.createOrUpdateAviUser(log, aviUsername, aviPassword, obj.Spec.Tenant.Name); err != nil {
-			log.Error(err, "Failed to create/update cluster avi user")
+		tenantName, dedicatedTenant, err := aviUserTenant(obj, cluster)
+		if err != nil {
+			log.Info("Wait for the Avi tenant of the cluster, requeue")
 			return res, err
-		} else {
-			log.Info("Successfully created/updated AVI User in AVI Controller")
+		}
+
+		// ensures the AVI User exists and matches the mc secret, as well as
+		// the user a rotation switches to
+		aviUsers := [][2]string{{string(mcSecret.Data["username"]), string(mcSecret.Data["password"])}}
+		pendingPassword, rotating := mcSecret.Data[akoov1alpha1.AviPendingPasswordKey]
+		if rotating {
+			aviUsers = append(aviUsers, [2]string{string(mcSecret.Data[akoov1alpha1.AviPendingUsernameKey]), string(pendingPassword)})
+		}
+		// Avi never returns the password of a user, the Secret records the
+		// hashes of the passwords synced to the Avi Controller instead
+		synced := sets.New(strings.Split(mcSecret.Annotations[akoov1alpha1.AviUserPasswordHashAnnotation], ",")...)
+		var hashes []string
+		for _, aviUser := range aviUsers {
+			hash := aviUserPasswordHash(aviUser[0], aviUser[1])
+			if err = r.createOrUpdateAviUser(log, cluster, aviUser[0], aviUser[1], synced.Has(hash), tenantName, dedicatedTenant, obj.Spec.RoleProfile); err != nil {
+				log.Error(err, "Failed to create/update cluster avi user")
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviUserSyncFailedReason,
+					"Failed to create or update Avi user %s: %v", aviUser[0], err)
+				return res, err
+			}
+			hashes = append(hashes, hash)
+		}
+		log.Info("Successfully created/updated AVI User in AVI Controller")
+		if mcSecret.Annotations[akoov1alpha1.AviUserPasswordHashAnnotation] != strings.Join(hashes, ",") {
+			metav1.SetMetaDataAnnotation(&mcSecret.ObjectMeta, akoov1alpha1.AviUserPasswordHashAnnotation, strings.Join(hashes, ","))
+			if err := r.Client.Update(ctx, mcSecret); err != nil {
+				log.Error(err, "Failed to update avi-credentials secret, requeue")
+				return res, err
+			}
+		}
+
+		if rotating {
+			if err := r.completePasswordRotation(ctx, log, obj, cluster, mcSecret); err != nil {
+				return res, err
+			}
 		}
 	}
 
 	return res, nil
 }
 
+// aviUserTenant returns the tenant of the Avi user of the Cluster and whether
+// the tenant is dedicated to the Cluster
+func aviUserTenant(obj *akoov1alpha1.AKODeploymentConfig, cluster *clusterv1.Cluster) (string, bool, error) {
+	if obj.Spec.Tenant.PerCluster == nil {
+		return obj.Spec.Tenant.Name, false, nil
+	}
+	name := cluster.Annotations[akoov1alpha1.AviClusterTenantAnnotation]
+	if name == "" {
+		return "", true, errors.New("the Avi tenant of the cluster is not created yet")
+	}
+	return name, true, nil
+}
+
 // getAVIControllerCA get avi certificateAuthority secret
 func (r *AkoUserReconciler) getAVIControllerCA(ctx context.Context, obj *akoov1alpha1.AKODeploymentConfig) (*corev1.Secret, error) {
 	aviControllerCA := &corev1.Secret{}
@@ -263,8 +375,28 @@ func (r *AkoUserReconciler) getAVIControllerCA(ctx context.Context, obj *akoov1a
 	return aviControllerCA, err
 }
 
-// createOrUpdateAviUser create an avi user in avi controller
-func (r *AkoUserReconciler) createOrUpdateAviUser(log logr.Logger, aviUsername, aviPassword, tenantName string) error {
+// aviUserPasswordHash returns the hash of the credentials of an Avi user
+// recorded in the avi-credentials Secret once they're synced
+func aviUserPasswordHash(username, password string) string {
+	h := sha256.Sum256([]byte(username + "\x00" + password))
+	return hex.EncodeToString(h[:])
+}
+
+// aviUserFullName returns the full name of the Avi users the operator of the
+// management cluster creates, the orphan sweeper recognizes them by it
+func aviUserFullName(managementClusterID string) string {
+	if managementClusterID == "" {
+		return akoov1alpha1.AkoUserFullName
+	}
+	return akoov1alpha1.AkoUserFullName + " of management cluster " + managementClusterID
+}
+
+// createOrUpdateAviUser create an avi user in avi controller, events are
+// recorded on the cluster the user belongs to. The password of an existing
+// user is only updated when it isn't synced yet. The user is bound to the Avi
+// role of the role profile, which lives in the tenant when it's dedicated to
+// the cluster.
+func (r *AkoUserReconciler) createOrUpdateAviUser(log logr.Logger, cluster *clusterv1.Cluster, aviUsername, aviPassword string, passwordSynced bool, tenantName string, dedicatedTenant bool, profile *akoov1alpha1.AviRoleProfile) error {
 	version, err := r.aviClient.GetControllerVersion()
 	if err != nil {
 		return err
@@ -273,6 +405,10 @@ func (r *AkoUserReconciler) createOrUpdateAviUser(log logr.Logger, aviUsername,
 	if len(version) > 0 && version[0] != 'v' {
 		version = "v" + version
 	}
+	var roleOptions []session.ApiOptionsParams
+	if dedicatedTenant {
+		roleOptions = append(roleOptions, session.SetOptTenant(tenantName))
+	}
 
 	aviUser, err := r.aviClient.UserGetByName(aviUsername)
 	// user not found, create one
@@ -282,16 +418,17 @@ func (r *AkoUserReconciler) createOrUpdateAviUser(log logr.Logger, aviUsername,
 		if tenantName == "" {
 			tenantName = "admin"
 		}
-		tenant, err := r.aviClient.TenantGet(tenantName)
+		tenant, err := r.aviClient.TenantGetByName(tenantName)
 		if err != nil {
 			return err
 		}
-		role, err := r.getOrCreateAkoUserRole(log, tenant.URL, version)
+		role, err := r.getOrCreateAkoUserRole(log, cluster, tenant.URL, profile, version, roleOptions...)
 		if err != nil {
 			return err
 		}
 		aviUser = &models.User{
 			Name:             &aviUsername,
+			FullName:         ptr.To(aviUserFullName(r.ManagementClusterID)),
 			Password:         &aviPassword,
 			DefaultTenantRef: tenant.URL,
 			Access: []*models.UserRole{
@@ -309,81 +446,184 @@ func (r *AkoUserReconciler) createOrUpdateAviUser(log logr.Logger, aviUsername,
 		if _, err := r.aviClient.UserCreate(aviUser); err != nil {
 			return err
 		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserCreatedReason,
+			"Created Avi user %s in tenant %s", aviUsername, tenantName)
 		return nil
 	} else if err != nil {
 		log.Info("Failed to get AVI User", "user", aviUsername, "error", err)
 		return err
 	}
 
-	// ensure user's role align with latest essential permission when user found
-	if _, err := r.ensureAkoUserRole(log, version); err != nil {
+	// ensure user's role align with latest essential permission when user
+	// found, the role of a new profile is created in the default tenant of
+	// the user
+	role, err := r.getOrCreateAkoUserRole(log, cluster, aviUser.DefaultTenantRef, profile, version, roleOptions...)
+	if err != nil {
 		return err
 	}
+	previousRoleRef, roleBound := bindAkoUserRole(aviUser, role.URL)
 	// Update the password when user found, this is needed when the AVI user was
 	// created before the mc Secret. And this operation will sync
 	// the User's password to be the same as mc Secret's
-	if aviUser.Password == nil || *aviUser.Password != aviPassword {
-		log.Info("AVI User found, updating the password")
-		aviUser.Password = &aviPassword
-		if _, err := r.aviClient.UserUpdate(aviUser); err != nil {
-			return err
+	passwordUpdated := !passwordSynced
+	// users created before they were marked get the full name which tells
+	// the orphan sweeper they're the operator's
+	unmarked := ptr.Deref(aviUser.FullName, "") != aviUserFullName(r.ManagementClusterID)
+	if !passwordUpdated && !roleBound && !unmarked {
+		return nil
+	}
+	log.Info("AVI User found, updating the password and the role")
+	aviUser.Password = &aviPassword
+	aviUser.FullName = ptr.To(aviUserFullName(r.ManagementClusterID))
+	if _, err := r.aviClient.UserUpdate(aviUser); err != nil {
+		return err
+	}
+	if passwordUpdated {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserPasswordUpdatedReason,
+			"Updated the password of Avi user %s", aviUsername)
+	}
+	if roleBound {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviUserRoleBoundReason,
+			"Bound Avi user %s to Avi role %s", aviUsername, RoleProfileRoleName(profile))
+		// the role of the former role profile goes once unused, it's left
+		// behind on failure
+		if previousRoleRef != "" {
+			if err := r.deleteUnusedRole(log, cluster, previousRoleRef, roleOptions...); err != nil {
+				log.Error(err, "Failed to delete unused Avi role", "role", previousRoleRef)
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.AviRoleDeleteFailedReason,
+					"Failed to delete unused Avi role %s: %v", previousRoleRef, err)
+			}
 		}
 	}
 	return nil
 }
 
-// getOrCreateAkoUserRole get ako user's role, create one if not exist
-func (r *AkoUserReconciler) getOrCreateAkoUserRole(log logr.Logger, roleTenantRef *string, version string) (*models.Role, error) {
-	log.Info("Ensure AKO User Role")
-	role, err := r.aviClient.RoleGetByName(akoov1alpha1.AkoUserRoleName)
+// bindAkoUserRole binds the user to the role in its default tenant and
+// returns the role it was bound to before and whether the binding changed
+func bindAkoUserRole(aviUser *models.User, roleRef *string) (string, bool) {
+	for _, access := range aviUser.Access {
+		if ptr.Deref(access.TenantRef, "") != ptr.Deref(aviUser.DefaultTenantRef, "") {
+			continue
+		}
+		previousRoleRef := ptr.Deref(access.RoleRef, "")
+		if previousRoleRef == ptr.Deref(roleRef, "") {
+			return "", false
+		}
+		access.RoleRef = roleRef
+		return previousRoleRef, true
+	}
+	aviUser.Access = append(aviUser.Access, &models.UserRole{
+		AllTenants: ptr.To(false),
+		RoleRef:    roleRef,
+		TenantRef:  aviUser.DefaultTenantRef,
+	})
+	return "", true
+}
+
+// deleteUnusedRole deletes the Avi role of a role profile once no Avi user is
+// bound to it anymore, the ako-essential-role is kept
+func (r *AkoUserReconciler) deleteUnusedRole(log logr.Logger, cluster *clusterv1.Cluster, roleRef string, options ...session.ApiOptionsParams) error {
+	uuid := aviclient.GetUUIDFromRef(roleRef)
+	role, err := r.aviClient.RoleGet(uuid, options...)
+	if aviclient.IsAviObjectNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	roleName := ptr.Deref(role.Name, "")
+	if !strings.HasPrefix(roleName, akoov1alpha1.AkoUserRoleName+"-") {
+		return nil
+	}
+	users, err := r.aviClient.UserList()
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		for _, access := range user.Access {
+			if aviclient.GetUUIDFromRef(ptr.Deref(access.RoleRef, "")) == uuid {
+				return nil
+			}
+		}
+	}
+	if err := r.aviClient.RoleDelete(uuid, options...); err != nil && !aviclient.IsAviObjectNotFoundError(err) {
+		return err
+	}
+	log.Info("Deleted unused Avi role", "role", roleName)
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviRoleDeletedReason,
+		"Deleted Avi role %s, no Avi user is bound to it anymore", roleName)
+	return nil
+}
+
+// getOrCreateAkoUserRole get ako user's role of the role profile, create one if not exist
+func (r *AkoUserReconciler) getOrCreateAkoUserRole(log logr.Logger, cluster *clusterv1.Cluster, roleTenantRef *string, profile *akoov1alpha1.AviRoleProfile, version string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	roleName := RoleProfileRoleName(profile)
+	log.Info("Ensure AKO User Role", "role", roleName)
+	permissionMap, err := RoleProfilePermissionMap(profile)
+	if err != nil {
+		return nil, err
+	}
+	role, err := r.aviClient.RoleGetByName(roleName, options...)
 	// not found ako user role, create one
 	if aviclient.IsAviRoleNonExistentError(err) {
-		log.V(3).Info("Creating AKO User Role since it's not found", "role", akoov1alpha1.AkoUserRoleName)
+		log.V(3).Info("Creating AKO User Role since it's not found", "role", roleName)
 		log.Info("current avi version", "version", version)
 		role = &models.Role{
-			Name:       ptr.To(akoov1alpha1.AkoUserRoleName),
-			Privileges: filterAkoRolePermissionByVersion(log, AkoRolePermission, version),
+			Name:       ptr.To(roleName),
+			Privileges: filterAkoRolePermissionByVersion(log, rolePermissions(profile, permissionMap), version),
 			TenantRef:  roleTenantRef,
 		}
-		return r.aviClient.RoleCreate(role)
+		role, err = r.aviClient.RoleCreate(role, options...)
+		if err == nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviRoleCreatedReason,
+				"Created Avi role %s", roleName)
+		}
+		return role, err
 	}
 	if err == nil {
-		return r.ensureAkoUserRole(log, version)
+		return r.ensureAkoUserRole(log, cluster, role, permissionMap, version, options...)
 	}
 	return role, err
 }
 
-// ensureAkoUserRole ensure ako-essential-role has the latest permission
-func (r *AkoUserReconciler) ensureAkoUserRole(log logr.Logger, version string) (*models.Role, error) {
-	role, err := r.aviClient.RoleGetByName(akoov1alpha1.AkoUserRoleName)
-	if err != nil {
-		return role, err
-	}
-
+// ensureAkoUserRole ensure the ako user role has the permissions of its role
+// profile
+func (r *AkoUserReconciler) ensureAkoUserRole(log logr.Logger, cluster *clusterv1.Cluster, role *models.Role, permissionMap map[string]string, version string, options ...session.ApiOptionsParams) (*models.Role, error) {
 	// check if role needs to be synced
-	if syncAkoUserRole(role, version) {
+	if syncRolePermissions(role, permissionMap, version) {
 		log.Info("Syncing AKO User Role with expected permissions")
-		return r.aviClient.RoleUpdate(role)
+		roleName := ptr.Deref(role.Name, "")
+		role, err := r.aviClient.RoleUpdate(role, options...)
+		if err == nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.AviRoleSyncedReason,
+				"Synced the permissions of Avi role %s", roleName)
+		}
+		return role, err
 	}
 
 	return role, nil
 }
 
 // syncAkoUserRole checks the Role for the complete/correct
+// set of permissions of the ako-essential-role, see syncRolePermissions.
+func syncAkoUserRole(role *models.Role, version string) bool {
+	return syncRolePermissions(role, utils.AkoRolePermissionMap, version)
+}
+
+// syncRolePermissions checks the Role for the complete/correct
 // set of permissions and makes any additions/updates necessary.
 // Any additional permissions on the Role that are not part of
 // the desired AKO role are left as-is. It returns a bool
 // indicating whether the Role was changed.
 // It also filters out permissions that are deprecated in the current AVI version.
-func syncAkoUserRole(role *models.Role, version string) bool {
+func syncRolePermissions(role *models.Role, permissionMap map[string]string, version string) bool {
 	existingResources := sets.New[string]()
 	updated := false
 
 	for i, permission := range role.Privileges {
-		desiredType, ok := AkoRolePermissionMap[*permission.Resource]
+		desiredType, ok := permissionMap[*permission.Resource]
 		if !ok {
 			// Existing AVI role in AVI Controller has a permission that's not part of
-			// the desired AKO role defined in AkoRolePermissionMap: leave it as-is.
+			// the desired AKO role defined in the permission map: leave it as-is.
 			// Since those could come from a new AVI Controller version that AKO-Operator
 			// Might not be aware of.
 			continue
@@ -399,7 +639,7 @@ func syncAkoUserRole(role *models.Role, version string) bool {
 		}
 	}
 
-	for resource, desiredType := range AkoRolePermissionMap {
+	for resource, desiredType := range permissionMap {
 		if !existingResources.Has(resource) {
 			// Filter out permissions that are deprecated in the current AVI version.
 			if deprecateVersion, ok := deprecatePermissionMap[resource]; ok && semver.Compare(version, deprecateVersion) >= 0 {
@@ -7,14 +7,21 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/test/aviserver"
 	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -40,7 +47,8 @@ func AkoUserReconcilerTest() {
 		userReconciler = NewProvider(testClient,
 			nil,
 			ctrl.Log.WithName("reconciler").WithName("AviUser"),
-			mgr.GetScheme())
+			mgr.GetScheme(),
+			record.NewFakeRecorder(100))
 
 		akoDeploymentConfig = &akoov1alpha1.AKODeploymentConfig{
 			ObjectMeta: metav1.ObjectMeta{
@@ -253,6 +261,116 @@ func SyncAkoUserRoleTest() {
 		Expect(role.Privileges).To(ContainElements(additionalPrivileges))
 	})
 
+	Specify("role drifted from its role profile", func() {
+		permissionMap, err := RoleProfilePermissionMap(&akoov1alpha1.AviRoleProfile{
+			Name:               "l4",
+			RemovedPermissions: []string{"PERMISSION_PKIPROFILE"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		role := &models.Role{}
+		for _, permission := range AkoRolePermission {
+			role.Privileges = append(role.Privileges, &models.Permission{
+				Resource: ptr.To(*permission.Resource),
+				Type:     ptr.To(*permission.Type),
+			})
+		}
+
+		updated := syncRolePermissions(role, permissionMap, "v20.0.0")
+		Expect(updated).To(BeTrue())
+		Expect(role.Privileges).To(HaveLen(len(AkoRolePermission)))
+		Expect(role.Privileges).To(ContainElement(&models.Permission{
+			Resource: ptr.To("PERMISSION_PKIPROFILE"),
+			Type:     ptr.To("NO_ACCESS"),
+		}))
+		Expect(syncRolePermissions(role, permissionMap, "v20.0.0")).To(BeFalse())
+	})
+
+	Specify("user is bound to the role of its role profile", func() {
+		user := &models.User{
+			DefaultTenantRef: ptr.To("tenant-1"),
+			Access: []*models.UserRole{
+				{TenantRef: ptr.To("tenant-2"), RoleRef: ptr.To("role-2")},
+				{TenantRef: ptr.To("tenant-1"), RoleRef: ptr.To("role-1")},
+			},
+		}
+		previousRoleRef, bound := bindAkoUserRole(user, ptr.To("role-1"))
+		Expect(bound).To(BeFalse())
+		Expect(previousRoleRef).To(BeEmpty())
+		previousRoleRef, bound = bindAkoUserRole(user, ptr.To("role-3"))
+		Expect(bound).To(BeTrue())
+		Expect(previousRoleRef).To(Equal("role-1"))
+		Expect(user.Access).To(HaveLen(2))
+		Expect(user.Access[0].RoleRef).To(Equal(ptr.To("role-2")))
+		Expect(user.Access[1].RoleRef).To(Equal(ptr.To("role-3")))
+	})
+
+	Specify("role of a former role profile is deleted once unused", func() {
+		fakeAviClient := aviclient.NewFakeAviClient()
+		userReconciler := NewProvider(nil, fakeAviClient, ctrl.Log, nil, record.NewFakeRecorder(10))
+		roles := map[string]*models.Role{
+			"role-1": {UUID: ptr.To("role-1"), Name: ptr.To("ako-essential-role-l4-0123abcd")},
+			"role-2": {UUID: ptr.To("role-2"), Name: ptr.To(akoov1alpha1.AkoUserRoleName)},
+		}
+		fakeAviClient.Role.SetGetRoleFunc(func(uuid string, options ...session.ApiOptionsParams) (*models.Role, error) {
+			if role, ok := roles[uuid]; ok {
+				return role, nil
+			}
+			return nil, session.AviError{HttpStatusCode: http.StatusNotFound}
+		})
+		var deleted []string
+		fakeAviClient.Role.SetDeleteRoleFunc(func(uuid string, options ...session.ApiOptionsParams) error {
+			deleted = append(deleted, uuid)
+			return nil
+		})
+		users := []*models.User{{Access: []*models.UserRole{{RoleRef: ptr.To("https://avi/api/role/role-1")}}}}
+		fakeAviClient.User.SetGetAllUserFunc(func(options ...session.ApiOptionsParams) ([]*models.User, error) {
+			return users, nil
+		})
+		cluster := &clusterv1.Cluster{}
+
+		// still bound
+		Expect(userReconciler.deleteUnusedRole(ctrl.Log, cluster, "https://avi/api/role/role-1")).To(Succeed())
+		Expect(deleted).To(BeEmpty())
+		// not the role of a role profile
+		users = nil
+		Expect(userReconciler.deleteUnusedRole(ctrl.Log, cluster, "https://avi/api/role/role-2")).To(Succeed())
+		Expect(deleted).To(BeEmpty())
+		// already gone
+		Expect(userReconciler.deleteUnusedRole(ctrl.Log, cluster, "https://avi/api/role/role-3")).To(Succeed())
+		Expect(deleted).To(BeEmpty())
+
+		Expect(userReconciler.deleteUnusedRole(ctrl.Log, cluster, "https://avi/api/role/role-1")).To(Succeed())
+		Expect(deleted).To(Equal([]string{"role-1"}))
+	})
+
+	Specify("password of an existing user is only updated when it isn't synced", func() {
+		server := aviserver.NewServer()
+		defer server.Close()
+		aviClient, err := aviclient.NewAviClient(server.ClientConfig(), "")
+		Expect(err).ShouldNot(HaveOccurred())
+		recorder := record.NewFakeRecorder(10)
+		userReconciler := NewProvider(nil, aviClient, ctrl.Log, nil, recorder)
+		cluster := &clusterv1.Cluster{}
+		reasons := func() []string {
+			var res []string
+			for {
+				select {
+				case event := <-recorder.Events:
+					res = append(res, strings.Fields(event)[1])
+				default:
+					return res
+				}
+			}
+		}
+
+		Expect(userReconciler.createOrUpdateAviUser(ctrl.Log, cluster, "ako-user", "password", false, "", false, nil)).To(Succeed())
+		Expect(reasons()).To(ContainElement(akoov1alpha1.AviUserCreatedReason))
+		Expect(userReconciler.createOrUpdateAviUser(ctrl.Log, cluster, "ako-user", "password", true, "", false, nil)).To(Succeed())
+		Expect(reasons()).To(BeEmpty())
+		Expect(userReconciler.createOrUpdateAviUser(ctrl.Log, cluster, "ako-user", "rotated", false, "", false, nil)).To(Succeed())
+		Expect(reasons()).To(Equal([]string{akoov1alpha1.AviUserPasswordUpdatedReason}))
+	})
+
 	Specify("AVI Controller is higher than 30.2.1", func() {
 		role := &models.Role{}
 
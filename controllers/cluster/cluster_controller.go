@@ -20,9 +20,11 @@ import (
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/haprovider"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,6 +34,7 @@ import (
 // SetupWithManager adds this reconciler to a new controller then to the
 // provided manager.
 func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Haprovider = haprovider.NewProvider(r.Client, r.Log.WithName("haprovider"), r.Recorder)
 	return ctrl.NewControllerManagedBy(mgr).
 		// Watch Cluster resources.
 		For(&clusterv1.Cluster{}).
@@ -46,6 +49,7 @@ type ClusterReconciler struct {
 	client.Client
 	Log        logr.Logger
 	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
 	Haprovider *haprovider.HAProvider
 }
 
@@ -58,6 +62,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Cluster not found, will not reconcile")
+			metrics.HAServiceVIPReady.DeleteLabelValues(req.Namespace, req.Name)
 			return res, nil
 		}
 		return res, err
@@ -88,7 +93,6 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 
 	if isVIPProvider {
 		log.Info("AVI is control plane HA provider")
-		r.Haprovider = haprovider.NewProvider(r.Client, r.Log)
 		if err = r.Haprovider.CreateOrUpdateHAService(ctx, cluster); err != nil {
 			log.Error(err, "Fail to reconcile HA service")
 			return res, err
@@ -30,9 +30,10 @@ var suite = builder.NewTestSuiteForController(
 		builder.FakeAvi = aviclient.NewFakeAviClient()
 
 		if err := (&cluster.ClusterReconciler{
-			Client: mgr.GetClient(),
-			Log:    ctrl.Log.WithName("controllers").WithName("Cluster"),
-			Scheme: mgr.GetScheme(),
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("Cluster"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor(akoov1alpha1.ClusterControllerName),
 		}).SetupWithManager(mgr); err != nil {
 			return err
 		}
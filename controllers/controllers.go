@@ -4,32 +4,82 @@
 package controllers
 
 import (
+	"context"
+	"time"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/user"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/cluster"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/machine"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
+	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func SetupReconcilers(mgr ctrl.Manager) error {
+// Options configures the reconcilers
+type Options struct {
+	// VIPPoolSyncPeriod is how often the utilization of the static IP pools
+	// of AKODeploymentConfigs is refreshed from the Avi Controller
+	VIPPoolSyncPeriod time.Duration
+	// OrphanSweepPeriod is how often the Avi users and Secrets of deleted
+	// clusters are swept, 0 disables the sweeper
+	OrphanSweepPeriod time.Duration
+	// OrphanGracePeriod is how long they have to be orphaned before they're
+	// deleted
+	OrphanGracePeriod time.Duration
+	// OrphanSweepDryRun only reports them
+	OrphanSweepDryRun bool
+	// AviUserPasswordPolicy is the default policy of the passwords generated
+	// for the Avi users
+	AviUserPasswordPolicy utils.PasswordPolicy
+	// AKOManifestOptions configures the AKO manifests applied by the Direct
+	// and ClusterResourceSet deliveries
+	AKOManifestOptions ako.ManifestOptions
+}
+
+func SetupReconcilers(mgr ctrl.Manager, opts Options) error {
 	if err := (&machine.MachineReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Machine"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Machine"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(akoov1alpha1.MachineControllerName),
 	}).SetupWithManager(mgr); err != nil {
 		return err
 	}
 
+	// the cache isn't started yet, read the namespace from the API server
+	managementClusterID, err := ako_operator.ManagementClusterID(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		return err
+	}
+	adcRecorder := mgr.GetEventRecorderFor(akoov1alpha1.AKODeploymentConfigControllerName)
+	var orphanSweeper *user.OrphanSweeper
+	if opts.OrphanSweepPeriod > 0 {
+		orphanSweeper = user.NewOrphanSweeper(mgr.GetClient(), adcRecorder, managementClusterID,
+			opts.OrphanSweepPeriod, opts.OrphanGracePeriod, opts.OrphanSweepDryRun)
+	}
 	if err := (&akodeploymentconfig.AKODeploymentConfigReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AKODeploymentConfig"),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("AKODeploymentConfig"),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            adcRecorder,
+		AviClients:          aviclient.DefaultRegistry,
+		VIPPoolSyncPeriod:   opts.VIPPoolSyncPeriod,
+		OrphanSweeper:       orphanSweeper,
+		PasswordPolicy:      opts.AviUserPasswordPolicy,
+		ManifestOptions:     opts.AKOManifestOptions,
+		ManagementClusterID: managementClusterID,
 	}).SetupWithManager(mgr); err != nil {
 		return err
 	}
 	if err := (&cluster.ClusterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Cluster"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Cluster"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(akoov1alpha1.ClusterControllerName),
 	}).SetupWithManager(mgr); err != nil {
 		return err
 	}
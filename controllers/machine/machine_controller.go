@@ -13,8 +13,10 @@ import (
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/handlers"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/haprovider"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -28,6 +30,7 @@ import (
 // SetupWithManager adds this reconciler to a new controller then to the
 // provided manager.
 func (r *MachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Haprovider = haprovider.NewProvider(r.Client, r.Log.WithName("haprovider"), r.Recorder)
 	return ctrl.NewControllerManagedBy(mgr).
 		// Watch Cluster API Machine resources.
 		For(&clusterv1.Machine{}).
@@ -42,6 +45,7 @@ type MachineReconciler struct {
 	client.Client
 	Log        logr.Logger
 	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
 	Haprovider *haprovider.HAProvider
 }
 
@@ -99,7 +103,6 @@ func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	}
 
 	if isVIPProvider {
-		r.Haprovider = haprovider.NewProvider(r.Client, log)
 		if err = r.Haprovider.CreateOrUpdateHAEndpoints(ctx, obj); err != nil {
 			log.Error(err, "Fail to reconcile HA endpoint")
 			return res, err
@@ -123,7 +126,7 @@ func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 
 	// Removes the pre-terminate hook when machine is being deleted directly and it's parent cluster is not.
 	if !obj.GetDeletionTimestamp().IsZero() && cluster.GetDeletionTimestamp().IsZero() {
-		delete(obj.Annotations, akoov1alpha1.PreTerminateAnnotation)
+		r.removePreTerminateHook(obj, "Machine is being deleted though its parent Cluster is not")
 		log.Info("Machine is being deleted though its parent Cluster is not, removing pre-terminate hook")
 		return res, nil
 	}
@@ -198,9 +201,20 @@ func (r *MachineReconciler) reconcileMachineDeletionHook(
 
 	if annotations.HasWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, obj.ObjectMeta.Annotations) {
 		// Removes the pre-terminate hook as the cleanup has finished
-		delete(obj.Annotations, akoov1alpha1.PreTerminateAnnotation)
+		r.removePreTerminateHook(obj, "Avi resources of the Cluster are cleaned up")
 		log.Info("Removing pre-terminate hook")
 	}
 
 	return res, nil
 }
+
+// removePreTerminateHook deletes the pre-terminate hook of the Machine and
+// records an Event when there was one
+func (r *MachineReconciler) removePreTerminateHook(obj *clusterv1.Machine, why string) {
+	if _, ok := obj.Annotations[akoov1alpha1.PreTerminateAnnotation]; !ok {
+		return
+	}
+	delete(obj.Annotations, akoov1alpha1.PreTerminateAnnotation)
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, akoov1alpha1.PreTerminateHookRemovedReason,
+		"Removed pre-terminate hook: %s", why)
+}
@@ -29,9 +29,10 @@ var suite = builder.NewTestSuiteForController(
 		builder.FakeAvi = aviclient.NewFakeAviClient()
 
 		if err := (&machine.MachineReconciler{
-			Client: mgr.GetClient(),
-			Log:    ctrl.Log.WithName("controllers").WithName("Machine"),
-			Scheme: mgr.GetScheme(),
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("Machine"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor(akoov1alpha1.MachineControllerName),
 		}).SetupWithManager(mgr); err != nil {
 			return err
 		}
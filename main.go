@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	runv1alpha3 "github.com/vmware-tanzu/tanzu-framework/apis/run/v1alpha3"
@@ -15,11 +17,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
 	logsv1 "k8s.io/component-base/logs/api/v1"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -27,8 +31,12 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	akoov1beta1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1beta1"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako"
 	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 )
 
 var (
@@ -38,6 +46,8 @@ var (
 	metricsAddr          string
 	enableLeaderElection bool
 	profilerAddress      string
+	aviRetryOptions      = aviclient.DefaultRetryOptions
+	reconcilerOptions    = controllers.Options{VIPPoolSyncPeriod: 5 * time.Minute, OrphanGracePeriod: time.Hour}
 )
 
 func initLog() {
@@ -49,7 +59,9 @@ func init() {
 	// ignoring errors
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = addonsv1.AddToScheme(scheme)
 	_ = akoov1alpha1.AddToScheme(scheme)
+	_ = akoov1beta1.AddToScheme(scheme)
 	_ = akov1beta1.AddToScheme(scheme)
 	_ = runv1alpha3.AddToScheme(scheme)
 }
@@ -61,6 +73,21 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&metricsAddr, "metrics-addr", "localhost:8080", "The address the metric endpoint binds to.")
 	fs.BoolVar(&enableLeaderElection, "enable-leader-election", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	fs.StringVar(&profilerAddress, "profiler-addr", "", "Bind address to expose the pprof profiler")
+	fs.IntVar(&aviRetryOptions.MaxRetries, "avi-api-max-retries", aviRetryOptions.MaxRetries, "Number of times a read or an update sent to an Avi Controller is retried when it fails transiently, 0 disables retries.")
+	fs.DurationVar(&aviRetryOptions.RetryBackoff, "avi-api-retry-backoff", aviRetryOptions.RetryBackoff, "Delay before the first retry of a request sent to an Avi Controller, doubled on every following retry.")
+	fs.DurationVar(&aviRetryOptions.InitialBackoff, "avi-api-initial-backoff", aviRetryOptions.InitialBackoff, "Delay before an AKODeploymentConfig whose reconcile failed, e.g. on an Avi Controller request which failed all its retries, is requeued, doubled on every following failure.")
+	fs.DurationVar(&aviRetryOptions.MaxBackoff, "avi-api-max-backoff", aviRetryOptions.MaxBackoff, "Maximum delay before an AKODeploymentConfig whose reconcile failed is requeued.")
+	fs.Float32Var(&aviRetryOptions.QPS, "avi-api-qps", aviRetryOptions.QPS, "Maximum sustained number of requests per second sent to one Avi Controller, 0 disables rate limiting.")
+	fs.IntVar(&aviRetryOptions.Burst, "avi-api-burst", aviRetryOptions.Burst, "Maximum burst of requests sent to one Avi Controller.")
+	fs.DurationVar(&reconcilerOptions.OrphanSweepPeriod, "orphan-sweep-period", reconcilerOptions.OrphanSweepPeriod, "How often the Avi users and avi-credentials Secrets left behind by deleted clusters are looked for, 0 disables the sweeper. Only the Avi users created by this management cluster are swept.")
+	fs.DurationVar(&reconcilerOptions.OrphanGracePeriod, "orphan-grace-period", reconcilerOptions.OrphanGracePeriod, "How long an Avi user or an avi-credentials Secret has to be orphaned before the sweeper deletes it.")
+	fs.BoolVar(&reconcilerOptions.OrphanSweepDryRun, "orphan-sweep-dry-run", reconcilerOptions.OrphanSweepDryRun, "Only report the orphaned Avi users and avi-credentials Secrets through events and metrics, without deleting them.")
+	fs.IntVar(&reconcilerOptions.AviUserPasswordPolicy.Length, "avi-user-password-length", utils.DefaultPasswordLength, "Length of the passwords generated for the Avi users, AKODeploymentConfigs can override it.")
+	fs.StringSliceVar(&reconcilerOptions.AviUserPasswordPolicy.CharacterClasses, "avi-user-password-character-classes", nil, "Character classes the passwords generated for the Avi users are made of, among "+strings.Join(utils.CharacterClasses, ", ")+". All of them by default, AKODeploymentConfigs can override it.")
+	fs.StringVar(&reconcilerOptions.AviUserPasswordPolicy.ExcludedCharacters, "avi-user-password-excluded-characters", "", "Characters never used in the passwords generated for the Avi users, AKODeploymentConfigs can override it.")
+	fs.StringVar(&reconcilerOptions.AKOManifestOptions.Image, "ako-image", ako.DefaultImage, "Image of AKO applied in the workload clusters by the Direct and ClusterResourceSet deliveries.")
+	fs.StringVar(&reconcilerOptions.AKOManifestOptions.GatewayAPIImage, "ako-gateway-api-image", ako.DefaultGatewayAPIImage, "Image of the ako-gateway-api sidecar applied with the GatewayAPI feature gate by the Direct and ClusterResourceSet deliveries.")
+	fs.DurationVar(&reconcilerOptions.VIPPoolSyncPeriod, "vip-pool-sync-period", reconcilerOptions.VIPPoolSyncPeriod, "How often the utilization of the static IP pools of AKODeploymentConfigs is refreshed from the Avi Controller, 0 disables the periodic refresh.")
 }
 
 func main() {
@@ -80,6 +107,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	aviclient.SetRetryOptions(aviRetryOptions)
+
+	if err := reconcilerOptions.AviUserPasswordPolicy.Validate(); err != nil {
+		setupLog.Error(err, "Invalid Avi user password policy")
+		os.Exit(1)
+	}
+
 	if profilerAddress != "" {
 		setupLog.Info(
 			"Profiler listening for requests",
@@ -91,6 +125,8 @@ func main() {
 	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{
 		Scheme:         scheme,
 		LeaderElection: enableLeaderElection,
+		// the manager lives as long as the process, the broadcaster can't leak
+		EventBroadcaster: newEventBroadcaster(), //nolint:staticcheck
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
@@ -108,7 +144,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	err = controllers.SetupReconcilers(mgr)
+	err = controllers.SetupReconcilers(mgr, reconcilerOptions)
 	if err != nil {
 		setupLog.Error(err, "Unable to setup reconcilers")
 		os.Exit(1)
@@ -129,6 +165,17 @@ func main() {
 	}
 }
 
+// newEventBroadcaster returns a broadcaster aggregating the events repeated by
+// reconciliations which are requeued on the same error: after 5 similar events
+// on an object within 10 minutes, they are folded into a single event whose
+// count is bumped
+func newEventBroadcaster() record.EventBroadcaster {
+	return record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		MaxEvents:            5,
+		MaxIntervalInSeconds: 600,
+	})
+}
+
 func runProfiler(addr string) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -4,14 +4,18 @@
 package ako_operator
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
 
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Legacy cluster environment variables
@@ -56,6 +60,17 @@ func IsClusterClassEnabled() bool {
 	return os.Getenv(ClusterClassEnabled) == "True"
 }
 
+// ManagementClusterID returns the UID of the kube-system namespace of the
+// cluster the operator runs in, it tells apart the management clusters
+// sharing an Avi Controller
+func ManagementClusterID(ctx context.Context, c client.Reader) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: metav1.NamespaceSystem}, ns); err != nil {
+		return "", err
+	}
+	return string(ns.UID), nil
+}
+
 // IsClusterClassBasedCluster checks if a cluster is cluster class based cluster
 func IsClusterClassBasedCluster(cluster *clusterv1.Cluster) bool {
 	if cluster != nil && cluster.Spec.Topology != nil {
@@ -4,14 +4,17 @@
 package ako_operator
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("AKO Operator lib unit test", func() {
@@ -555,4 +558,19 @@ var _ = Describe("AKO Operator lib unit test", func() {
 			})
 		})
 	})
+
+	Context("ManagementClusterID", func() {
+		It("should return the UID of the kube-system namespace", func() {
+			c := fake.NewClientBuilder().WithObjects(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceSystem, UID: "mgmt-uid"},
+			}).Build()
+			id, err := ManagementClusterID(context.Background(), c)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(id).Should(Equal("mgmt-uid"))
+		})
+		It("should fail without a kube-system namespace", func() {
+			_, err := ManagementClusterID(context.Background(), fake.NewClientBuilder().Build())
+			Expect(err).Should(HaveOccurred())
+		})
+	})
 })
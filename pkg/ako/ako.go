@@ -16,7 +16,6 @@ import (
 )
 
 const (
-	akoStatefulSetName         = "ako"
 	akoCleanUpAnnotationKey    = "AviObjectDeletionStatus"
 	akoCleanUpInProgressStatus = "Started"
 	akoCleanUpFinishedStatus   = "Done"
@@ -27,7 +26,7 @@ func CleanupFinished(ctx context.Context, remoteClient client.Client, log logr.L
 
 	ss := &appv1.StatefulSet{}
 	if err := remoteClient.Get(ctx, client.ObjectKey{
-		Name:      akoStatefulSetName,
+		Name:      StatefulSetName,
 		Namespace: akoov1alpha1.AviNamespace,
 	}, ss); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -40,7 +40,7 @@ var _ = Describe("AKO", func() {
 		log.SetLogger(zap.New())
 		ss = &appv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      akoStatefulSetName,
+				Name:      StatefulSetName,
 				Namespace: akoov1alpha1.AviNamespace,
 			},
 		}
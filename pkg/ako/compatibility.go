@@ -0,0 +1,150 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ako
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"gopkg.in/yaml.v3"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
+
+// feature is a setting of the values which AKO only supports since a version,
+// older AKO packages reject or ignore it
+type feature struct {
+	// name of the feature reported when the AKODeploymentConfig asks for it
+	name string
+	// since is the first AKO version supporting it
+	since semver.Version
+	// path of the setting under loadBalancerAndIngressService.config
+	path []string
+	// requested returns whether the AKODeploymentConfig asks for it
+	requested func(c *Config) bool
+	// drop removes the setting from the values
+	drop func(c *Config)
+}
+
+// features is the compatibility matrix of the values, the settings missing
+// from it are supported by every AKO package shipped in a TKR
+var features = []feature{
+	{
+		name:      "enableMCI",
+		since:     semver.MustParse("1.5.1"),
+		path:      []string{"l7_settings", "enable_MCI"},
+		requested: func(c *Config) bool { return c.L7Settings != nil && c.L7Settings.EnableMCI == "true" },
+		drop: func(c *Config) {
+			if c.L7Settings != nil {
+				c.L7Settings.EnableMCI = ""
+			}
+		},
+	},
+	{
+		name:  "useDefaultSecretsOnly",
+		since: semver.MustParse("1.10.1"),
+		path:  []string{"ako_settings", "use_default_secrets_only"},
+		requested: func(c *Config) bool {
+			return c.AKOSettings != nil && c.AKOSettings.UseDefaultSecretsOnly == "true"
+		},
+		drop: func(c *Config) {
+			if c.AKOSettings != nil {
+				c.AKOSettings.UseDefaultSecretsOnly = ""
+			}
+		},
+	},
+	{
+		name:      "featureGates.GatewayAPI",
+		since:     semver.MustParse("1.11.1"),
+		path:      []string{"feature_gates"},
+		requested: func(c *Config) bool { return c.FeatureGates != nil && c.FeatureGates.GatewayAPI == "true" },
+		drop:      func(c *Config) { c.FeatureGates = nil },
+	},
+	{
+		name:      "akoGatewayLogFile",
+		since:     semver.MustParse("1.11.1"),
+		path:      []string{"ako_gateway_log_file"},
+		requested: func(c *Config) bool { return c.AKOGatewayLogFile != "" },
+		drop:      func(c *Config) { c.AKOGatewayLogFile = "" },
+	},
+}
+
+// ForVersion drops the settings the AKO version doesn't support from the
+// values, so that they render the schema of its package. It returns the
+// features the AKODeploymentConfig asked for which were dropped.
+func (v *Values) ForVersion(version semver.Version) []string {
+	config := &v.LoadBalancerAndIngressService.Config
+	var unsupported []string
+	for _, f := range features {
+		if version.GTE(f.since) {
+			continue
+		}
+		if f.requested(config) {
+			unsupported = append(unsupported, f.name)
+		}
+		f.drop(config)
+		v.dropped = append(v.dropped, f.path)
+	}
+	return unsupported
+}
+
+// ParsePackageVersion returns the AKO version of a package ref name listed in
+// a TKR, such as load-balancer-and-ingress-service.tanzu.vmware.com.1.11.2+vmware.1-tkg.1
+func ParsePackageVersion(refName string) (semver.Version, error) {
+	prefix := akoov1alpha1.AkoClusterBootstrapRefNamePrefix + "."
+	if !strings.HasPrefix(refName, prefix) {
+		return semver.Version{}, fmt.Errorf("%s is not an AKO package", refName)
+	}
+	return semver.ParseTolerant(strings.TrimPrefix(refName, prefix))
+}
+
+// ParseImageVersion returns the AKO version of an image from its tag
+func ParseImageVersion(image string) (semver.Version, error) {
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i:], "/") {
+		return semver.Version{}, fmt.Errorf("image %s has no tag", image)
+	}
+	return semver.ParseTolerant(image[i+1:])
+}
+
+// Version returns the AKO version of the image the manifests deploy
+func (o ManifestOptions) Version() (semver.Version, error) {
+	return ParseImageVersion(o.image())
+}
+
+// marshal renders the values without the settings dropped by ForVersion, the
+// keys keep the order of the structs
+func (v *Values) marshal() ([]byte, error) {
+	if len(v.dropped) == 0 {
+		return yaml.Marshal(v)
+	}
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	for _, path := range v.dropped {
+		removeKey(&node, append([]string{"loadBalancerAndIngressService", "config"}, path...))
+	}
+	return yaml.Marshal(&node)
+}
+
+// removeKey removes the key at the path of the mapping node
+func removeKey(node *yaml.Node, path []string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	// the content of a mapping node alternates the keys and their values
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+		removeKey(node.Content[i+1], path[1:])
+		return
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ako
+
+import (
+	"github.com/blang/semver/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
+
+var _ = Describe("Compatibility", func() {
+	var (
+		values  *Values
+		cluster *clusterv1.Cluster
+	)
+
+	BeforeEach(func() {
+		var err error
+		values, err = NewValues(&akoov1alpha1.AKODeploymentConfig{
+			Spec: akoov1alpha1.AKODeploymentConfigSpec{
+				CloudName:          "test-cloud",
+				Controller:         "10.23.122.1",
+				ServiceEngineGroup: "Default-SEG",
+				DataNetwork:        akoov1alpha1.DataNetwork{Name: "test-akdc", CIDR: "10.0.0.0/24"},
+				ExtraConfigs: akoov1alpha1.ExtraConfigs{
+					UseDefaultSecretsOnly: ptr.To(false),
+					Log:                   akoov1alpha1.AKOLogConfig{AKOGatewayLogFile: "gateway.log"},
+					FeatureGates:          akoov1alpha1.FeatureGates{GatewayAPI: ptr.To(true)},
+				},
+			},
+		}, "default-test")
+		Expect(err).NotTo(HaveOccurred())
+		cluster = &clusterv1.Cluster{}
+	})
+
+	It("should keep every setting for the latest AKO", func() {
+		full, err := values.YttYaml(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values.ForVersion(semver.MustParse("1.12.1"))).To(BeEmpty())
+		rendered, err := values.YttYaml(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(Equal(full))
+		Expect(rendered).To(ContainSubstring("gateway_api: \"true\""))
+	})
+
+	It("should drop the settings older AKO versions don't support", func() {
+		Expect(values.ForVersion(semver.MustParse("1.10.1"))).To(ConsistOf("featureGates.GatewayAPI", "akoGatewayLogFile"))
+		rendered, err := values.YttYaml(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).NotTo(ContainSubstring("feature_gates"))
+		Expect(rendered).NotTo(ContainSubstring("ako_gateway_log_file"))
+		Expect(rendered).To(ContainSubstring("use_default_secrets_only"))
+		Expect(rendered).To(ContainSubstring("enable_MCI"))
+		Expect(values.StatefulSet(ManifestOptions{}).Spec.Template.Spec.Containers).To(HaveLen(1))
+	})
+
+	It("should only report the features asked for", func() {
+		// useDefaultSecretsOnly is false, it's dropped without a warning
+		Expect(values.ForVersion(semver.MustParse("1.9.3"))).To(ConsistOf("featureGates.GatewayAPI", "akoGatewayLogFile"))
+		rendered, err := values.YttYaml(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).NotTo(ContainSubstring("use_default_secrets_only"))
+		Expect(values.ConfigMap().Data).NotTo(HaveKey("useDefaultSecretsOnly"))
+	})
+
+	It("should parse the AKO version of a TKR package", func() {
+		version, err := ParsePackageVersion("load-balancer-and-ingress-service.tanzu.vmware.com.1.10.3+vmware.1-tkg.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version.String()).To(Equal("1.10.3+vmware.1-tkg.1"))
+		_, err = ParsePackageVersion("antrea.tanzu.vmware.com.1.11.1+vmware.4-tkg.1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should parse the AKO version of an image", func() {
+		version, err := ParseImageVersion("registry.local:5000/ako/ako:v1.11.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal(semver.MustParse("1.11.1")))
+		_, err = ParseImageVersion("registry.local:5000/ako/ako")
+		Expect(err).To(HaveOccurred())
+	})
+})
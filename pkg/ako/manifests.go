@@ -0,0 +1,351 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ako
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap AKO reads its settings
+	// from
+	ConfigMapName = "avi-k8s-config"
+	// SecretName is the name of the Secret AKO reads its Avi credentials
+	// from
+	SecretName = "avi-secret"
+	// DeleteConfigKey is the key of the ConfigMap which makes AKO delete
+	// the Avi objects of the cluster
+	DeleteConfigKey = "deleteConfig"
+
+	serviceAccountName     = "ako-sa"
+	clusterRoleName        = "ako-cr"
+	clusterRoleBindingName = "ako-crb"
+	ingressClassName       = "avi-lb"
+	ingressController      = "ako.vmware.com/avi-lb"
+)
+
+const (
+	// DefaultImage is the AKO image deployed when ManifestOptions doesn't
+	// tell
+	DefaultImage = "projects.registry.vmware.com/ako/ako:1.11.1"
+	// DefaultGatewayAPIImage is the ako-gateway-api image deployed when
+	// ManifestOptions doesn't tell
+	DefaultGatewayAPIImage = "projects.registry.vmware.com/ako/ako-gateway-api:1.11.1"
+)
+
+// crds are the custom resource definitions of the AKO helm chart
+//
+//go:embed crds/*.yaml
+var crds embed.FS
+
+// ManifestOptions configures the rendering of the AKO manifests beyond the
+// values
+type ManifestOptions struct {
+	// Image of the AKO container
+	Image string
+	// GatewayAPIImage of the ako-gateway-api container, it's only deployed
+	// with the GatewayAPI feature gate
+	GatewayAPIImage string
+}
+
+func (o ManifestOptions) image() string {
+	if o.Image == "" {
+		return DefaultImage
+	}
+	return o.Image
+}
+
+func (o ManifestOptions) gatewayAPIImage() string {
+	if o.GatewayAPIImage == "" {
+		return DefaultGatewayAPIImage
+	}
+	return o.GatewayAPIImage
+}
+
+// Manifests returns the objects the AKO helm chart renders from the values,
+// in the order they have to be applied. Pod security policies are never
+// rendered, they're gone since Kubernetes 1.25.
+func (v *Values) Manifests(opts ManifestOptions) ([]client.Object, error) {
+	objs, err := CRDs()
+	if err != nil {
+		return nil, err
+	}
+	objs = append(objs,
+		v.Namespace(),
+		v.ServiceAccount(),
+	)
+	if v.primaryInstance() {
+		objs = append(objs, v.ClusterRole())
+	}
+	objs = append(objs,
+		v.ClusterRoleBinding(),
+		v.ConfigMap(),
+		v.Secret(),
+	)
+	if v.primaryInstance() {
+		objs = append(objs, v.IngressClass())
+	}
+	return append(objs, v.StatefulSet(opts)), nil
+}
+
+// ManifestsYaml renders the objects returned by Manifests as a multi-document
+// YAML
+func (v *Values) ManifestsYaml(opts ManifestOptions) ([]byte, error) {
+	objs, err := v.Manifests(opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		doc, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(doc)
+	}
+	return buf.Bytes(), nil
+}
+
+// RemovableObjects returns the objects of Manifests deleted to remove AKO
+// from a cluster, the namespace takes the namespaced ones with it. The CRDs
+// are kept, deleting them would delete the custom resources of the cluster.
+func RemovableObjects() []client.Object {
+	return []client.Object{
+		&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: ingressClassName}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: akoov1alpha1.AviNamespace}},
+	}
+}
+
+// CRDs returns the custom resource definitions of AKO, they're copied from its
+// helm chart
+func CRDs() ([]client.Object, error) {
+	files, err := fs.Glob(crds, "crds/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var objs []client.Object
+	for _, file := range files {
+		data, err := crds.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		objs = append(objs, crd)
+	}
+	return objs, nil
+}
+
+// Namespace returns the namespace AKO runs in
+func (v *Values) Namespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: v.namespace()},
+	}
+}
+
+// ConfigMap returns the avi-k8s-config ConfigMap, its keys are the ones of the
+// AKO helm chart
+func (v *Values) ConfigMap() *corev1.ConfigMap {
+	config := v.LoadBalancerAndIngressService.Config
+	data := map[string]string{}
+	set := func(key, value string) {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	if s := config.AKOSettings; s != nil {
+		set("primaryInstance", s.PrimaryInstance)
+		set("logLevel", s.LogLevel)
+		set("fullSyncFrequency", s.FullSyncFrequency)
+		set("apiServerPort", strconv.Itoa(s.ApiServerPort))
+		set(DeleteConfigKey, s.DeleteConfig)
+		set("disableStaticRouteSync", s.DisableStaticRouteSync)
+		set("clusterName", s.ClusterName)
+		set("cniPlugin", s.CniPlugin)
+		set("enableEVH", s.EnableEVH)
+		set("layer7Only", s.Layer7Only)
+		set("servicesAPI", s.ServicesAPI)
+		set("vipPerNamespace", s.VIPPerNamespace)
+		set("nsSyncLabelKey", s.NamespaceSector.LabelKey)
+		set("nsSyncLabelValue", s.NamespaceSector.LabelValue)
+		set("enableEvents", s.EnableEvents)
+		set("istioEnabled", s.IstioEnabled)
+		set("blockedNamespaceList", s.BlockedNamespaceListJson)
+		set("ipFamily", s.IpFamily)
+		set("useDefaultSecretsOnly", s.UseDefaultSecretsOnly)
+	}
+	if s := config.NetworkSettings; s != nil {
+		set("nodeNetworkList", s.NodeNetworkListJson)
+		set("vipNetworkList", s.VIPNetworkListJson)
+		set("enableRHI", s.EnableRHI)
+		set("nsxtT1LR", s.NsxtT1LR)
+		set("bgpPeerLabels", s.BGPPeerLabelsJson)
+	}
+	if s := config.L7Settings; s != nil {
+		set("defaultIngController", strconv.FormatBool(s.DefaultIngController))
+		set("l7ShardingScheme", s.L7ShardingScheme)
+		set("serviceType", s.ServiceType)
+		set("shardVSSize", s.ShardVSSize)
+		set("passthroughShardSize", s.PassthroughShardSize)
+		set("noPGForSNI", strconv.FormatBool(s.NoPGForSNI))
+		set("enableMCI", s.EnableMCI)
+	}
+	if s := config.L4Settings; s != nil {
+		set("defaultDomain", s.DefaultDomain)
+		set("autoFQDN", s.AutoFQDN)
+	}
+	if s := config.ControllerSettings; s != nil {
+		set("serviceEngineGroupName", s.ServiceEngineGroupName)
+		set("controllerVersion", s.ControllerVersion)
+		set("cloudName", s.CloudName)
+		set("controllerIP", s.ControllerIP)
+		set("tenantName", s.TenantName)
+	}
+	if s := config.NodePortSelector; s != nil {
+		set("nodeKey", s.Key)
+		set("nodeValue", s.Value)
+	}
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: v.namespace()},
+		Data:       data,
+	}
+}
+
+// Secret returns the avi-secret Secret holding the Avi credentials
+func (v *Values) Secret() *corev1.Secret {
+	credentials := v.LoadBalancerAndIngressService.Config.Avicredentials
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: v.namespace()},
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"username":                     []byte(credentials.Username),
+			"password":                     []byte(credentials.Password),
+			akoov1alpha1.AviCertificateKey: []byte(credentials.CertificateAuthorityData),
+		},
+	}
+}
+
+// ServiceAccount returns the service account AKO runs as
+func (v *Values) ServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: v.namespace()},
+	}
+}
+
+// ClusterRole returns the cluster role of AKO, it's shared by the AKO
+// instances of the cluster so only the primary one renders it
+func (v *Values) ClusterRole() *rbacv1.ClusterRole {
+	read := []string{"get", "watch", "list"}
+	write := []string{"get", "watch", "list", "patch", "update"}
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"*"}, Verbs: read},
+		{APIGroups: []string{"apps"}, Resources: []string{"statefulsets", "statefulsets/status"}, Verbs: write},
+		{APIGroups: []string{"extensions", "networking.k8s.io"}, Resources: []string{"ingresses", "ingresses/status"}, Verbs: write},
+		{APIGroups: []string{"networking.k8s.io"}, Resources: []string{"ingressclasses"}, Verbs: read},
+		{APIGroups: []string{""}, Resources: []string{"services", "services/status"}, Verbs: write},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: append(write, "create")},
+		{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"create", "patch", "update"}},
+		{APIGroups: []string{"crd.projectcalico.org"}, Resources: []string{"blockaffinities"}, Verbs: read},
+		{APIGroups: []string{"network.openshift.io"}, Resources: []string{"hostsubnets"}, Verbs: read},
+		{APIGroups: []string{"route.openshift.io"}, Resources: []string{"routes", "routes/status"}, Verbs: write},
+		{APIGroups: []string{"ako.vmware.com"}, Resources: []string{
+			"hostrules", "hostrules/status", "httprules", "httprules/status", "aviinfrasettings", "aviinfrasettings/status",
+			"l4rules", "l4rules/status", "ssorules", "ssorules/status",
+		}, Verbs: write},
+		{APIGroups: []string{"networking.x-k8s.io"}, Resources: []string{"gateways", "gateways/status", "gatewayclasses", "gatewayclasses/status"}, Verbs: write},
+		{APIGroups: []string{"ako.vmware.com"}, Resources: []string{"multiclusteringresses", "serviceimports"}, Verbs: []string{"get", "watch", "list", "patch"}},
+		{APIGroups: []string{"ako.vmware.com"}, Resources: []string{"multiclusteringresses/status", "serviceimports/status"}, Verbs: []string{"get", "patch"}},
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"create", "get", "update"}},
+		{APIGroups: []string{"cilium.io"}, Resources: []string{"ciliumnodes"}, Verbs: read},
+	}
+	if v.gatewayAPI() {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{"gateway.networking.k8s.io"},
+			Resources: []string{"gatewayclasses", "gatewayclasses/status", "gateways", "gateways/status", "httproutes", "httproutes/status"},
+			Verbs:     write,
+		})
+	}
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName},
+		Rules:      rules,
+	}
+}
+
+// ClusterRoleBinding returns the binding of the cluster role to the service
+// account of AKO
+func (v *Values) ClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	name := clusterRoleBindingName
+	if !v.primaryInstance() {
+		name += "-" + v.namespace()
+	}
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRoleName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      serviceAccountName,
+			Namespace: v.namespace(),
+		}},
+	}
+}
+
+// IngressClass returns the avi-lb IngressClass, only the primary AKO instance
+// renders it
+func (v *Values) IngressClass() *networkingv1.IngressClass {
+	ic := &networkingv1.IngressClass{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "IngressClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: ingressClassName},
+		Spec:       networkingv1.IngressClassSpec{Controller: ingressController},
+	}
+	if l7 := v.LoadBalancerAndIngressService.Config.L7Settings; l7 != nil && l7.DefaultIngController {
+		ic.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+	}
+	return ic
+}
+
+func (v *Values) primaryInstance() bool {
+	s := v.LoadBalancerAndIngressService.Config.AKOSettings
+	return s == nil || s.PrimaryInstance != "false"
+}
+
+func (v *Values) gatewayAPI() bool {
+	f := v.LoadBalancerAndIngressService.Config.FeatureGates
+	return f != nil && f.GatewayAPI == "true"
+}
+
+func (v *Values) namespace() string {
+	if v.LoadBalancerAndIngressService.Namespace != "" {
+		return v.LoadBalancerAndIngressService.Namespace
+	}
+	return akoov1alpha1.AviNamespace
+}
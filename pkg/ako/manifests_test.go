@@ -0,0 +1,103 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ako
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+)
+
+var _ = Describe("Manifests", func() {
+	var values *Values
+
+	BeforeEach(func() {
+		var err error
+		values, err = NewValues(&akoov1alpha1.AKODeploymentConfig{
+			Spec: akoov1alpha1.AKODeploymentConfigSpec{
+				CloudName:          "test-cloud",
+				Controller:         "10.23.122.1",
+				ServiceEngineGroup: "Default-SEG",
+				DataNetwork:        akoov1alpha1.DataNetwork{Name: "test-akdc", CIDR: "10.0.0.0/24"},
+				ExtraConfigs: akoov1alpha1.ExtraConfigs{
+					IngressConfigs: akoov1alpha1.AKOIngressConfig{ShardVSSize: "SMALL"},
+				},
+			},
+		}, "default-test")
+		Expect(err).NotTo(HaveOccurred())
+		values.LoadBalancerAndIngressService.Config.Avicredentials.Username = "admin"
+		values.LoadBalancerAndIngressService.Config.AKOSettings.EnableEVH = "true"
+	})
+
+	It("should render the settings of AKO into its ConfigMap", func() {
+		cm := values.ConfigMap()
+		Expect(cm.Namespace).To(Equal(akoov1alpha1.AviNamespace))
+		Expect(cm.Data).To(HaveKeyWithValue("clusterName", "default-test"))
+		Expect(cm.Data).To(HaveKeyWithValue("controllerIP", "10.23.122.1"))
+		Expect(cm.Data).To(HaveKeyWithValue("cloudName", "test-cloud"))
+		Expect(cm.Data).To(HaveKeyWithValue("shardVSSize", "SMALL"))
+		Expect(cm.Data).To(HaveKeyWithValue("enableEVH", "true"))
+		Expect(cm.Data).To(HaveKeyWithValue(DeleteConfigKey, "false"))
+		Expect(cm.Data).NotTo(HaveKey("cniPlugin"))
+	})
+
+	It("should render the credentials into the AKO Secret", func() {
+		Expect(values.Secret().Data).To(HaveKeyWithValue("username", []byte("admin")))
+	})
+
+	It("should render every manifest", func() {
+		manifests, err := values.ManifestsYaml(ManifestOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, kind := range []string{
+			"CustomResourceDefinition", "Namespace", "ServiceAccount", "ClusterRole", "ClusterRoleBinding",
+			"ConfigMap", "Secret", "IngressClass", "StatefulSet",
+		} {
+			Expect(string(manifests)).To(ContainSubstring("kind: " + kind + "\n"))
+		}
+	})
+
+	It("should render the CRDs of AKO", func() {
+		crds, err := CRDs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(crds).To(HaveLen(8))
+		for _, crd := range crds {
+			Expect(crd.GetName()).To(HaveSuffix(".ako.vmware.com"))
+		}
+	})
+
+	It("should leave the shared objects to the primary AKO instance", func() {
+		values.LoadBalancerAndIngressService.Config.AKOSettings.PrimaryInstance = "false"
+		objs, err := values.Manifests(ManifestOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, obj := range objs {
+			Expect(obj.GetObjectKind().GroupVersionKind().Kind).NotTo(BeElementOf("ClusterRole", "IngressClass"))
+		}
+		Expect(values.ClusterRoleBinding().Name).To(Equal("ako-crb-" + akoov1alpha1.AviNamespace))
+	})
+
+	It("should render the AKO StatefulSet", func() {
+		sts := values.StatefulSet(ManifestOptions{Image: "registry/ako:1.12.1"})
+		Expect(sts.Namespace).To(Equal(akoov1alpha1.AviNamespace))
+		Expect(*sts.Spec.Replicas).To(Equal(int32(1)))
+		Expect(sts.Spec.Template.Spec.ServiceAccountName).To(Equal(values.ServiceAccount().Name))
+		Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(1))
+		container := sts.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("registry/ako:1.12.1"))
+		Expect(container.LivenessProbe.HTTPGet.Port.IntValue()).To(Equal(8080))
+		Expect(container.Env).To(ContainElement(HaveField("Name", "CTRL_IPADDRESS")))
+	})
+
+	It("should run the ako-gateway-api sidecar with the GatewayAPI feature gate", func() {
+		values.LoadBalancerAndIngressService.Config.FeatureGates.GatewayAPI = "true"
+		values.LoadBalancerAndIngressService.Config.PersistentVolumeClaim = "ako-pvc"
+		values.LoadBalancerAndIngressService.Config.MountPath = "/log"
+		sts := values.StatefulSet(ManifestOptions{})
+		Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(2))
+		Expect(sts.Spec.Template.Spec.Containers[1].Image).To(Equal(DefaultGatewayAPIImage))
+		Expect(sts.Spec.Template.Spec.Containers[1].VolumeMounts).To(ConsistOf(HaveField("MountPath", "/log")))
+		Expect(sts.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(values.ClusterRole().Rules).To(ContainElement(HaveField("APIGroups", ConsistOf("gateway.networking.k8s.io"))))
+	})
+})
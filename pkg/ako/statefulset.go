@@ -0,0 +1,238 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ako
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// StatefulSetName is the name of the AKO StatefulSet
+	StatefulSetName = "ako"
+
+	containerName           = "ako"
+	gatewayAPIContainerName = "ako-gateway-api"
+	pvVolumeName            = "ako-pv-storage"
+	istioVolumeName         = "istio-certs"
+	istioCertsPath          = "/etc/istio-output-certs/"
+)
+
+// configMapEnv maps the environment variables of the AKO container to the
+// keys of the ConfigMap, in the order of the helm chart
+var configMapEnv = [][2]string{
+	{"CTRL_IPADDRESS", "controllerIP"},
+	{"CTRL_VERSION", "controllerVersion"},
+	{"CNI_PLUGIN", "cniPlugin"},
+	{"ISTIO_ENABLED", "istioEnabled"},
+	{"VIP_PER_NAMESPACE", "vipPerNamespace"},
+	{"SHARD_VS_SIZE", "shardVSSize"},
+	{"PASSTHROUGH_SHARD_SIZE", "passthroughShardSize"},
+	{"FULL_SYNC_INTERVAL", "fullSyncFrequency"},
+	{"PRIMARY_AKO_FLAG", "primaryInstance"},
+	{"CLOUD_NAME", "cloudName"},
+	{"TENANT_NAME", "tenantName"},
+	{"CLUSTER_NAME", "clusterName"},
+	{"ENABLE_RHI", "enableRHI"},
+	{"ENABLE_EVH", "enableEVH"},
+	{"SERVICES_API", "servicesAPI"},
+	{"DEFAULT_DOMAIN", "defaultDomain"},
+	{"DISABLE_STATIC_ROUTE_SYNC", "disableStaticRouteSync"},
+	{"NSXT_T1_LR", "nsxtT1LR"},
+	{"NAMESPACE_SYNC_LABEL_KEY", "nsSyncLabelKey"},
+	{"NAMESPACE_SYNC_LABEL_VALUE", "nsSyncLabelValue"},
+	{"DEFAULT_ING_CONTROLLER", "defaultIngController"},
+	{"SEG_NAME", "serviceEngineGroupName"},
+	{"BGP_PEER_LABELS", "bgpPeerLabels"},
+	{"NODE_NETWORK_LIST", "nodeNetworkList"},
+	{"VIP_NETWORK_LIST", "vipNetworkList"},
+	{"AKO_API_PORT", "apiServerPort"},
+	{"SERVICE_TYPE", "serviceType"},
+	{"NODE_KEY", "nodeKey"},
+	{"NODE_VALUE", "nodeValue"},
+	{"AUTO_L4_FQDN", "autoFQDN"},
+	{"MCI_ENABLED", "enableMCI"},
+	{"BLOCKED_NS_LIST", "blockedNamespaceList"},
+	{"IP_FAMILY", "ipFamily"},
+	{"USE_DEFAULT_SECRETS_ONLY", "useDefaultSecretsOnly"},
+}
+
+// gatewayAPIConfigMapEnv are the ConfigMap environment variables of the
+// ako-gateway-api container
+var gatewayAPIConfigMapEnv = [][2]string{
+	{"CTRL_IPADDRESS", "controllerIP"},
+	{"CTRL_VERSION", "controllerVersion"},
+	{"CLUSTER_NAME", "clusterName"},
+	{"PRIMARY_AKO_FLAG", "primaryInstance"},
+	{"CLOUD_NAME", "cloudName"},
+	{"TENANT_NAME", "tenantName"},
+	{"SEG_NAME", "serviceEngineGroupName"},
+	{"FULL_SYNC_INTERVAL", "fullSyncFrequency"},
+}
+
+// StatefulSet returns the AKO StatefulSet the way the helm chart renders it,
+// with the ako-gateway-api sidecar when the GatewayAPI feature gate is on
+func (v *Values) StatefulSet(opts ManifestOptions) *appsv1.StatefulSet {
+	config := v.LoadBalancerAndIngressService.Config
+	labels := map[string]string{"app.kubernetes.io/name": StatefulSetName}
+
+	port := 8080
+	if config.AKOSettings != nil && config.AKOSettings.ApiServerPort != 0 {
+		port = config.AKOSettings.ApiServerPort
+	}
+	replicas := int32(config.ReplicaCount)
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	ako := corev1.Container{
+		Name:            containerName,
+		Image:           opts.image(),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "/var/pre_stop_hook.sh"}},
+			},
+		},
+		Env:       append(configMapEnvVars(configMapEnv), v.logEnvVars(config.LogFile)...),
+		Resources: resources(),
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/api/status", Port: intstr.FromInt(port)},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+		},
+	}
+	containers := []corev1.Container{ako}
+	if v.gatewayAPI() {
+		containers = append(containers, corev1.Container{
+			Name:            gatewayAPIContainerName,
+			Image:           opts.gatewayAPIImage(),
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Env:             append(v.logEnvVars(config.AKOGatewayLogFile), configMapEnvVars(gatewayAPIConfigMapEnv)...),
+			Resources:       resources(),
+		})
+	}
+
+	var volumes []corev1.Volume
+	if config.PersistentVolumeClaim != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: pvVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: config.PersistentVolumeClaim},
+			},
+		})
+		for i := range containers {
+			containers[i].VolumeMounts = append(containers[i].VolumeMounts,
+				corev1.VolumeMount{Name: pvVolumeName, MountPath: config.MountPath})
+		}
+	}
+
+	var annotations map[string]string
+	if config.AKOSettings != nil && config.AKOSettings.IstioEnabled == "true" {
+		annotations = map[string]string{
+			"sidecar.istio.io/inject":                          "true",
+			"traffic.sidecar.istio.io/includeInboundPorts":     "",
+			"traffic.sidecar.istio.io/includeOutboundIPRanges": "",
+			"proxy.istio.io/config":                            "proxyMetadata:\n  OUTPUT_CERTS: /etc/istio-output-certs\n",
+			"sidecar.istio.io/userVolume":                      `[{"name": "istio-certs", "emptyDir": {"medium":"Memory"}}]`,
+			"sidecar.istio.io/userVolumeMount":                 `[{"name": "istio-certs", "mountPath": "/etc/istio-output-certs"}]`,
+		}
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts,
+			corev1.VolumeMount{Name: istioVolumeName, MountPath: istioCertsPath})
+	}
+
+	return &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StatefulSetName,
+			Namespace: v.namespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    ptr.To(replicas),
+			ServiceName: StatefulSetName,
+			Selector:    &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Volumes:            volumes,
+					Containers:         containers,
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								LabelSelector: &metav1.LabelSelector{
+									MatchExpressions: []metav1.LabelSelectorRequirement{{
+										Key:      "app.kubernetes.io/name",
+										Operator: metav1.LabelSelectorOpIn,
+										Values:   []string{StatefulSetName},
+									}},
+								},
+								TopologyKey: corev1.LabelHostname,
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// logEnvVars returns the environment variables telling AKO where to write its
+// logs, and the pod identity it reports them with
+func (v *Values) logEnvVars(logFile string) []corev1.EnvVar {
+	config := v.LoadBalancerAndIngressService.Config
+	var env []corev1.EnvVar
+	if config.PersistentVolumeClaim != "" {
+		env = append(env, corev1.EnvVar{Name: "USE_PVC", Value: "true"})
+	}
+	return append(env,
+		corev1.EnvVar{Name: "LOG_FILE_PATH", Value: config.MountPath},
+		corev1.EnvVar{Name: "LOG_FILE_NAME", Value: logFile},
+		corev1.EnvVar{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		}},
+		corev1.EnvVar{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+		}},
+	)
+}
+
+// configMapEnvVars reads the environment variables from the ConfigMap, they're
+// optional since the ConfigMap leaves the empty settings out
+func configMapEnvVars(keys [][2]string) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, corev1.EnvVar{
+			Name: k[0],
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ConfigMapName},
+					Key:                  k[1],
+					Optional:             ptr.To(true),
+				},
+			},
+		})
+	}
+	return env
+}
+
+func resources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("350m"),
+			corev1.ResourceMemory: resource.MustParse("400Mi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+			corev1.ResourceMemory: resource.MustParse("300Mi"),
+		},
+	}
+}
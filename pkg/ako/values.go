@@ -16,13 +16,15 @@ import (
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
-	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 )
 
 // Values defines the structures of an Ako addon secret string data
 // this constructs the payload (string data) of the corev1.Secret
 type Values struct {
 	LoadBalancerAndIngressService LoadBalancerAndIngressService `yaml:"loadBalancerAndIngressService"`
+
+	// dropped are the paths of the settings removed by ForVersion
+	dropped [][]string
 }
 
 // NewValues creates a new Values
@@ -91,7 +93,7 @@ func NewValuesFromBytes(data []byte) (*Values, error) {
 // YttYaml converts the AkoAddonSecretData to a Ytt Yaml template string,
 // return any unmarshall error occurs
 func (v *Values) YttYaml(cluster *clusterv1.Cluster) (string, error) {
-	buf, err := yaml.Marshal(v)
+	buf, err := v.marshal()
 	if err != nil {
 		return "", err
 	}
@@ -293,10 +295,13 @@ func NewNetworkSettings(obj *akoov1alpha1.AKODeploymentConfig) (*NetworkSettings
 
 	settings.NodeNetworkList = obj.Spec.ExtraConfigs.IngressConfigs.NodeNetworkList
 	//V6CIDR will enable the VS networks to use ipv6
-	if utils.GetIPFamilyFromCidr(obj.Spec.DataNetwork.CIDR) == "V6" {
-		settings.VIPNetworkList = []akoov1alpha1.VIPNetwork{{NetworkName: obj.Spec.DataNetwork.Name, V6CIDR: obj.Spec.DataNetwork.CIDR}}
-	} else {
-		settings.VIPNetworkList = []akoov1alpha1.VIPNetwork{{NetworkName: obj.Spec.DataNetwork.Name, CIDR: obj.Spec.DataNetwork.CIDR}}
+	for _, vipNetwork := range obj.VIPNetworks() {
+		// ip pools are configured in the Avi network, AKO doesn't take them
+		settings.VIPNetworkList = append(settings.VIPNetworkList, akoov1alpha1.VIPNetwork{
+			NetworkName: vipNetwork.NetworkName,
+			CIDR:        vipNetwork.CIDR,
+			V6CIDR:      vipNetwork.V6CIDR,
+		})
 	}
 
 	if len(settings.NodeNetworkList) != 0 {
@@ -339,6 +344,27 @@ func NewNetworkSettings(obj *akoov1alpha1.AKODeploymentConfig) (*NetworkSettings
 	return settings, nil
 }
 
+// ScopeVIPNetwork restricts the VIP network to the subnet of ipPool, AKO then
+// allocates the VIPs of the cluster from that subnet only
+func (s *NetworkSettings) ScopeVIPNetwork(networkName string, ipPool akoov1alpha1.IPPool) error {
+	for i := range s.VIPNetworkList {
+		if s.VIPNetworkList[i].NetworkName != networkName {
+			continue
+		}
+		if ipPool.Type == "V6" {
+			s.VIPNetworkList[i].V6CIDR = ipPool.CIDR()
+		} else {
+			s.VIPNetworkList[i].CIDR = ipPool.CIDR()
+		}
+	}
+	jsonBytes, err := json.Marshal(s.VIPNetworkList)
+	if err != nil {
+		return err
+	}
+	s.VIPNetworkListJson = string(jsonBytes)
+	return nil
+}
+
 // L7Settings outlines all the knobs used to control Layer 7 load balancing settings in AKO.
 type L7Settings struct {
 	DisableIngressClass  bool   `yaml:"disable_ingress_class"`
@@ -211,5 +211,74 @@ var _ = Describe("AKO", func() {
 				Expect(rendered.LoadBalancerAndIngressService.Config.FeatureGates.GatewayAPI).To(Equal("false"))
 			})
 		})
+
+		When("a dual-stack data network is provided", func() {
+			BeforeEach(func() {
+				akoDeploymentConfig = &akoov1alpha1.AKODeploymentConfig{
+					Spec: akoov1alpha1.AKODeploymentConfigSpec{
+						CloudName:          "test-cloud",
+						Controller:         "10.23.122.1",
+						ServiceEngineGroup: "Default-SEG",
+						DataNetwork: akoov1alpha1.DataNetwork{
+							Name:   "test-akdc",
+							CIDR:   "10.0.0.0/24",
+							V6CIDR: "2002::/64",
+							IPPools: []akoov1alpha1.IPPool{
+								{Start: "10.0.0.10", End: "10.0.0.20", Type: "V4"},
+								{Start: "2002::10", End: "2002::20", Type: "V6"},
+							},
+						},
+					},
+				}
+			})
+			It("should render both cidrs of the data network", func() {
+				networkSettings := rendered.LoadBalancerAndIngressService.Config.NetworkSettings
+				Expect(networkSettings.SubnetIP).To(Equal("10.0.0.0"))
+				Expect(networkSettings.SubnetPrefix).To(Equal("24"))
+				Expect(networkSettings.VIPNetworkListJson).To(Equal(
+					`[{"networkName":"test-akdc","cidr":"10.0.0.0/24","v6cidr":"2002::/64"}]`))
+			})
+		})
+
+		When("a VIP network list is provided", func() {
+			BeforeEach(func() {
+				akoDeploymentConfig = &akoov1alpha1.AKODeploymentConfig{
+					Spec: akoov1alpha1.AKODeploymentConfigSpec{
+						CloudName:          "test-cloud",
+						Controller:         "10.23.122.1",
+						ServiceEngineGroup: "Default-SEG",
+						DataNetwork: akoov1alpha1.DataNetwork{
+							Name: "test-akdc",
+							CIDR: "10.0.0.0/24",
+						},
+						ExtraConfigs: akoov1alpha1.ExtraConfigs{
+							NetworksConfig: akoov1alpha1.NetworksConfig{
+								VipNetworkList: []akoov1alpha1.VIPNetwork{
+									{
+										NetworkName: "public-vip",
+										CIDR:        "10.10.0.0/24",
+										IPPools: []akoov1alpha1.IPPool{
+											{Start: "10.10.0.10", End: "10.10.0.20", Type: "V4"},
+										},
+									},
+									{
+										NetworkName: "internal-vip",
+										CIDR:        "10.20.0.0/24",
+										V6CIDR:      "2002::/64",
+									},
+								},
+							},
+						},
+					},
+				}
+			})
+			It("should render every VIP network without the ip pools", func() {
+				networkSettings := rendered.LoadBalancerAndIngressService.Config.NetworkSettings
+				Expect(networkSettings.NetworkName).To(Equal("test-akdc"))
+				Expect(networkSettings.VIPNetworkListJson).To(Equal(
+					`[{"networkName":"public-vip","cidr":"10.10.0.0/24"},` +
+						`{"networkName":"internal-vip","cidr":"10.20.0.0/24","v6cidr":"2002::/64"}]`))
+			})
+		})
 	})
 })
@@ -48,9 +48,28 @@ type AviClientConfig struct {
 
 var ErrEmptyInput = errors.New("input is empty")
 
-// NewAviClientFromSecrets creates a Client from two secrets, adminCredential and CA
+// NewAviClientFromSecrets creates a Client from two secrets, adminCredential and CA.
+// The Client is rate limited and retries according to the options passed to
+// SetRetryOptions.
 func NewAviClientFromSecrets(c client.Client, ctx context.Context, log logr.Logger,
-	controllerIP, credName, credNamespace, caName, caNamespace, version string) (*realAviClient, error) {
+	controllerIP, credName, credNamespace, caName, caNamespace, version string) (Client, error) {
+	config, err := NewAviClientConfigFromSecrets(c, ctx, log, controllerIP, credName, credNamespace, caName, caNamespace)
+	if err != nil {
+		return nil, err
+	}
+	aviClient, err := newRetryingAviClient(config, version)
+	if err != nil {
+		log.Error(err, "Failed to initialize AVI Controller Client, requeue the request")
+		return nil, err
+	}
+
+	return aviClient, nil
+}
+
+// NewAviClientConfigFromSecrets reads the adminCredential and CA secrets and
+// returns the configuration to build a Client with
+func NewAviClientConfigFromSecrets(c client.Client, ctx context.Context, log logr.Logger,
+	controllerIP, credName, credNamespace, caName, caNamespace string) (*AviClientConfig, error) {
 	if controllerIP == "" {
 		log.Error(ErrEmptyInput, "controllerIP is empty", "controllerIP", controllerIP)
 		return nil, ErrEmptyInput
@@ -88,18 +107,22 @@ func NewAviClientFromSecrets(c client.Client, ctx context.Context, log logr.Logg
 		}
 		return nil, err
 	}
-	aviClient, err := NewAviClient(&AviClientConfig{
+	return &AviClientConfig{
 		ServerIP: controllerIP,
 		Username: string(adminCredential.Data["username"][:]),
 		Password: string(adminCredential.Data["password"][:]),
 		CA:       string(aviControllerCA.Data["certificateAuthorityData"][:]),
-	}, version)
+	}, nil
+}
+
+// newRetryingAviClient creates a Client decorated with the configured
+// retries and rate limiting. Every request is observed by the metrics.
+func newRetryingAviClient(config *AviClientConfig, version string) (Client, error) {
+	aviClient, err := NewAviClient(config, version)
 	if err != nil {
-		log.Error(err, "Failed to initialize AVI Controller Client, requeue the request")
 		return nil, err
 	}
-
-	return aviClient, nil
+	return NewRetryingClient(NewInstrumentedClient(aviClient), config.ServerIP, getRetryOptions()), nil
 }
 
 // NewAviClient creates an Client
@@ -147,8 +170,9 @@ func NewAviClient(config *AviClientConfig, version string) (*realAviClient, erro
 }
 
 // GetUUIDFromRef takes a AVI Ref, parses it as a classic URL and returns the
-// last part
+// last part, without the name appended by include_name
 func GetUUIDFromRef(ref string) string {
+	ref, _, _ = strings.Cut(ref, "#")
 	parts := strings.Split(ref, "/")
 	if len(parts) == 0 {
 		return ""
@@ -156,6 +180,13 @@ func GetUUIDFromRef(ref string) string {
 	return parts[len(parts)-1]
 }
 
+// IsAviAuthenticationError returns if the Avi Controller rejected the
+// credentials of a request
+func IsAviAuthenticationError(err error) bool {
+	code := statusCode(err)
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
 // IsAviUserAlreadyExistsError returns if an error is User Already Exists error
 // by matching error message
 func IsAviUserAlreadyExistsError(err error) bool {
@@ -176,6 +207,33 @@ func IsAviUserNonExistentError(err error) bool {
 	return err == nil && matched
 }
 
+// IsAviObjectNonExistentError returns if an error is Object doesn't exist
+// error returned by a lookup by name, e.g. of a network deleted by hand
+func IsAviObjectNonExistentError(err error) bool {
+	if err == nil {
+		return false
+	}
+	matched, err := regexp.Match(`No object of type .* with name .*is found`, []byte(err.Error()))
+	return err == nil && matched
+}
+
+// IsAviObjectNotFoundError returns if an error is the Not Found error
+// returned by a lookup by uuid
+func IsAviObjectNotFoundError(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsAviObjectInUseError returns if an error is the error returned when
+// deleting an object other objects still refer to, e.g. a Service Engine
+// Group which still holds Virtual Services
+func IsAviObjectInUseError(err error) bool {
+	if err == nil {
+		return false
+	}
+	matched, err := regexp.Match(`Cannot delete, object is referred by`, []byte(err.Error()))
+	return err == nil && matched
+}
+
 // IsAviRoleNonExistentError returns if an error is User role doesn't exist error
 // by matching error message
 func IsAviRoleNonExistentError(err error) bool {
@@ -219,6 +277,14 @@ func (r *realAviClient) ServiceEngineGroupCreate(obj *models.ServiceEngineGroup,
 	return r.ServiceEngineGroup.Create(obj)
 }
 
+func (r *realAviClient) ServiceEngineGroupUpdate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return r.ServiceEngineGroup.Update(obj)
+}
+
+func (r *realAviClient) ServiceEngineGroupDelete(uuid string, options ...session.ApiOptionsParams) error {
+	return r.ServiceEngineGroup.Delete(uuid)
+}
+
 func (r *realAviClient) NetworkGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.Network, error) {
 	var obj *models.Network
 	err := r.GetObjectByName("network", name, cloudName, &obj, options...)
@@ -233,6 +299,10 @@ func (r *realAviClient) NetworkUpdate(obj *models.Network, options ...session.Ap
 	return r.Network.Update(obj)
 }
 
+func (r *realAviClient) NetworkRuntimeGet(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error) {
+	return r.NetworkRuntime.Get(uuid)
+}
+
 func (r *realAviClient) CloudGetByName(name string, options ...session.ApiOptionsParams) (*models.Cloud, error) {
 	return r.Cloud.GetByName(name)
 }
@@ -253,6 +323,10 @@ func (r *realAviClient) UserGetByName(name string, options ...session.ApiOptions
 	return r.User.GetByName(name)
 }
 
+func (r *realAviClient) UserList(options ...session.ApiOptionsParams) ([]*models.User, error) {
+	return r.User.GetAll(options...)
+}
+
 func (r *realAviClient) UserDeleteByName(name string, options ...session.ApiOptionsParams) error {
 	return r.User.DeleteByName(name)
 }
@@ -269,16 +343,71 @@ func (r *realAviClient) TenantGet(uuid string, options ...session.ApiOptionsPara
 	return r.Tenant.Get(uuid)
 }
 
+func (r *realAviClient) TenantGetByName(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return r.Tenant.GetByName(name)
+}
+
+func (r *realAviClient) TenantCreate(obj *models.Tenant, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return r.Tenant.Create(obj)
+}
+
+func (r *realAviClient) TenantDelete(uuid string, options ...session.ApiOptionsParams) error {
+	return r.Tenant.Delete(uuid)
+}
+
+// tenantObjectKinds are the kinds of the objects which keep a tenant from
+// being deleted: the ones AKO creates and the roles
+var tenantObjectKinds = []string{
+	"virtualservice",
+	"vsvip",
+	"pool",
+	"poolgroup",
+	"httppolicyset",
+	"sslkeyandcertificate",
+	"role",
+}
+
+// TenantObjectCount counts the objects left in the tenant which AKO or users
+// created, the role of the Avi users of the tenant must be deleted first.
+// The roles and certificates of the admin tenant are visible in every tenant,
+// so only the objects whose tenant_ref is the tenant are counted.
+func (r *realAviClient) TenantObjectCount(name string, options ...session.ApiOptionsParams) (int, error) {
+	tenant, err := r.Tenant.GetByName(name, options...)
+	if err != nil {
+		return 0, err
+	}
+	options = append(options, session.SetOptTenant(name))
+	count := 0
+	for _, kind := range tenantObjectKinds {
+		result, err := r.AviSession.GetCollectionRaw("api/"+kind+"?tenant_ref.uuid="+url.QueryEscape(*tenant.UUID), options...)
+		if err != nil {
+			return 0, err
+		}
+		count += result.Count
+	}
+	return count, nil
+}
+
+// The role calls forward the options, the AKO role of a per-cluster tenant
+// is looked up in the tenant with session.SetOptTenant
+func (r *realAviClient) RoleGet(uuid string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return r.Role.Get(uuid, options...)
+}
+
 func (r *realAviClient) RoleGetByName(name string, options ...session.ApiOptionsParams) (*models.Role, error) {
-	return r.Role.GetByName(name)
+	return r.Role.GetByName(name, options...)
 }
 
 func (r *realAviClient) RoleCreate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error) {
-	return r.Role.Create(obj)
+	return r.Role.Create(obj, options...)
 }
 
 func (r *realAviClient) RoleUpdate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error) {
-	return r.Role.Update(obj)
+	return r.Role.Update(obj, options...)
+}
+
+func (r *realAviClient) RoleDelete(uuid string, options ...session.ApiOptionsParams) error {
+	return r.Role.Delete(uuid, options...)
 }
 
 func (r *realAviClient) VirtualServiceGetByName(name string, options ...session.ApiOptionsParams) (*models.VirtualService, error) {
@@ -289,6 +418,16 @@ func (r *realAviClient) PoolGetByName(name string, options ...session.ApiOptions
 	return r.Pool.GetByName(name)
 }
 
+// SystemConfigurationGet returns the system configuration, a singleton
+// which isn't looked up by UUID
+func (r *realAviClient) SystemConfigurationGet(options ...session.ApiOptionsParams) (*models.SystemConfiguration, error) {
+	obj := &models.SystemConfiguration{}
+	if err := r.AviSession.Get("api/systemconfiguration", obj, options...); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
 func (r *realAviClient) AviCertificateConfig() (string, error) {
 	return r.config.CA, nil
 }
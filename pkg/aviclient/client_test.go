@@ -0,0 +1,208 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/netprovider"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/test/aviserver"
+)
+
+var _ = Describe("Avi client against the fake Avi Controller", func() {
+	var (
+		server   *aviserver.Server
+		client   aviclient.Client
+		cloudRef string
+		ipamRef  string
+	)
+
+	strPtr := func(s string) *string { return &s }
+
+	BeforeEach(func() {
+		server = aviserver.NewServer()
+
+		var err error
+		ipamRef, err = server.Create("ipamdnsproviderprofile", &models.IPAMDNSProviderProfile{
+			Name:            strPtr("ipam"),
+			Type:            strPtr("IPAMDNS_TYPE_INTERNAL"),
+			InternalProfile: &models.IPAMDNSInternalProfile{},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		cloudRef, err = server.Create("cloud", &models.Cloud{
+			Name:            strPtr("Default-Cloud"),
+			Vtype:           strPtr("CLOUD_VCENTER"),
+			IPAMProviderRef: &ipamRef,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		otherCloudRef, err := server.Create("cloud", &models.Cloud{
+			Name:  strPtr("other-cloud"),
+			Vtype: strPtr("CLOUD_NONE"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		for _, ref := range []string{cloudRef, otherCloudRef} {
+			_, err = server.Create("network", &models.Network{Name: strPtr("vip-network"), CloudRef: strPtr(ref)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		client, err = aviclient.NewAviClient(server.ClientConfig(), "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should log in and report the controller version", func() {
+		Expect(client.GetControllerVersion()).To(Equal(aviserver.DefaultVersion))
+	})
+
+	It("should read the password complexity settings", func() {
+		sysConfig, err := client.SystemConfigurationGet()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*sysConfig.PortalConfiguration.PasswordStrengthCheck).To(BeTrue())
+		Expect(*sysConfig.PortalConfiguration.MinimumPasswordLength).To(Equal(uint32(8)))
+	})
+
+	It("should refuse wrong credentials", func() {
+		config := server.ClientConfig()
+		config.Password = "wrong"
+		_, err := aviclient.NewAviClient(config, "")
+		Expect(aviclient.IsAviAuthenticationError(err)).To(BeTrue())
+	})
+
+	It("should filter networks by cloud name", func() {
+		network, err := client.NetworkGetByName("vip-network", "Default-Cloud")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*network.CloudRef).To(Equal(cloudRef + "#Default-Cloud"))
+		Expect(*network.URL).To(HaveSuffix("#vip-network"))
+
+		_, err = client.NetworkGetByName("vip-network", "missing-cloud")
+		Expect(err).To(MatchError(ContainSubstring("No object of type network with name vip-network is found")))
+		Expect(aviclient.IsAviObjectNonExistentError(err)).To(BeTrue())
+	})
+
+	It("should create, update and read back objects", func() {
+		network, err := client.NetworkGetByName("vip-network", "Default-Cloud")
+		Expect(err).NotTo(HaveOccurred())
+		network.ConfiguredSubnets = []*models.Subnet{{
+			Prefix: &models.IPAddrPrefix{
+				IPAddr: &models.IPAddr{Addr: strPtr("10.0.0.0"), Type: strPtr("V4")},
+				Mask:   ptr.To(int32(24)),
+			},
+		}}
+		_, err = client.NetworkUpdate(network)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := &models.Network{}
+		Expect(server.GetByName("network", "vip-network", stored)).To(BeTrue())
+		Expect(stored.ConfiguredSubnets).To(HaveLen(1))
+		// names appended by include_name are not persisted
+		Expect(*stored.CloudRef).To(Equal(cloudRef))
+
+		seg, err := client.ServiceEngineGroupCreate(&models.ServiceEngineGroup{Name: strPtr("seg"), CloudRef: &cloudRef})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*seg.UUID).NotTo(BeEmpty())
+		seg, err = client.ServiceEngineGroupGetByName("seg", "Default-Cloud")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*seg.Name).To(Equal("seg"))
+		seg.MaxSe = ptr.To(int32(4))
+		_, err = client.ServiceEngineGroupUpdate(seg)
+		Expect(err).NotTo(HaveOccurred())
+		storedSEG := &models.ServiceEngineGroup{}
+		Expect(server.GetByName("serviceenginegroup", "seg", storedSEG)).To(BeTrue())
+		Expect(*storedSEG.MaxSe).To(Equal(int32(4)))
+
+		_, err = server.Create("virtualservice", &models.VirtualService{Name: strPtr("vs"), SeGroupRef: seg.URL})
+		Expect(err).NotTo(HaveOccurred())
+		err = client.ServiceEngineGroupDelete(*seg.UUID)
+		Expect(aviclient.IsAviObjectInUseError(err)).To(BeTrue())
+		server.Delete("virtualservice", "vs")
+		Expect(client.ServiceEngineGroupDelete(*seg.UUID)).To(Succeed())
+		Expect(server.GetByName("serviceenginegroup", "seg", storedSEG)).To(BeFalse())
+	})
+
+	It("should manage users", func() {
+		_, err := client.UserCreate(&models.User{Name: strPtr("ako-user"), Username: strPtr("ako-user")})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.UserCreate(&models.User{Name: strPtr("ako-user"), Username: strPtr("ako-user")})
+		Expect(aviclient.IsAviUserAlreadyExistsError(err)).To(BeTrue())
+		users, err := client.UserList()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(HaveLen(1))
+		Expect(*users[0].Name).To(Equal("ako-user"))
+
+		Expect(client.UserDeleteByName("ako-user")).To(Succeed())
+		Expect(server.Len("user")).To(Equal(0))
+		_, err = client.UserGetByName("ako-user")
+		Expect(aviclient.IsAviUserNonExistentError(err)).To(BeTrue())
+
+		tenant, err := client.TenantGet("admin")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*tenant.Name).To(Equal("admin"))
+	})
+
+	It("should manage tenants", func() {
+		tenant, err := client.TenantCreate(&models.Tenant{Name: strPtr("dev-workload")})
+		Expect(err).NotTo(HaveOccurred())
+		tenant, err = client.TenantGetByName("dev-workload")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*tenant.Name).To(Equal("dev-workload"))
+
+		_, err = server.Create("virtualservice", &models.VirtualService{Name: strPtr("vs"), TenantRef: tenant.URL})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = server.Create("vsvip", &models.VsVip{Name: strPtr("vsvip"), TenantRef: tenant.URL})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = server.Create("pool", &models.Pool{Name: strPtr("pool")})
+		Expect(err).NotTo(HaveOccurred())
+		// the roles and certificates of the admin tenant are visible in every
+		// tenant but don't belong to it
+		_, err = server.Create("role", &models.Role{Name: strPtr("System-Admin")})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = server.Create("sslkeyandcertificate", &models.SSLKeyAndCertificate{Name: strPtr("System-Default-Cert")})
+		Expect(err).NotTo(HaveOccurred())
+		role, err := client.RoleCreate(&models.Role{Name: strPtr("ako-essential-role"), TenantRef: tenant.URL})
+		Expect(err).NotTo(HaveOccurred())
+		count, err := client.TenantObjectCount("dev-workload")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(3))
+
+		Expect(client.RoleDelete(*role.UUID)).To(Succeed())
+		count, err = client.TenantObjectCount("dev-workload")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(2))
+
+		Expect(client.TenantDelete(*tenant.UUID)).To(Succeed())
+		_, err = client.TenantGetByName("dev-workload")
+		Expect(aviclient.IsAviObjectNonExistentError(err)).To(BeTrue())
+	})
+
+	It("should register a usable network in the IPAM profile once", func() {
+		provider := &netprovider.UsableNetworkProvider{}
+		log := ctrl.Log.WithName("test")
+		Expect(provider.AddUsableNetwork(client, "Default-Cloud", "vip-network", log)).To(BeTrue())
+		Expect(provider.AddUsableNetwork(client, "Default-Cloud", "vip-network", log)).To(BeFalse())
+
+		ipam, err := client.IPAMDNSProviderProfileGet(aviclient.GetUUIDFromRef(ipamRef))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ipam.InternalProfile.UsableNetworks).To(HaveLen(1))
+	})
+
+	It("should unregister a usable network from the IPAM profile", func() {
+		provider := &netprovider.UsableNetworkProvider{}
+		log := ctrl.Log.WithName("test")
+		Expect(provider.AddUsableNetwork(client, "Default-Cloud", "vip-network", log)).To(BeTrue())
+		Expect(provider.RemoveUsableNetwork(client, "Default-Cloud", "vip-network", log)).To(Succeed())
+		Expect(provider.RemoveUsableNetwork(client, "Default-Cloud", "vip-network", log)).To(Succeed())
+
+		ipam, err := client.IPAMDNSProviderProfileGet(aviclient.GetUUIDFromRef(ipamRef))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ipam.InternalProfile.UsableNetworks).To(BeEmpty())
+	})
+})
@@ -5,9 +5,11 @@ package aviclient
 
 import (
 	"errors"
+	"net/http"
 
 	"github.com/vmware/alb-sdk/go/models"
 	"github.com/vmware/alb-sdk/go/session"
+	"k8s.io/utils/ptr"
 )
 
 // FakeAviClient -- an API Client for Avi Controller for intg test of AkoDeploymentConfig
@@ -21,6 +23,8 @@ type FakeAviClient struct {
 	Role                   *RoleClient
 	VirtualService         *VirtualServiceClient
 	Pool                   *PoolClient
+	// SystemConfiguration is returned by SystemConfigurationGet
+	SystemConfiguration *models.SystemConfiguration
 }
 
 func NewFakeAviClient() *FakeAviClient {
@@ -32,6 +36,7 @@ func NewFakeAviClient() *FakeAviClient {
 		User:                   &UserClient{},
 		Tenant:                 &TenantClient{},
 		Role:                   &RoleClient{},
+		SystemConfiguration:    &models.SystemConfiguration{},
 	}
 }
 
@@ -49,6 +54,14 @@ func (r *FakeAviClient) ServiceEngineGroupCreate(obj *models.ServiceEngineGroup,
 	return obj, nil
 }
 
+func (r *FakeAviClient) ServiceEngineGroupUpdate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return r.ServiceEngineGroup.Update(obj)
+}
+
+func (r *FakeAviClient) ServiceEngineGroupDelete(uuid string, options ...session.ApiOptionsParams) error {
+	return r.ServiceEngineGroup.Delete(uuid)
+}
+
 func (r *FakeAviClient) NetworkGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.Network, error) {
 	return r.Network.GetByName(name)
 }
@@ -67,6 +80,10 @@ func (r *FakeAviClient) NetworkUpdate(obj *models.Network, options ...session.Ap
 	return r.Network.Update(obj)
 }
 
+func (r *FakeAviClient) NetworkRuntimeGet(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error) {
+	return r.Network.GetRuntime(uuid)
+}
+
 func (r *FakeAviClient) CloudGetByName(name string, options ...session.ApiOptionsParams) (*models.Cloud, error) {
 	return r.Cloud.GetByName(name)
 }
@@ -92,6 +109,10 @@ func (r *FakeAviClient) IPAMDNSProviderProfileUpdate(obj *models.IPAMDNSProvider
 func (r *FakeAviClient) UserGetByName(name string, options ...session.ApiOptionsParams) (*models.User, error) {
 	return r.User.GetByName(name)
 }
+func (r *FakeAviClient) UserList(options ...session.ApiOptionsParams) ([]*models.User, error) {
+	return r.User.GetAll()
+}
+
 func (r *FakeAviClient) UserDeleteByName(name string, options ...session.ApiOptionsParams) error {
 	return r.User.DeleteByName(name)
 }
@@ -108,6 +129,26 @@ func (r *FakeAviClient) TenantGet(uuid string, options ...session.ApiOptionsPara
 	return r.Tenant.Get(uuid)
 }
 
+func (r *FakeAviClient) TenantGetByName(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return r.Tenant.GetByName(name)
+}
+
+func (r *FakeAviClient) TenantCreate(obj *models.Tenant, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return r.Tenant.Create(obj)
+}
+
+func (r *FakeAviClient) TenantDelete(uuid string, options ...session.ApiOptionsParams) error {
+	return r.Tenant.Delete(uuid)
+}
+
+func (r *FakeAviClient) TenantObjectCount(name string, options ...session.ApiOptionsParams) (int, error) {
+	return r.Tenant.ObjectCount(name)
+}
+
+func (r *FakeAviClient) RoleGet(uuid string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return r.Role.Get(uuid)
+}
+
 func (r *FakeAviClient) RoleGetByName(name string, options ...session.ApiOptionsParams) (*models.Role, error) {
 	return r.Role.GetByName(name)
 }
@@ -120,6 +161,10 @@ func (r *FakeAviClient) RoleUpdate(obj *models.Role, options ...session.ApiOptio
 	return r.Role.Update(obj)
 }
 
+func (r *FakeAviClient) RoleDelete(uuid string, options ...session.ApiOptionsParams) error {
+	return r.Role.Delete(uuid)
+}
+
 func (r *FakeAviClient) VirtualServiceGetByName(name string, options ...session.ApiOptionsParams) (*models.VirtualService, error) {
 	return r.VirtualService.GetByName(name)
 }
@@ -128,6 +173,10 @@ func (r *FakeAviClient) PoolGetByName(name string, options ...session.ApiOptions
 	return r.Pool.GetByName(name)
 }
 
+func (r *FakeAviClient) SystemConfigurationGet(options ...session.ApiOptionsParams) (*models.SystemConfiguration, error) {
+	return r.SystemConfiguration, nil
+}
+
 func (r *FakeAviClient) AviCertificateConfig() (string, error) {
 	return "", nil
 }
@@ -139,9 +188,13 @@ func (r *FakeAviClient) GetControllerVersion() (string, error) {
 // ServiceEngineGroup Client
 type ServiceEngineGroupClient struct {
 	getByNameFn GetByNameSEGFunc
+	updateFn    UpdateSEGFn
+	deleteFn    DeleteSEGFn
 }
 
 type GetByNameSEGFunc func(name string, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error)
+type UpdateSEGFn func(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error)
+type DeleteSEGFn func(uuid string, options ...session.ApiOptionsParams) error
 
 func (client *ServiceEngineGroupClient) SetGetByNameFn(fn GetByNameSEGFunc) {
 	client.getByNameFn = fn
@@ -151,14 +204,38 @@ func (client *ServiceEngineGroupClient) GetByName(name string, options ...sessio
 	return client.getByNameFn(name)
 }
 
+func (client *ServiceEngineGroupClient) SetUpdateFn(fn UpdateSEGFn) {
+	client.updateFn = fn
+}
+
+func (client *ServiceEngineGroupClient) Update(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	if client.updateFn == nil {
+		return obj, nil
+	}
+	return client.updateFn(obj)
+}
+
+func (client *ServiceEngineGroupClient) SetDeleteFn(fn DeleteSEGFn) {
+	client.deleteFn = fn
+}
+
+func (client *ServiceEngineGroupClient) Delete(uuid string, options ...session.ApiOptionsParams) error {
+	if client.deleteFn == nil {
+		return nil
+	}
+	return client.deleteFn(uuid)
+}
+
 // Network Client
 type NetworkClient struct {
-	getByNameFn GetByNameFunc
-	updateFn    UpdateFn
+	getByNameFn  GetByNameFunc
+	updateFn     UpdateFn
+	getRuntimeFn GetRuntimeFunc
 }
 
 type GetByNameFunc func(name string, options ...session.ApiOptionsParams) (*models.Network, error)
 type UpdateFn func(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error)
+type GetRuntimeFunc func(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error)
 
 func (client *NetworkClient) SetGetByNameFn(fn GetByNameFunc) {
 	client.getByNameFn = fn
@@ -176,6 +253,18 @@ func (client *NetworkClient) Update(obj *models.Network, options ...session.ApiO
 	return client.updateFn(obj)
 }
 
+func (client *NetworkClient) SetGetRuntimeFn(fn GetRuntimeFunc) {
+	client.getRuntimeFn = fn
+}
+
+// GetRuntime returns a runtime without any allocated IP unless a function is set
+func (client *NetworkClient) GetRuntime(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error) {
+	if client.getRuntimeFn == nil {
+		return &models.NetworkRuntime{UUID: &uuid}, nil
+	}
+	return client.getRuntimeFn(uuid)
+}
+
 // Cloud Client
 type CloudClient struct {
 	getByNameCloudFn GetByNameCloudFunc
@@ -219,12 +308,14 @@ func (client *IPAMDNSProviderProfileClient) Update(obj *models.IPAMDNSProviderPr
 // User Client
 type UserClient struct {
 	getByNameUserFn    GetByNameUserFunc
+	getAllUserFn       GetAllUserFunc
 	deleteByNameUserFn DeleteByNameUserFunc
 	createUserFunc     CreateUserFunc
 	updateUserFunc     UpdateUserFunc
 }
 
 type GetByNameUserFunc func(name string, options ...session.ApiOptionsParams) (*models.User, error)
+type GetAllUserFunc func(options ...session.ApiOptionsParams) ([]*models.User, error)
 type DeleteByNameUserFunc func(name string, options ...session.ApiOptionsParams) error
 type CreateUserFunc func(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error)
 type UpdateUserFunc func(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error)
@@ -233,6 +324,10 @@ func (client *UserClient) SetGetByNameUserFunc(fn GetByNameUserFunc) {
 	client.getByNameUserFn = fn
 }
 
+func (client *UserClient) SetGetAllUserFunc(fn GetAllUserFunc) {
+	client.getAllUserFn = fn
+}
+
 func (client *UserClient) SetDeleteByNameUserFunc(fn DeleteByNameUserFunc) {
 	client.deleteByNameUserFn = fn
 }
@@ -249,6 +344,10 @@ func (client *UserClient) GetByName(name string, options ...session.ApiOptionsPa
 	return client.getByNameUserFn(name)
 }
 
+func (client *UserClient) GetAll(options ...session.ApiOptionsParams) ([]*models.User, error) {
+	return client.getAllUserFn()
+}
+
 func (client *UserClient) DeleteByName(name string, options ...session.ApiOptionsParams) error {
 	return client.deleteByNameUserFn(name)
 }
@@ -261,12 +360,19 @@ func (client *UserClient) Update(obj *models.User, options ...session.ApiOptions
 	return client.updateUserFunc(obj)
 }
 
-// Tenant Client
+// Tenant Client, the tenants it creates are returned by GetByName before
+// the ones of getByNameFn
 type TenantClient struct {
-	getTenantFn GetTenantFunc
+	getTenantFn   GetTenantFunc
+	getByNameFn   GetTenantFunc
+	deleteFn      DeleteTenantFunc
+	objectCountFn ObjectCountTenantFunc
+	created       map[string]*models.Tenant
 }
 
 type GetTenantFunc func(uuid string, options ...session.ApiOptionsParams) (*models.Tenant, error)
+type DeleteTenantFunc func(uuid string, options ...session.ApiOptionsParams) error
+type ObjectCountTenantFunc func(name string, options ...session.ApiOptionsParams) (int, error)
 
 func (client *TenantClient) SetGetTenantFunc(fn GetTenantFunc) {
 	client.getTenantFn = fn
@@ -276,16 +382,73 @@ func (client *TenantClient) Get(uuid string, options ...session.ApiOptionsParams
 	return client.getTenantFn(uuid)
 }
 
+func (client *TenantClient) SetGetByNameFn(fn GetTenantFunc) {
+	client.getByNameFn = fn
+}
+
+func (client *TenantClient) GetByName(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	if tenant, ok := client.created[name]; ok {
+		return tenant, nil
+	}
+	if client.getByNameFn == nil {
+		return nil, errors.New("No object of type tenant with name " + name + " is found")
+	}
+	return client.getByNameFn(name)
+}
+
+func (client *TenantClient) Create(obj *models.Tenant, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	if obj == nil || obj.Name == nil {
+		return nil, errors.New("tenant name is required")
+	}
+	if client.created == nil {
+		client.created = map[string]*models.Tenant{}
+	}
+	client.created[*obj.Name] = obj
+	return obj, nil
+}
+
+func (client *TenantClient) SetDeleteFn(fn DeleteTenantFunc) {
+	client.deleteFn = fn
+}
+
+func (client *TenantClient) Delete(uuid string, options ...session.ApiOptionsParams) error {
+	for name, tenant := range client.created {
+		if ptr.Deref(tenant.UUID, "") == uuid {
+			delete(client.created, name)
+		}
+	}
+	if client.deleteFn == nil {
+		return nil
+	}
+	return client.deleteFn(uuid)
+}
+
+func (client *TenantClient) SetObjectCountFn(fn ObjectCountTenantFunc) {
+	client.objectCountFn = fn
+}
+
+func (client *TenantClient) ObjectCount(name string, options ...session.ApiOptionsParams) (int, error) {
+	return client.objectCountFn(name)
+}
+
 // Role Client
 type RoleClient struct {
+	getRoleFn       GetRoleFunc
 	getByNameRoleFn GetByNameRoleFunc
 	createRoleFunc  CreateRoleFunc
 	updateRoleFunc  UpdateRoleFunc
+	deleteRoleFunc  DeleteRoleFunc
 }
 
+type GetRoleFunc func(uuid string, options ...session.ApiOptionsParams) (*models.Role, error)
 type GetByNameRoleFunc func(name string, options ...session.ApiOptionsParams) (*models.Role, error)
 type CreateRoleFunc func(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error)
 type UpdateRoleFunc func(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error)
+type DeleteRoleFunc func(uuid string, options ...session.ApiOptionsParams) error
+
+func (client *RoleClient) SetGetRoleFunc(fn GetRoleFunc) {
+	client.getRoleFn = fn
+}
 
 func (client *RoleClient) SetGetByNameRoleFunc(fn GetByNameRoleFunc) {
 	client.getByNameRoleFn = fn
@@ -299,6 +462,17 @@ func (client *RoleClient) SetUpdateRoleFunc(fn UpdateRoleFunc) {
 	client.updateRoleFunc = fn
 }
 
+func (client *RoleClient) SetDeleteRoleFunc(fn DeleteRoleFunc) {
+	client.deleteRoleFunc = fn
+}
+
+func (client *RoleClient) Get(uuid string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	if client.getRoleFn == nil {
+		return nil, session.AviError{HttpStatusCode: http.StatusNotFound}
+	}
+	return client.getRoleFn(uuid)
+}
+
 func (client *RoleClient) GetByName(name string, options ...session.ApiOptionsParams) (*models.Role, error) {
 	return client.getByNameRoleFn(name)
 }
@@ -311,6 +485,13 @@ func (client *RoleClient) Update(obj *models.Role, options ...session.ApiOptions
 	return client.updateRoleFunc(obj)
 }
 
+func (client *RoleClient) Delete(uuid string, options ...session.ApiOptionsParams) error {
+	if client.deleteRoleFunc == nil {
+		return nil
+	}
+	return client.deleteRoleFunc(uuid)
+}
+
 // Pool Client
 type PoolClient struct {
 	getByNameFn GetByNamePoolFunc
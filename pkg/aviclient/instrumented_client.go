@@ -0,0 +1,235 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+)
+
+// instrumentedClient decorates a Client so that the latency and the status
+// of every request are recorded in metrics.AviAPIRequestDuration
+type instrumentedClient struct {
+	client Client
+}
+
+// NewInstrumentedClient wraps client so that its requests are observed
+func NewInstrumentedClient(client Client) Client {
+	return &instrumentedClient{client: client}
+}
+
+// requestStatus returns the status label of a request outcome
+func requestStatus(err error) string {
+	if err == nil {
+		return "success"
+	}
+	if code := statusCode(err); code != 0 {
+		return strconv.Itoa(code)
+	}
+	return "error"
+}
+
+func observe[T any](method string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	result, err := fn()
+	metrics.AviAPIRequestDuration.WithLabelValues(method, requestStatus(err)).Observe(time.Since(start).Seconds())
+	return result, err
+}
+
+func (r *instrumentedClient) ServiceEngineGroupGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return observe("ServiceEngineGroupGetByName", func() (*models.ServiceEngineGroup, error) {
+		return r.client.ServiceEngineGroupGetByName(name, cloudName, options...)
+	})
+}
+
+func (r *instrumentedClient) ServiceEngineGroupCreate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return observe("ServiceEngineGroupCreate", func() (*models.ServiceEngineGroup, error) {
+		return r.client.ServiceEngineGroupCreate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) ServiceEngineGroupUpdate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return observe("ServiceEngineGroupUpdate", func() (*models.ServiceEngineGroup, error) {
+		return r.client.ServiceEngineGroupUpdate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) ServiceEngineGroupDelete(uuid string, options ...session.ApiOptionsParams) error {
+	_, err := observe("ServiceEngineGroupDelete", func() (struct{}, error) {
+		return struct{}{}, r.client.ServiceEngineGroupDelete(uuid, options...)
+	})
+	return err
+}
+
+func (r *instrumentedClient) NetworkGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.Network, error) {
+	return observe("NetworkGetByName", func() (*models.Network, error) {
+		return r.client.NetworkGetByName(name, cloudName, options...)
+	})
+}
+
+func (r *instrumentedClient) NetworkCreate(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+	return observe("NetworkCreate", func() (*models.Network, error) {
+		return r.client.NetworkCreate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) NetworkUpdate(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+	return observe("NetworkUpdate", func() (*models.Network, error) {
+		return r.client.NetworkUpdate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) NetworkRuntimeGet(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error) {
+	return observe("NetworkRuntimeGet", func() (*models.NetworkRuntime, error) {
+		return r.client.NetworkRuntimeGet(uuid, options...)
+	})
+}
+
+func (r *instrumentedClient) CloudGetByName(name string, options ...session.ApiOptionsParams) (*models.Cloud, error) {
+	return observe("CloudGetByName", func() (*models.Cloud, error) {
+		return r.client.CloudGetByName(name, options...)
+	})
+}
+
+func (r *instrumentedClient) CloudCreate(obj *models.Cloud, options ...session.ApiOptionsParams) (*models.Cloud, error) {
+	return observe("CloudCreate", func() (*models.Cloud, error) {
+		return r.client.CloudCreate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) UserGetByName(name string, options ...session.ApiOptionsParams) (*models.User, error) {
+	return observe("UserGetByName", func() (*models.User, error) {
+		return r.client.UserGetByName(name, options...)
+	})
+}
+
+func (r *instrumentedClient) UserList(options ...session.ApiOptionsParams) ([]*models.User, error) {
+	return observe("UserList", func() ([]*models.User, error) {
+		return r.client.UserList(options...)
+	})
+}
+
+func (r *instrumentedClient) UserDeleteByName(name string, options ...session.ApiOptionsParams) error {
+	_, err := observe("UserDeleteByName", func() (struct{}, error) {
+		return struct{}{}, r.client.UserDeleteByName(name, options...)
+	})
+	return err
+}
+
+func (r *instrumentedClient) UserCreate(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error) {
+	return observe("UserCreate", func() (*models.User, error) {
+		return r.client.UserCreate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) UserUpdate(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error) {
+	return observe("UserUpdate", func() (*models.User, error) {
+		return r.client.UserUpdate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) TenantGet(uuid string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return observe("TenantGet", func() (*models.Tenant, error) {
+		return r.client.TenantGet(uuid, options...)
+	})
+}
+
+func (r *instrumentedClient) TenantGetByName(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return observe("TenantGetByName", func() (*models.Tenant, error) {
+		return r.client.TenantGetByName(name, options...)
+	})
+}
+
+func (r *instrumentedClient) TenantCreate(obj *models.Tenant, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return observe("TenantCreate", func() (*models.Tenant, error) {
+		return r.client.TenantCreate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) TenantDelete(uuid string, options ...session.ApiOptionsParams) error {
+	_, err := observe("TenantDelete", func() (struct{}, error) {
+		return struct{}{}, r.client.TenantDelete(uuid, options...)
+	})
+	return err
+}
+
+func (r *instrumentedClient) TenantObjectCount(name string, options ...session.ApiOptionsParams) (int, error) {
+	return observe("TenantObjectCount", func() (int, error) {
+		return r.client.TenantObjectCount(name, options...)
+	})
+}
+
+func (r *instrumentedClient) RoleGet(uuid string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return observe("RoleGet", func() (*models.Role, error) {
+		return r.client.RoleGet(uuid, options...)
+	})
+}
+
+func (r *instrumentedClient) RoleGetByName(name string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return observe("RoleGetByName", func() (*models.Role, error) {
+		return r.client.RoleGetByName(name, options...)
+	})
+}
+
+func (r *instrumentedClient) RoleCreate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return observe("RoleCreate", func() (*models.Role, error) {
+		return r.client.RoleCreate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) RoleUpdate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return observe("RoleUpdate", func() (*models.Role, error) {
+		return r.client.RoleUpdate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) RoleDelete(uuid string, options ...session.ApiOptionsParams) error {
+	_, err := observe("RoleDelete", func() (struct{}, error) {
+		return struct{}{}, r.client.RoleDelete(uuid, options...)
+	})
+	return err
+}
+
+func (r *instrumentedClient) IPAMDNSProviderProfileGet(uuid string, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error) {
+	return observe("IPAMDNSProviderProfileGet", func() (*models.IPAMDNSProviderProfile, error) {
+		return r.client.IPAMDNSProviderProfileGet(uuid, options...)
+	})
+}
+
+func (r *instrumentedClient) IPAMDNSProviderProfileUpdate(obj *models.IPAMDNSProviderProfile, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error) {
+	return observe("IPAMDNSProviderProfileUpdate", func() (*models.IPAMDNSProviderProfile, error) {
+		return r.client.IPAMDNSProviderProfileUpdate(obj, options...)
+	})
+}
+
+func (r *instrumentedClient) VirtualServiceGetByName(name string, options ...session.ApiOptionsParams) (*models.VirtualService, error) {
+	return observe("VirtualServiceGetByName", func() (*models.VirtualService, error) {
+		return r.client.VirtualServiceGetByName(name, options...)
+	})
+}
+
+func (r *instrumentedClient) PoolGetByName(name string, options ...session.ApiOptionsParams) (*models.Pool, error) {
+	return observe("PoolGetByName", func() (*models.Pool, error) {
+		return r.client.PoolGetByName(name, options...)
+	})
+}
+
+func (r *instrumentedClient) AviCertificateConfig() (string, error) {
+	return r.client.AviCertificateConfig()
+}
+
+func (r *instrumentedClient) SystemConfigurationGet(options ...session.ApiOptionsParams) (*models.SystemConfiguration, error) {
+	return observe("SystemConfigurationGet", func() (*models.SystemConfiguration, error) {
+		return r.client.SystemConfigurationGet(options...)
+	})
+}
+
+func (r *instrumentedClient) GetControllerVersion() (string, error) {
+	return observe("GetControllerVersion", r.client.GetControllerVersion)
+}
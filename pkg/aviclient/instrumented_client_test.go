@@ -0,0 +1,57 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/test/aviserver"
+)
+
+var _ = Describe("Instrumented Avi client", func() {
+	var (
+		server *aviserver.Server
+		client aviclient.Client
+	)
+
+	BeforeEach(func() {
+		server = aviserver.NewServer()
+		realClient, err := aviclient.NewAviClient(server.ClientConfig(), "")
+		Expect(err).NotTo(HaveOccurred())
+		client = aviclient.NewInstrumentedClient(realClient)
+		metrics.AviAPIRequestDuration.Reset()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should observe requests by method and status", func() {
+		_, err := client.TenantGet("admin")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.TenantGet("missing")
+		Expect(err).To(HaveOccurred())
+		_, err = client.UserGetByName("missing")
+		Expect(err).To(HaveOccurred())
+
+		Expect(testutil.CollectAndCount(metrics.AviAPIRequestDuration)).To(Equal(3))
+		Expect(sampleCount("TenantGet", "success")).To(Equal(uint64(1)))
+		Expect(sampleCount("TenantGet", "404")).To(Equal(uint64(1)))
+		// a lookup by name without match isn't an HTTP error
+		Expect(sampleCount("UserGetByName", "error")).To(Equal(uint64(1)))
+	})
+})
+
+func sampleCount(method, status string) uint64 {
+	m := &dto.Metric{}
+	observer := metrics.AviAPIRequestDuration.WithLabelValues(method, status)
+	Expect(observer.(prometheus.Metric).Write(m)).To(Succeed())
+	return m.GetHistogram().GetSampleCount()
+}
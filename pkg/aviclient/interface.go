@@ -10,24 +10,34 @@ import (
 type Client interface {
 	ServiceEngineGroupGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error)
 	ServiceEngineGroupCreate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error)
+	ServiceEngineGroupUpdate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error)
+	ServiceEngineGroupDelete(uuid string, options ...session.ApiOptionsParams) error
 
 	NetworkGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.Network, error)
 	NetworkCreate(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error)
 	NetworkUpdate(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error)
+	NetworkRuntimeGet(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error)
 
 	CloudGetByName(name string, options ...session.ApiOptionsParams) (*models.Cloud, error)
 	CloudCreate(obj *models.Cloud, options ...session.ApiOptionsParams) (*models.Cloud, error)
 
 	UserGetByName(name string, options ...session.ApiOptionsParams) (*models.User, error)
+	UserList(options ...session.ApiOptionsParams) ([]*models.User, error)
 	UserDeleteByName(name string, options ...session.ApiOptionsParams) error
 	UserCreate(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error)
 	UserUpdate(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error)
 
 	TenantGet(uuid string, options ...session.ApiOptionsParams) (*models.Tenant, error)
+	TenantGetByName(name string, options ...session.ApiOptionsParams) (*models.Tenant, error)
+	TenantCreate(obj *models.Tenant, options ...session.ApiOptionsParams) (*models.Tenant, error)
+	TenantDelete(uuid string, options ...session.ApiOptionsParams) error
+	TenantObjectCount(name string, options ...session.ApiOptionsParams) (int, error)
 
+	RoleGet(uuid string, options ...session.ApiOptionsParams) (*models.Role, error)
 	RoleGetByName(name string, options ...session.ApiOptionsParams) (*models.Role, error)
 	RoleCreate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error)
 	RoleUpdate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error)
+	RoleDelete(uuid string, options ...session.ApiOptionsParams) error
 
 	IPAMDNSProviderProfileGet(uuid string, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error)
 	IPAMDNSProviderProfileUpdate(obj *models.IPAMDNSProviderProfile, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error)
@@ -36,6 +46,8 @@ type Client interface {
 
 	PoolGetByName(name string, options ...session.ApiOptionsParams) (*models.Pool, error)
 
+	SystemConfigurationGet(options ...session.ApiOptionsParams) (*models.SystemConfiguration, error)
+
 	AviCertificateConfig() (string, error)
 
 	GetControllerVersion() (string, error)
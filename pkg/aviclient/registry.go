@@ -0,0 +1,173 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ClientKey identifies an Avi Controller session. AKODeploymentConfigs
+// with the same key share one session.
+type ClientKey struct {
+	// Controller is the Avi Controller endpoint
+	Controller string
+	// AdminCredentialRef is the namespace/name of the admin credential secret
+	AdminCredentialRef string
+	// CARef is the namespace/name of the Avi Controller CA secret
+	CARef string
+	// Tenant is the Avi tenant
+	Tenant string
+}
+
+// ClientFactory creates a Client, it defaults to NewAviClient decorated with
+// the configured rate limiting
+type ClientFactory func(config *AviClientConfig, version string) (Client, error)
+
+// Registry caches one Client per ClientKey. Owners, usually
+// AKODeploymentConfigs, acquire the Client of their key and release it once
+// they are gone. A Client is evicted when no owner references it anymore.
+type Registry struct {
+	mu        sync.Mutex
+	newClient ClientFactory
+	entries   map[ClientKey]*registryEntry
+	owners    map[string]ClientKey
+}
+
+type registryEntry struct {
+	client Client
+	// fingerprint is the hash of the credentials and CA the client was
+	// created with
+	fingerprint string
+	version     string
+	owners      sets.Set[string]
+}
+
+// DefaultRegistry is shared by the AKODeploymentConfig controller and the
+// AKODeploymentConfig webhook
+var DefaultRegistry = NewRegistry(nil)
+
+// NewRegistry returns an empty Registry creating clients with factory, or
+// with the default ClientFactory when factory is nil
+func NewRegistry(factory ClientFactory) *Registry {
+	if factory == nil {
+		factory = newRetryingAviClient
+	}
+	return &Registry{
+		newClient: factory,
+		entries:   map[ClientKey]*registryEntry{},
+		owners:    map[string]ClientKey{},
+	}
+}
+
+// Acquire returns the Client of key and records owner as one of its users.
+// The cached Client is replaced when the credentials or the CA changed, or
+// when a version is given and differs from the cached one. If owner
+// previously held another key, that reference is released.
+func (r *Registry) Acquire(owner string, key ClientKey, config *AviClientConfig, version string) (Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, err := r.getOrCreate(key, config, version)
+	if err != nil {
+		return nil, err
+	}
+	if previous, ok := r.owners[owner]; ok && previous != key {
+		r.release(owner, previous)
+	}
+	r.owners[owner] = key
+	entry.owners.Insert(owner)
+	return entry.client, nil
+}
+
+// Get returns the cached Client of key when it matches the credentials, CA
+// and version, otherwise a new Client which isn't cached
+func (r *Registry) Get(key ClientKey, config *AviClientConfig, version string) (Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.entries[key]; ok && entry.matches(config, version) {
+		return entry.client, nil
+	}
+	return r.newClient(config, version)
+}
+
+// ClientFor returns the Client acquired by owner, or nil
+func (r *Registry) ClientFor(owner string) Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.owners[owner]
+	if !ok {
+		return nil
+	}
+	return r.entries[key].client
+}
+
+// Release drops the reference owner holds. The Client is evicted once it
+// has no owner left.
+func (r *Registry) Release(owner string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, ok := r.owners[owner]; ok {
+		r.release(owner, key)
+		delete(r.owners, owner)
+	}
+}
+
+// Len returns the number of cached clients
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+func (r *Registry) getOrCreate(key ClientKey, config *AviClientConfig, version string) (*registryEntry, error) {
+	entry, ok := r.entries[key]
+	if ok && entry.matches(config, version) {
+		return entry, nil
+	}
+	client, err := r.newClient(config, version)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		entry = &registryEntry{owners: sets.New[string]()}
+		r.entries[key] = entry
+	}
+	entry.client = client
+	entry.fingerprint = fingerprint(config)
+	entry.version = version
+	return entry, nil
+}
+
+func (r *Registry) release(owner string, key ClientKey) {
+	entry, ok := r.entries[key]
+	if !ok {
+		return
+	}
+	entry.owners.Delete(owner)
+	if entry.owners.Len() == 0 {
+		delete(r.entries, key)
+	}
+}
+
+// matches returns if the cached client was created from config, an empty
+// version matches any cached version
+func (e *registryEntry) matches(config *AviClientConfig, version string) bool {
+	return e.fingerprint == fingerprint(config) && (version == "" || version == e.version)
+}
+
+func fingerprint(config *AviClientConfig) string {
+	h := sha256.New()
+	for _, s := range []string{config.ServerIP, config.Username, config.Password, config.CA, config.ServerName} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+var _ = Describe("Avi client registry", func() {
+	var (
+		registry *aviclient.Registry
+		created  int
+		config   *aviclient.AviClientConfig
+		keyA     = aviclient.ClientKey{Controller: "10.0.0.1", AdminCredentialRef: "ns/cred", CARef: "ns/ca"}
+		keyB     = aviclient.ClientKey{Controller: "10.0.0.2", AdminCredentialRef: "ns/cred", CARef: "ns/ca"}
+	)
+
+	BeforeEach(func() {
+		created = 0
+		registry = aviclient.NewRegistry(func(*aviclient.AviClientConfig, string) (aviclient.Client, error) {
+			created++
+			return aviclient.NewFakeAviClient(), nil
+		})
+		config = &aviclient.AviClientConfig{ServerIP: "10.0.0.1", Username: "admin", Password: "pw", CA: "ca"}
+	})
+
+	It("should share one session between owners of the same key", func() {
+		c1, err := registry.Acquire("adc-1", keyA, config, "")
+		Expect(err).NotTo(HaveOccurred())
+		c2, err := registry.Acquire("adc-2", keyA, config, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c1).To(BeIdenticalTo(c2))
+		Expect(created).To(Equal(1))
+		Expect(registry.ClientFor("adc-2")).To(BeIdenticalTo(c1))
+	})
+
+	It("should keep sessions of different controllers apart", func() {
+		c1, _ := registry.Acquire("adc-1", keyA, config, "")
+		c2, _ := registry.Acquire("adc-2", keyB, config, "")
+		Expect(c1).NotTo(BeIdenticalTo(c2))
+		Expect(registry.Len()).To(Equal(2))
+
+		// reconciling adc-1 again must not touch the session of adc-2
+		_, _ = registry.Acquire("adc-1", keyA, config, "")
+		Expect(registry.ClientFor("adc-2")).To(BeIdenticalTo(c2))
+		Expect(created).To(Equal(2))
+	})
+
+	It("should rebuild the session when the credentials or the version change", func() {
+		c1, _ := registry.Acquire("adc-1", keyA, config, "")
+		c2, _ := registry.Acquire("adc-1", keyA, config, "22.1.3")
+		Expect(c2).NotTo(BeIdenticalTo(c1))
+		c3, _ := registry.Acquire("adc-1", keyA, config, "")
+		Expect(c3).To(BeIdenticalTo(c2))
+
+		config.Password = "rotated"
+		c4, _ := registry.Acquire("adc-1", keyA, config, "")
+		Expect(c4).NotTo(BeIdenticalTo(c3))
+		Expect(created).To(Equal(3))
+	})
+
+	It("should evict sessions once no owner references them", func() {
+		_, _ = registry.Acquire("adc-1", keyA, config, "")
+		_, _ = registry.Acquire("adc-2", keyA, config, "")
+		registry.Release("adc-1")
+		Expect(registry.Len()).To(Equal(1))
+		registry.Release("adc-2")
+		Expect(registry.Len()).To(Equal(0))
+		Expect(registry.ClientFor("adc-2")).To(BeNil())
+	})
+
+	It("should release the previous key when an owner moves", func() {
+		_, _ = registry.Acquire("adc-1", keyA, config, "")
+		_, _ = registry.Acquire("adc-1", keyB, config, "")
+		Expect(registry.Len()).To(Equal(1))
+	})
+
+	It("should not cache sessions returned by Get", func() {
+		_, err := registry.Get(keyA, config, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(registry.Len()).To(Equal(0))
+
+		c1, _ := registry.Acquire("adc-1", keyA, config, "")
+		c2, _ := registry.Get(keyA, config, "")
+		Expect(c2).To(BeIdenticalTo(c1))
+	})
+
+	It("should not record owners when the session can't be created", func() {
+		registry = aviclient.NewRegistry(func(*aviclient.AviClientConfig, string) (aviclient.Client, error) {
+			return nil, errors.New("unreachable")
+		})
+		_, err := registry.Acquire("adc-1", keyA, config, "")
+		Expect(err).To(HaveOccurred())
+		Expect(registry.ClientFor("adc-1")).To(BeNil())
+		Expect(registry.Len()).To(Equal(0))
+	})
+})
@@ -0,0 +1,381 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RetryOptions configures the retries and the rate limiting applied to the
+// requests sent to an Avi Controller, and the backoff of the reconciles they
+// fail
+type RetryOptions struct {
+	// MaxRetries is the number of times a failed idempotent request is
+	// retried in place. 0 disables retries.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry of a request, it
+	// doubles on every following retry. Keep it short, the reconcile worker
+	// is blocked meanwhile.
+	RetryBackoff time.Duration
+	// InitialBackoff is the delay before a failed reconcile is requeued, it
+	// doubles on every following failure
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay before a failed reconcile is requeued
+	MaxBackoff time.Duration
+	// QPS is the sustained number of requests per second sent to one Avi
+	// Controller. A value <= 0 disables rate limiting.
+	QPS float32
+	// Burst is the number of requests which can exceed QPS
+	Burst int
+}
+
+// DefaultRetryOptions are used unless SetRetryOptions is called
+var DefaultRetryOptions = RetryOptions{
+	MaxRetries:     3,
+	RetryBackoff:   100 * time.Millisecond,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	QPS:            20,
+	Burst:          40,
+}
+
+var (
+	retryOptionsLock sync.Mutex
+	retryOptions     = DefaultRetryOptions
+	// limiters holds one token bucket per Avi Controller, it's shared by
+	// every Client talking to the same controller
+	limiters = map[string]flowcontrol.RateLimiter{}
+)
+
+// SetRetryOptions sets the options of the Clients and rate limiters created
+// afterwards, it's meant to be called once from the manager flags
+func SetRetryOptions(opts RetryOptions) {
+	retryOptionsLock.Lock()
+	defer retryOptionsLock.Unlock()
+	retryOptions = opts
+	limiters = map[string]flowcontrol.RateLimiter{}
+}
+
+func getRetryOptions() RetryOptions {
+	retryOptionsLock.Lock()
+	defer retryOptionsLock.Unlock()
+	return retryOptions
+}
+
+// RequeueRateLimiter returns the rate limiter of the controllers talking to
+// an Avi Controller, configured by SetRetryOptions. Requests still failing
+// after their retries fail the reconcile, which is requeued with an
+// exponential backoff.
+func RequeueRateLimiter() workqueue.RateLimiter {
+	return NewRequeueRateLimiter(getRetryOptions())
+}
+
+// NewRequeueRateLimiter returns a rate limiter requeueing failed reconciles
+// with an exponential backoff from opts.InitialBackoff to opts.MaxBackoff.
+// Like the default one of controller-runtime, an overall token bucket bounds
+// the requeues of all the items.
+func NewRequeueRateLimiter(opts RetryOptions) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(opts.InitialBackoff, opts.MaxBackoff),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+func limiterFor(controller string, opts RetryOptions) flowcontrol.RateLimiter {
+	if opts.QPS <= 0 {
+		return flowcontrol.NewFakeAlwaysRateLimiter()
+	}
+	retryOptionsLock.Lock()
+	defer retryOptionsLock.Unlock()
+	if limiter, ok := limiters[controller]; ok {
+		return limiter
+	}
+	burst := opts.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := flowcontrol.NewTokenBucketRateLimiter(opts.QPS, burst)
+	limiters[controller] = limiter
+	return limiter
+}
+
+// retryingClient decorates a Client with rate limiting and retries. Only
+// reads and full object updates (PUT) are retried, creations and deletions
+// are sent once.
+type retryingClient struct {
+	client  Client
+	opts    RetryOptions
+	limiter flowcontrol.RateLimiter
+}
+
+// NewRetryingClient wraps client so that requests to controller are rate
+// limited and retriable failures of idempotent requests are retried with a
+// bounded exponential backoff
+func NewRetryingClient(client Client, controller string, opts RetryOptions) Client {
+	return &retryingClient{
+		client:  client,
+		opts:    opts,
+		limiter: limiterFor(controller, opts),
+	}
+}
+
+// IsRetriableError returns if err is a transient failure of the Avi
+// Controller: throttling, unavailability, a timeout or a refused or reset
+// connection
+func IsRetriableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code := statusCode(err); code != 0 {
+		return isRetriableStatusCode(code)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// statusCode returns the HTTP status code of the Avi Controller response
+// which caused err, or 0
+func statusCode(err error) int {
+	var aviErr session.AviError
+	if errors.As(err, &aviErr) {
+		return aviErr.HttpStatusCode
+	}
+	var aviErrPtr *session.AviError
+	if errors.As(err, &aviErrPtr) && aviErrPtr != nil {
+		return aviErrPtr.HttpStatusCode
+	}
+	return 0
+}
+
+func isRetriableStatusCode(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// call sends one request, it's retried at most opts.MaxRetries times when
+// idempotent is true and the failure is transient
+func call[T any](r *retryingClient, idempotent bool, fn func() (T, error)) (T, error) {
+	backoff := r.opts.RetryBackoff
+	for retries := 0; ; retries++ {
+		r.limiter.Accept()
+		result, err := fn()
+		if err == nil || !idempotent || retries >= r.opts.MaxRetries || !IsRetriableError(err) {
+			return result, err
+		}
+		time.Sleep(wait.Jitter(backoff, 0.1))
+		backoff *= 2
+	}
+}
+
+func (r *retryingClient) ServiceEngineGroupGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return call(r, true, func() (*models.ServiceEngineGroup, error) {
+		return r.client.ServiceEngineGroupGetByName(name, cloudName, options...)
+	})
+}
+
+func (r *retryingClient) ServiceEngineGroupCreate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return call(r, false, func() (*models.ServiceEngineGroup, error) {
+		return r.client.ServiceEngineGroupCreate(obj, options...)
+	})
+}
+
+func (r *retryingClient) ServiceEngineGroupUpdate(obj *models.ServiceEngineGroup, options ...session.ApiOptionsParams) (*models.ServiceEngineGroup, error) {
+	return call(r, true, func() (*models.ServiceEngineGroup, error) {
+		return r.client.ServiceEngineGroupUpdate(obj, options...)
+	})
+}
+
+func (r *retryingClient) ServiceEngineGroupDelete(uuid string, options ...session.ApiOptionsParams) error {
+	_, err := call(r, false, func() (struct{}, error) {
+		return struct{}{}, r.client.ServiceEngineGroupDelete(uuid, options...)
+	})
+	return err
+}
+
+func (r *retryingClient) NetworkGetByName(name, cloudName string, options ...session.ApiOptionsParams) (*models.Network, error) {
+	return call(r, true, func() (*models.Network, error) {
+		return r.client.NetworkGetByName(name, cloudName, options...)
+	})
+}
+
+func (r *retryingClient) NetworkCreate(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+	return call(r, false, func() (*models.Network, error) {
+		return r.client.NetworkCreate(obj, options...)
+	})
+}
+
+func (r *retryingClient) NetworkUpdate(obj *models.Network, options ...session.ApiOptionsParams) (*models.Network, error) {
+	return call(r, true, func() (*models.Network, error) {
+		return r.client.NetworkUpdate(obj, options...)
+	})
+}
+
+func (r *retryingClient) NetworkRuntimeGet(uuid string, options ...session.ApiOptionsParams) (*models.NetworkRuntime, error) {
+	return call(r, true, func() (*models.NetworkRuntime, error) {
+		return r.client.NetworkRuntimeGet(uuid, options...)
+	})
+}
+
+func (r *retryingClient) CloudGetByName(name string, options ...session.ApiOptionsParams) (*models.Cloud, error) {
+	return call(r, true, func() (*models.Cloud, error) {
+		return r.client.CloudGetByName(name, options...)
+	})
+}
+
+func (r *retryingClient) CloudCreate(obj *models.Cloud, options ...session.ApiOptionsParams) (*models.Cloud, error) {
+	return call(r, false, func() (*models.Cloud, error) {
+		return r.client.CloudCreate(obj, options...)
+	})
+}
+
+func (r *retryingClient) UserGetByName(name string, options ...session.ApiOptionsParams) (*models.User, error) {
+	return call(r, true, func() (*models.User, error) {
+		return r.client.UserGetByName(name, options...)
+	})
+}
+
+func (r *retryingClient) UserList(options ...session.ApiOptionsParams) ([]*models.User, error) {
+	return call(r, true, func() ([]*models.User, error) {
+		return r.client.UserList(options...)
+	})
+}
+
+func (r *retryingClient) UserDeleteByName(name string, options ...session.ApiOptionsParams) error {
+	_, err := call(r, false, func() (struct{}, error) {
+		return struct{}{}, r.client.UserDeleteByName(name, options...)
+	})
+	return err
+}
+
+func (r *retryingClient) UserCreate(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error) {
+	return call(r, false, func() (*models.User, error) {
+		return r.client.UserCreate(obj, options...)
+	})
+}
+
+func (r *retryingClient) UserUpdate(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error) {
+	return call(r, true, func() (*models.User, error) {
+		return r.client.UserUpdate(obj, options...)
+	})
+}
+
+func (r *retryingClient) TenantGet(uuid string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return call(r, true, func() (*models.Tenant, error) {
+		return r.client.TenantGet(uuid, options...)
+	})
+}
+
+func (r *retryingClient) TenantGetByName(name string, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return call(r, true, func() (*models.Tenant, error) {
+		return r.client.TenantGetByName(name, options...)
+	})
+}
+
+func (r *retryingClient) TenantCreate(obj *models.Tenant, options ...session.ApiOptionsParams) (*models.Tenant, error) {
+	return call(r, false, func() (*models.Tenant, error) {
+		return r.client.TenantCreate(obj, options...)
+	})
+}
+
+func (r *retryingClient) TenantDelete(uuid string, options ...session.ApiOptionsParams) error {
+	_, err := call(r, false, func() (struct{}, error) {
+		return struct{}{}, r.client.TenantDelete(uuid, options...)
+	})
+	return err
+}
+
+func (r *retryingClient) TenantObjectCount(name string, options ...session.ApiOptionsParams) (int, error) {
+	return call(r, true, func() (int, error) {
+		return r.client.TenantObjectCount(name, options...)
+	})
+}
+
+func (r *retryingClient) RoleGet(uuid string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return call(r, true, func() (*models.Role, error) {
+		return r.client.RoleGet(uuid, options...)
+	})
+}
+
+func (r *retryingClient) RoleGetByName(name string, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return call(r, true, func() (*models.Role, error) {
+		return r.client.RoleGetByName(name, options...)
+	})
+}
+
+func (r *retryingClient) RoleCreate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return call(r, false, func() (*models.Role, error) {
+		return r.client.RoleCreate(obj, options...)
+	})
+}
+
+func (r *retryingClient) RoleUpdate(obj *models.Role, options ...session.ApiOptionsParams) (*models.Role, error) {
+	return call(r, true, func() (*models.Role, error) {
+		return r.client.RoleUpdate(obj, options...)
+	})
+}
+
+func (r *retryingClient) RoleDelete(uuid string, options ...session.ApiOptionsParams) error {
+	_, err := call(r, false, func() (struct{}, error) {
+		return struct{}{}, r.client.RoleDelete(uuid, options...)
+	})
+	return err
+}
+
+func (r *retryingClient) IPAMDNSProviderProfileGet(uuid string, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error) {
+	return call(r, true, func() (*models.IPAMDNSProviderProfile, error) {
+		return r.client.IPAMDNSProviderProfileGet(uuid, options...)
+	})
+}
+
+func (r *retryingClient) IPAMDNSProviderProfileUpdate(obj *models.IPAMDNSProviderProfile, options ...session.ApiOptionsParams) (*models.IPAMDNSProviderProfile, error) {
+	return call(r, true, func() (*models.IPAMDNSProviderProfile, error) {
+		return r.client.IPAMDNSProviderProfileUpdate(obj, options...)
+	})
+}
+
+func (r *retryingClient) VirtualServiceGetByName(name string, options ...session.ApiOptionsParams) (*models.VirtualService, error) {
+	return call(r, true, func() (*models.VirtualService, error) {
+		return r.client.VirtualServiceGetByName(name, options...)
+	})
+}
+
+func (r *retryingClient) PoolGetByName(name string, options ...session.ApiOptionsParams) (*models.Pool, error) {
+	return call(r, true, func() (*models.Pool, error) {
+		return r.client.PoolGetByName(name, options...)
+	})
+}
+
+func (r *retryingClient) AviCertificateConfig() (string, error) {
+	return r.client.AviCertificateConfig()
+}
+
+func (r *retryingClient) SystemConfigurationGet(options ...session.ApiOptionsParams) (*models.SystemConfiguration, error) {
+	return call(r, true, func() (*models.SystemConfiguration, error) {
+		return r.client.SystemConfigurationGet(options...)
+	})
+}
+
+func (r *retryingClient) GetControllerVersion() (string, error) {
+	return call(r, true, r.client.GetControllerVersion)
+}
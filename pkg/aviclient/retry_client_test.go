@@ -0,0 +1,132 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package aviclient_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	"github.com/vmware/alb-sdk/go/session"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+var _ = Describe("Retrying Avi client", func() {
+	var (
+		fake  *aviclient.FakeAviClient
+		calls int
+		opts  = aviclient.RetryOptions{
+			MaxRetries:     2,
+			RetryBackoff:   time.Millisecond,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     40 * time.Millisecond,
+		}
+		failing = func(times int, err error) {
+			fake.Network.SetGetByNameFn(func(name string, options ...session.ApiOptionsParams) (*models.Network, error) {
+				calls++
+				if calls <= times {
+					return nil, err
+				}
+				return &models.Network{Name: &name}, nil
+			})
+		}
+	)
+
+	BeforeEach(func() {
+		calls = 0
+		fake = aviclient.NewFakeAviClient()
+	})
+
+	It("should retry idempotent requests on retriable errors", func() {
+		failing(2, session.AviError{HttpStatusCode: http.StatusServiceUnavailable})
+		c := aviclient.NewRetryingClient(fake, "10.0.0.1", opts)
+		network, err := c.NetworkGetByName("net", "cloud")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*network.Name).To(Equal("net"))
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should give up after MaxRetries", func() {
+		failing(10, session.AviError{HttpStatusCode: http.StatusTooManyRequests})
+		c := aviclient.NewRetryingClient(fake, "10.0.0.1", opts)
+		_, err := c.NetworkGetByName("net", "cloud")
+		Expect(err).To(HaveOccurred())
+		Expect(aviclient.IsRetriableError(err)).To(BeTrue())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should not retry non retriable errors", func() {
+		failing(1, session.AviError{HttpStatusCode: http.StatusBadRequest})
+		c := aviclient.NewRetryingClient(fake, "10.0.0.1", opts)
+		_, err := c.NetworkGetByName("net", "cloud")
+		Expect(err).To(HaveOccurred())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should not retry creations", func() {
+		fake.User.SetCreateUserFunc(func(obj *models.User, options ...session.ApiOptionsParams) (*models.User, error) {
+			calls++
+			return nil, session.AviError{HttpStatusCode: http.StatusServiceUnavailable}
+		})
+		c := aviclient.NewRetryingClient(fake, "10.0.0.1", opts)
+		_, err := c.UserCreate(&models.User{})
+		Expect(err).To(HaveOccurred())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should requeue failed reconciles with an exponential backoff", func() {
+		limiter := aviclient.NewRequeueRateLimiter(opts)
+		Expect(limiter.When("adc")).To(Equal(10 * time.Millisecond))
+		Expect(limiter.When("adc")).To(Equal(20 * time.Millisecond))
+		Expect(limiter.When("adc")).To(Equal(40 * time.Millisecond))
+		Expect(limiter.When("adc")).To(Equal(40 * time.Millisecond))
+		Expect(limiter.When("other-adc")).To(Equal(10 * time.Millisecond))
+		limiter.Forget("adc")
+		Expect(limiter.When("adc")).To(Equal(10 * time.Millisecond))
+	})
+
+	It("should bound the requeues of all the items", func() {
+		limiter := aviclient.NewRequeueRateLimiter(opts)
+		for i := 0; i < 100; i++ {
+			limiter.When(fmt.Sprintf("adc-%d", i))
+		}
+		Expect(limiter.When("adc")).To(BeNumerically(">", opts.InitialBackoff))
+	})
+
+	It("should rate limit requests per controller", func() {
+		failing(0, nil)
+		limited := opts
+		limited.QPS = 50
+		limited.Burst = 1
+		// a controller no other test talks to gets a bucket of its own
+		c1 := aviclient.NewRetryingClient(fake, "10.0.0.3", limited)
+		c2 := aviclient.NewRetryingClient(fake, "10.0.0.3", limited)
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, _ = c1.NetworkGetByName("net", "cloud")
+			_, _ = c2.NetworkGetByName("net", "cloud")
+		}
+		// 6 requests with a burst of 1 at 50 qps share one bucket
+		Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+	})
+
+	It("should classify errors", func() {
+		Expect(aviclient.IsRetriableError(nil)).To(BeFalse())
+		Expect(aviclient.IsRetriableError(errors.New("boom"))).To(BeFalse())
+		Expect(aviclient.IsRetriableError(&session.AviError{HttpStatusCode: http.StatusBadGateway})).To(BeTrue())
+		Expect(aviclient.IsRetriableError(session.AviError{HttpStatusCode: http.StatusBadRequest})).To(BeFalse())
+		Expect(aviclient.IsRetriableError(&url.Error{Op: "Get", URL: "https://10.0.0.1", Err: os.ErrDeadlineExceeded})).To(BeTrue())
+		Expect(aviclient.IsRetriableError(&url.Error{Op: "Get", URL: "https://10.0.0.1", Err: syscall.ECONNRESET})).To(BeTrue())
+		Expect(aviclient.IsRetriableError(&url.Error{Op: "Get", URL: "https://10.0.0.1", Err: syscall.ECONNREFUSED})).To(BeTrue())
+		Expect(aviclient.IsRetriableError(&url.Error{Op: "Get", URL: "https://10.0.0.1", Err: errors.New("x509: certificate signed by unknown authority")})).To(BeFalse())
+	})
+})
@@ -6,7 +6,6 @@ package haprovider
 import (
 	"context"
 	"net"
-	"sync"
 
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/utils"
 
@@ -16,14 +15,17 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	ako_operator "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/ako-operator"
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 )
 
@@ -35,24 +37,20 @@ const (
 
 type HAProvider struct {
 	client.Client
-	log logr.Logger
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
-var (
-	instance  *HAProvider
-	once      sync.Once
-	QueryFQDN = queryFQDNEndpoint
-)
+var QueryFQDN = queryFQDNEndpoint
 
-// NewProvider make HAProvider as a singleton
-func NewProvider(c client.Client, log logr.Logger) *HAProvider {
-	once.Do(func() {
-		instance = &HAProvider{
-			Client: c,
-			log:    log,
-		}
-	})
-	return instance
+// NewProvider returns an HAProvider emitting its events with recorder, each
+// controller builds its own so that the events are reported by it
+func NewProvider(c client.Client, log logr.Logger, recorder record.EventRecorder) *HAProvider {
+	return &HAProvider{
+		Client:   c,
+		log:      log,
+		recorder: recorder,
+	}
 }
 
 func (r *HAProvider) getHAServiceName(cluster *clusterv1.Cluster) string {
@@ -70,21 +68,36 @@ func (r *HAProvider) CreateOrUpdateHAService(ctx context.Context, cluster *clust
 			r.log.Info(serviceName + " service doesn't exist, start creating it...")
 			service, err = r.createService(ctx, cluster)
 			if err != nil {
+				r.recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.HAServiceFailedReason,
+					"Failed to create control plane load balancer service %s: %v", serviceName, err)
 				return err
 			}
+			r.recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.HAServiceCreatedReason,
+				"Created control plane load balancer service %s", serviceName)
 		} else {
 			return err
 		}
 	}
+	previousHost := cluster.Spec.ControlPlaneEndpoint.Host
 	if err := r.updateClusterControlPlaneEndpoint(cluster, service); err != nil {
+		metrics.HAServiceVIPReady.WithLabelValues(cluster.Namespace, cluster.Name).Set(0)
+		r.recorder.Event(cluster, corev1.EventTypeWarning, akoov1alpha1.HAServiceVIPNotReadyReason, err.Error())
 		return err
 	}
+	metrics.HAServiceVIPReady.WithLabelValues(cluster.Namespace, cluster.Name).Set(1)
+	if host := cluster.Spec.ControlPlaneEndpoint.Host; host != previousHost {
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, akoov1alpha1.ControlPlaneEndpointUpdatedReason,
+			"Control plane endpoint set to %s", host)
+	}
 
 	if err := r.updateControlPlaneEndpointToService(ctx, cluster, service); err != nil {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, akoov1alpha1.HAServiceFailedReason, err.Error())
 		return err
 	}
 
 	if _, err := r.ensureEndpoints(ctx, serviceName, service.Namespace); err != nil {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, akoov1alpha1.HAServiceFailedReason,
+			"Failed to ensure the Endpoints of service %s: %v", serviceName, err)
 		return err
 	}
 	return nil
@@ -417,17 +430,25 @@ func (r *HAProvider) CreateOrUpdateHAEndpoints(ctx context.Context, machine *clu
 	if adcForCluster != nil && adcForCluster.Spec.ExtraConfigs.IpFamily != "" {
 		ipFamily = adcForCluster.Spec.ExtraConfigs.IpFamily
 	}
+	previousSubsets := endpoints.DeepCopy().Subsets
+	reason, message := akoov1alpha1.HAEndpointAddedReason, "Added machine address to the Endpoints of service %s"
 	if !machine.DeletionTimestamp.IsZero() {
 		r.log.Info("machine" + machine.Name + " is being deleted, remove the endpoint of the machine from " + r.getHAServiceName(cluster) + " Endpoints")
 		r.removeMachineIpFromEndpoints(endpoints, machine)
+		reason, message = akoov1alpha1.HAEndpointRemovedReason, "Removed machine address from the Endpoints of service %s"
 	} else {
 		// Add machine ip to the Endpoints object no matter it's ready or not
 		// Because avi controller checks the status of machine. If it's not ready, avi won't use it as an endpoint
 		r.addMachineIpToEndpoints(endpoints, machine, ipFamily)
 	}
 	if err := r.Update(ctx, endpoints); err != nil {
+		r.recorder.Eventf(machine, corev1.EventTypeWarning, akoov1alpha1.HAEndpointUpdateFailedReason,
+			"Failed to update the Endpoints of service %s: %v", endpoints.Name, err)
 		return errors.Wrapf(err, "Failed to update endpoints <%s>, control plane machine IP doesn't get allocated yet\n", endpoints.Name)
 	}
+	if !equality.Semantic.DeepEqual(previousSubsets, endpoints.Subsets) {
+		r.recorder.Eventf(machine, corev1.EventTypeNormal, reason, message, endpoints.Name)
+	}
 	return nil
 }
 
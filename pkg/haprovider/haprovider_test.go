@@ -17,6 +17,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -29,6 +30,7 @@ var _ = Describe("Control Plane HA provider", func() {
 	var (
 		ctx        context.Context
 		haProvider HAProvider
+		recorder   *record.FakeRecorder
 		err        error
 	)
 	BeforeEach(func() {
@@ -40,7 +42,8 @@ var _ = Describe("Control Plane HA provider", func() {
 		log.SetLogger(zap.New())
 		fc := fakeClient.NewClientBuilder().WithScheme(scheme).Build()
 		logger := log.Log
-		haProvider = *NewProvider(fc, logger)
+		recorder = record.NewFakeRecorder(100)
+		haProvider = *NewProvider(fc, logger, recorder)
 	})
 
 	Context("Test_CreateOrUpdateHAService", func() {
@@ -71,6 +74,8 @@ var _ = Describe("Control Plane HA provider", func() {
 		It("load balancer type of service should be created and no ip provisioned", func() {
 			Expect(haProvider.Client.Get(ctx, key, svc)).ShouldNot(HaveOccurred())
 			Expect(err.Error()).Should(Equal("default-test-cluster-control-plane service external ip is not ready"))
+			Expect(recorder.Events).Should(Receive(HavePrefix("Normal " + akoov1alpha1.HAServiceCreatedReason)))
+			Expect(recorder.Events).Should(Receive(HavePrefix("Warning " + akoov1alpha1.HAServiceVIPNotReadyReason)))
 		})
 
 		When("service has external IP", func() {
@@ -0,0 +1,143 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics defines the Prometheus collectors of the operator. They
+// are registered on the controller-runtime registry and served on the
+// manager's metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "ako_operator"
+
+// vipPoolLabels identify a static IP pool, the pool is labeled start-end
+var vipPoolLabels = []string{"akodeploymentconfig", "network", "pool"}
+
+var (
+	// ReconcilePhaseDuration observes the duration of each reconcile phase
+	ReconcilePhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_phase_duration_seconds",
+		Help:      "Duration of the AKODeploymentConfig reconcile phases",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"phase"})
+
+	// ReconcilePhaseErrors counts the errors returned by each reconcile phase
+	ReconcilePhaseErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_phase_errors_total",
+		Help:      "Number of errors returned by the AKODeploymentConfig reconcile phases",
+	}, []string{"phase"})
+
+	// AviAPIRequestDuration observes the latency of the Avi Controller API
+	// requests by client method and status, the status is the HTTP status
+	// code of failed requests, "success" or "error" when there's no response
+	AviAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "avi_api_request_duration_seconds",
+		Help:      "Latency of the Avi Controller API requests",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"method", "status"})
+
+	// SelectedClusters is the number of clusters selected by each
+	// AKODeploymentConfig
+	SelectedClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "akodeploymentconfig_selected_clusters",
+		Help:      "Number of clusters selected by the AKODeploymentConfig",
+	}, []string{"akodeploymentconfig"})
+
+	// HAServiceVIPReady is 1 when the control plane HA service of a cluster
+	// has been assigned a VIP, 0 otherwise
+	HAServiceVIPReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ha_service_vip_ready",
+		Help:      "Whether the control plane HA service of the cluster has a VIP",
+	}, []string{"namespace", "cluster"})
+
+	// VIPPoolTotalIPs is the number of IPs of each static IP pool of the
+	// data and VIP networks of an AKODeploymentConfig
+	VIPPoolTotalIPs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "vip_pool_total_ips",
+		Help:      "Number of IPs of the static IP pool",
+	}, vipPoolLabels)
+
+	// VIPPoolUsedIPs is the number of IPs of each static IP pool allocated
+	// by the Avi Controller
+	VIPPoolUsedIPs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "vip_pool_used_ips",
+		Help:      "Number of IPs of the static IP pool allocated by the Avi Controller",
+	}, vipPoolLabels)
+
+	// VIPPoolFreeIPs is the number of IPs of each static IP pool left to
+	// allocate
+	VIPPoolFreeIPs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "vip_pool_free_ips",
+		Help:      "Number of IPs of the static IP pool left to allocate",
+	}, vipPoolLabels)
+
+	// CleanupDuration observes the time AKO takes to remove the Avi
+	// resources of a deleted cluster
+	CleanupDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "cluster_cleanup_duration_seconds",
+		Help:      "Duration of the Avi resources cleanup of deleted clusters",
+		Buckets:   prometheus.ExponentialBuckets(10, 2, 10),
+	})
+
+	// OrphanedResources is the number of Avi users and avi-credentials
+	// Secrets of deleted clusters found by the last orphan sweep of each
+	// AKODeploymentConfig, kind is user or secret
+	OrphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphaned_resources",
+		Help:      "Number of Avi users and Secrets left behind by deleted clusters",
+	}, []string{"akodeploymentconfig", "kind"})
+
+	// OrphanedResourcesDeleted counts the orphaned Avi users and
+	// avi-credentials Secrets deleted by the orphan sweeps
+	OrphanedResourcesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orphaned_resources_deleted_total",
+		Help:      "Number of orphaned Avi users and Secrets deleted",
+	}, []string{"kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcilePhaseDuration,
+		ReconcilePhaseErrors,
+		AviAPIRequestDuration,
+		SelectedClusters,
+		HAServiceVIPReady,
+		VIPPoolTotalIPs,
+		VIPPoolUsedIPs,
+		VIPPoolFreeIPs,
+		CleanupDuration,
+		OrphanedResources,
+		OrphanedResourcesDeleted,
+	)
+}
+
+// SetVIPPool publishes the utilization of a static IP pool of an
+// AKODeploymentConfig
+func SetVIPPool(adc, network, start, end string, total, used, free int64) {
+	pool := start + "-" + end
+	VIPPoolTotalIPs.WithLabelValues(adc, network, pool).Set(float64(total))
+	VIPPoolUsedIPs.WithLabelValues(adc, network, pool).Set(float64(used))
+	VIPPoolFreeIPs.WithLabelValues(adc, network, pool).Set(float64(free))
+}
+
+// DeleteVIPPools removes the static IP pool series of an AKODeploymentConfig
+func DeleteVIPPools(adc string) {
+	labels := prometheus.Labels{"akodeploymentconfig": adc}
+	VIPPoolTotalIPs.DeletePartialMatch(labels)
+	VIPPoolUsedIPs.DeletePartialMatch(labels)
+	VIPPoolFreeIPs.DeletePartialMatch(labels)
+}
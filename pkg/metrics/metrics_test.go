@@ -0,0 +1,26 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/metrics"
+)
+
+var _ = Describe("VIP pools", func() {
+	It("should publish and delete the series of an AKODeploymentConfig", func() {
+		metrics.SetVIPPool("adc-1", "vip", "10.0.0.10", "10.0.0.19", 10, 3, 7)
+		metrics.SetVIPPool("adc-2", "vip", "10.0.0.20", "10.0.0.29", 10, 0, 10)
+		Expect(testutil.ToFloat64(metrics.VIPPoolFreeIPs.WithLabelValues("adc-1", "vip", "10.0.0.10-10.0.0.19"))).To(Equal(float64(7)))
+
+		metrics.DeleteVIPPools("adc-1")
+		Expect(testutil.CollectAndCount(metrics.VIPPoolTotalIPs)).To(Equal(1))
+		Expect(testutil.CollectAndCount(metrics.VIPPoolUsedIPs)).To(Equal(1))
+		Expect(testutil.CollectAndCount(metrics.VIPPoolFreeIPs)).To(Equal(1))
+		metrics.DeleteVIPPools("adc-2")
+	})
+})
@@ -21,26 +21,12 @@ type UsableNetwork struct {
 
 type UsableNetworkProvider struct{}
 
-func (c *UsableNetworkProvider) AddUsableNetwork(client aviclient.Client, cloudName, networkName string, log logr.Logger) error {
-	cloud, err := client.CloudGetByName(cloudName)
-	if err != nil {
-		// Cannot find the configured cloud, requeue the request but
-		// leave enough time for operators to resolve this issue
-		return errors.Wrapf(err, "Failed to find cloud %s, requeue the request\n", cloudName)
-	}
-	if cloud.IPAMProviderRef == nil {
-		// Cannot find any configured IPAM Provider, requeue the request but
-		// leave enough time for operators to resolve this issue
-		return errors.Wrap(err, "No IPAM Provider is registered for the cloud, requeue the request")
-	}
-	ipamProviderUUID := aviclient.GetUUIDFromRef(*(cloud.IPAMProviderRef))
-	ipam, err := client.IPAMDNSProviderProfileGet(ipamProviderUUID)
+// AddUsableNetwork ensures the network is one of the usable networks of the
+// cloud's IPAM profile. It returns true when it added the network.
+func (c *UsableNetworkProvider) AddUsableNetwork(client aviclient.Client, cloudName, networkName string, log logr.Logger) (bool, error) {
+	ipam, network, err := getIPAMAndNetwork(client, cloudName, networkName)
 	if err != nil {
-		return errors.Wrap(err, "Failed to find IPAM profile")
-	}
-	network, err := client.NetworkGetByName(networkName, cloudName)
-	if err != nil {
-		return errors.Wrapf(err, "Failed to get Data Network %s from AVI Controller\n", networkName)
+		return false, err
 	}
 	// Ensure network is added to the cloud's IPAM Profile as one of its
 	// usable Networks
@@ -49,15 +35,65 @@ func (c *UsableNetworkProvider) AddUsableNetwork(client aviclient.Client, cloudN
 	for _, usableNetwork := range ipam.InternalProfile.UsableNetworks {
 		if strings.Contains(*(network.URL), *(usableNetwork.NwRef)) {
 			log.Info("Network is already one of the cloud's usable network", "network", networkName)
-			return nil
+			return false, nil
 		}
 	}
 	ipam.InternalProfile.UsableNetworks = append(ipam.InternalProfile.UsableNetworks, &models.IPAMUsableNetwork{NwRef: network.URL})
 	_, err = client.IPAMDNSProviderProfileUpdate(ipam)
 	if err != nil {
-		return errors.Wrapf(err, "Failed to add usable network %s\n", *network.Name)
+		return false, errors.Wrapf(err, "Failed to add usable network %s\n", *network.Name)
 	}
 
 	log.Info("Added Usable Network", "network", networkName)
+	return true, nil
+}
+
+// RemoveUsableNetwork ensures the network is not one of the usable networks
+// of the cloud's IPAM profile
+func (c *UsableNetworkProvider) RemoveUsableNetwork(client aviclient.Client, cloudName, networkName string, log logr.Logger) error {
+	ipam, network, err := getIPAMAndNetwork(client, cloudName, networkName)
+	if err != nil {
+		return err
+	}
+	var usableNetworks []*models.IPAMUsableNetwork
+	for _, usableNetwork := range ipam.InternalProfile.UsableNetworks {
+		if !strings.Contains(*(network.URL), *(usableNetwork.NwRef)) {
+			usableNetworks = append(usableNetworks, usableNetwork)
+		}
+	}
+	if len(usableNetworks) == len(ipam.InternalProfile.UsableNetworks) {
+		log.Info("Network is not one of the cloud's usable network", "network", networkName)
+		return nil
+	}
+	ipam.InternalProfile.UsableNetworks = usableNetworks
+	if _, err := client.IPAMDNSProviderProfileUpdate(ipam); err != nil {
+		return errors.Wrapf(err, "Failed to remove usable network %s\n", *network.Name)
+	}
+
+	log.Info("Removed Usable Network", "network", networkName)
 	return nil
 }
+
+func getIPAMAndNetwork(client aviclient.Client, cloudName, networkName string) (*models.IPAMDNSProviderProfile, *models.Network, error) {
+	cloud, err := client.CloudGetByName(cloudName)
+	if err != nil {
+		// Cannot find the configured cloud, requeue the request but
+		// leave enough time for operators to resolve this issue
+		return nil, nil, errors.Wrapf(err, "Failed to find cloud %s, requeue the request\n", cloudName)
+	}
+	if cloud.IPAMProviderRef == nil {
+		// Cannot find any configured IPAM Provider, requeue the request but
+		// leave enough time for operators to resolve this issue
+		return nil, nil, errors.Errorf("No IPAM Provider is registered for the cloud %s, requeue the request", cloudName)
+	}
+	ipamProviderUUID := aviclient.GetUUIDFromRef(*(cloud.IPAMProviderRef))
+	ipam, err := client.IPAMDNSProviderProfileGet(ipamProviderUUID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to find IPAM profile")
+	}
+	network, err := client.NetworkGetByName(networkName, cloudName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to get Data Network %s from AVI Controller\n", networkName)
+	}
+	return ipam, network, nil
+}
@@ -0,0 +1,577 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package aviserver provides an in-memory Avi Controller served over HTTPS,
+// so that tests can exercise the real aviclient end to end.
+package aviserver
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/pkg/aviclient"
+)
+
+const (
+	// DefaultUsername and DefaultPassword are the credentials accepted by a
+	// new Server
+	DefaultUsername = "admin"
+	DefaultPassword = "Admin!23"
+	// DefaultVersion is the version reported by a new Server
+	DefaultVersion = "22.1.3"
+
+	csrfToken = "fake-csrf-token"
+)
+
+// Kinds lists the Avi objects served by the Server
+var Kinds = []string{
+	"cloud",
+	"network",
+	"ipamdnsproviderprofile",
+	"serviceenginegroup",
+	"user",
+	"role",
+	"tenant",
+	"virtualservice",
+	"vsvip",
+	"pool",
+	"poolgroup",
+	"httppolicyset",
+	"sslkeyandcertificate",
+}
+
+// Server is a stateful stand-in for an Avi Controller. It serves login,
+// the controller version, the system configuration and the CRUD REST API of
+// Kinds, objects are kept in memory as plain JSON.
+type Server struct {
+	*httptest.Server
+
+	// Username, Password and Version can be changed before the first
+	// request
+	Username string
+	Password string
+	Version  string
+	// SystemConfiguration is served as is at /api/systemconfiguration
+	SystemConfiguration map[string]interface{}
+
+	mu       sync.Mutex
+	seq      int
+	sessions map[string]bool
+	objects  map[string][]map[string]interface{}
+}
+
+// NewServer starts a Server with the default credentials and version. The
+// "admin" tenant exists from the start, like on a real controller.
+func NewServer() *Server {
+	s := &Server{
+		Username: DefaultUsername,
+		Password: DefaultPassword,
+		Version:  DefaultVersion,
+		SystemConfiguration: map[string]interface{}{
+			"portal_configuration": map[string]interface{}{
+				"password_strength_check": true,
+				"minimum_password_length": 8,
+			},
+		},
+		sessions: map[string]bool{},
+		objects:  map[string][]map[string]interface{}{},
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	s.objects["tenant"] = []map[string]interface{}{s.adminTenant()}
+	return s
+}
+
+// ClientConfig returns the configuration of an aviclient connecting to the
+// Server with its credentials, trusting its certificate
+func (s *Server) ClientConfig() *aviclient.AviClientConfig {
+	return &aviclient.AviClientConfig{
+		ServerIP: s.Listener.Addr().String(),
+		Username: s.Username,
+		Password: s.Password,
+		CA:       s.CA(),
+	}
+}
+
+// CA returns the PEM encoded certificate of the Server
+func (s *Server) CA() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+}
+
+// Create stores obj, usually one of the alb-sdk models, as an object of the
+// given kind and returns its URL. It's meant to seed the Server.
+func (s *Server) Create(kind string, obj interface{}) (string, error) {
+	m, err := toMap(obj)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created, code, err := s.create(kind, m)
+	if err != nil {
+		return "", fmt.Errorf("%d: %w", code, err)
+	}
+	return created["url"].(string), nil
+}
+
+// GetByName decodes the object of the given kind and name into out, it
+// returns false when there is no such object
+func (s *Server) GetByName(kind, name string, out interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, obj := range s.objects[kind] {
+		if obj["name"] == name {
+			return true, fromMap(obj, out)
+		}
+	}
+	return false, nil
+}
+
+// Len returns the number of objects of the given kind
+func (s *Server) Len(kind string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.objects[kind])
+}
+
+// Delete removes the objects of the given kind and name, from every cloud
+func (s *Server) Delete(kind, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var kept []map[string]interface{}
+	for _, obj := range s.objects[kind] {
+		if obj["name"] != name {
+			kept = append(kept, obj)
+		}
+	}
+	s.objects[kind] = kept
+}
+
+// Reset removes every object but the "admin" tenant, so that a suite can
+// share one Server and seed it again between its tests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects = map[string][]map[string]interface{}{
+		"tenant": {s.adminTenant()},
+	}
+}
+
+func (s *Server) adminTenant() map[string]interface{} {
+	return map[string]interface{}{
+		"uuid": "admin",
+		"name": "admin",
+		"url":  s.ref("tenant", "admin"),
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	// the SDK joins its prefix and the URIs without caring about slashes,
+	// e.g. https://host//api/initial-data or /api/network/?name=
+	var parts []string
+	for _, p := range strings.Split(req.URL.Path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	if len(parts) == 1 && parts[0] == "login" && req.Method == http.MethodPost {
+		s.login(w, req)
+		return
+	}
+	if len(parts) == 0 || parts[0] != "api" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if !s.authenticated(req) {
+		writeError(w, http.StatusUnauthorized, "Authentication credentials were not provided.")
+		return
+	}
+	if req.Method != http.MethodGet && req.Header.Get("X-CSRFToken") != csrfToken {
+		writeError(w, http.StatusForbidden, "CSRF Failed: CSRF token missing or incorrect.")
+		return
+	}
+
+	parts = parts[1:]
+	switch {
+	case len(parts) == 1 && parts[0] == "initial-data" && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"version": map[string]interface{}{"Version": s.Version},
+		})
+	case len(parts) == 2 && parts[0] == "cluster" && parts[1] == "status":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"cluster_state": map[string]interface{}{"state": "CLUSTER_UP_NO_HA"},
+		})
+	case len(parts) == 1 && parts[0] == "systemconfiguration" && req.Method == http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.SystemConfiguration)
+	case len(parts) == 1 && isKind(parts[0]):
+		s.serveCollection(w, req, parts[0])
+	case len(parts) == 2 && isKind(parts[0]):
+		s.serveObject(w, req, parts[0], parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, req *http.Request) {
+	cred := map[string]string{}
+	if err := json.NewDecoder(req.Body).Decode(&cred); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cred["username"] != s.Username || cred["password"] != s.Password {
+		writeError(w, http.StatusUnauthorized, "Invalid credentials")
+		return
+	}
+	s.seq++
+	sessionID := fmt.Sprintf("session-%d", s.seq)
+	s.sessions[sessionID] = true
+	http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: csrfToken})
+	http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: sessionID})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user": map[string]interface{}{"username": cred["username"]},
+	})
+}
+
+func (s *Server) authenticated(req *http.Request) bool {
+	cookie, err := req.Cookie("sessionid")
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[cookie.Value]
+}
+
+func (s *Server) serveCollection(w http.ResponseWriter, req *http.Request, kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Method {
+	case http.MethodGet:
+		query := req.URL.Query()
+		_, includeName := query["include_name"]
+		tenant := req.Header.Get("X-Avi-Tenant")
+		results := []map[string]interface{}{}
+		for _, obj := range s.objects[kind] {
+			if s.matches(obj, query) && s.inTenant(kind, obj, tenant) {
+				results = append(results, s.render(obj, includeName))
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"count":   len(results),
+			"results": results,
+		})
+	case http.MethodPost:
+		obj := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&obj); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		created, code, err := s.create(kind, obj)
+		if err != nil {
+			writeError(w, code, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, s.render(created, false))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) serveObject(w http.ResponseWriter, req *http.Request, kind, uuid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(kind, uuid)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Object not found!")
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		_, includeName := req.URL.Query()["include_name"]
+		writeJSON(w, http.StatusOK, s.render(s.objects[kind][i], includeName))
+	case http.MethodPut:
+		obj := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&obj); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		name, _ := obj["name"].(string)
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "name: This field is required.")
+			return
+		}
+		if j := s.indexOfName(kind, name); j >= 0 && j != i {
+			writeError(w, http.StatusConflict, alreadyExists(kind))
+			return
+		}
+		stripRefNames(obj)
+		obj["uuid"] = uuid
+		obj["url"] = s.ref(kind, uuid)
+		s.objects[kind][i] = obj
+		writeJSON(w, http.StatusOK, s.render(obj, false))
+	case http.MethodDelete:
+		if referrers := s.referrers(uuid); len(referrers) != 0 {
+			writeError(w, http.StatusPreconditionFailed, fmt.Sprintf("Cannot delete, object is referred by: %s", referrers))
+			return
+		}
+		s.objects[kind] = append(s.objects[kind][:i], s.objects[kind][i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// create stores obj under a new uuid, names are unique per kind, cloud and
+// tenant
+func (s *Server) create(kind string, obj map[string]interface{}) (map[string]interface{}, int, error) {
+	if !isKind(kind) {
+		return nil, http.StatusNotFound, fmt.Errorf("unknown kind %s", kind)
+	}
+	name, _ := obj["name"].(string)
+	if name == "" {
+		return nil, http.StatusBadRequest, errors.New("name: This field is required.")
+	}
+	stripRefNames(obj)
+	if _, ok := obj["tenant_ref"]; !ok && kind != "tenant" && kind != "user" {
+		obj["tenant_ref"] = s.ref("tenant", "admin")
+	}
+	for _, existing := range s.objects[kind] {
+		if existing["name"] == name && existing["cloud_ref"] == obj["cloud_ref"] && existing["tenant_ref"] == obj["tenant_ref"] {
+			return nil, http.StatusConflict, errors.New(alreadyExists(kind))
+		}
+	}
+	s.seq++
+	uuid := fmt.Sprintf("%s-%d", kind, s.seq)
+	obj["uuid"] = uuid
+	obj["url"] = s.ref(kind, uuid)
+	s.objects[kind] = append(s.objects[kind], obj)
+	return obj, http.StatusCreated, nil
+}
+
+// sharedKinds are the kinds whose objects of the admin tenant are visible in
+// every tenant, like the system roles and certificates of a real controller
+var sharedKinds = map[string]bool{
+	"role":                 true,
+	"sslkeyandcertificate": true,
+}
+
+// inTenant implements the X-Avi-Tenant header of the collections, objects
+// without a tenant_ref, i.e. tenants and users, are visible in every tenant
+func (s *Server) inTenant(kind string, obj map[string]interface{}, tenant string) bool {
+	tenantRef, ok := obj["tenant_ref"].(string)
+	if !ok || tenant == "" {
+		return true
+	}
+	name := s.refName(tenantRef)
+	return name == tenant || (sharedKinds[kind] && name == "admin")
+}
+
+// matches implements the collection filters used by the SDK and the
+// aviclient: name, cloud, cloud_ref.name, cloud_ref.uuid and tenant_ref.uuid
+func (s *Server) matches(obj map[string]interface{}, query url.Values) bool {
+	if name, ok := query["name"]; ok && obj["name"] != name[0] {
+		return false
+	}
+	tenantRef, _ := obj["tenant_ref"].(string)
+	if uuid := query.Get("tenant_ref.uuid"); uuid != "" && refUUID(tenantRef) != uuid {
+		return false
+	}
+	cloudRef, _ := obj["cloud_ref"].(string)
+	if uuid := query.Get("cloud_ref.uuid"); uuid != "" && refUUID(cloudRef) != uuid {
+		return false
+	}
+	for _, key := range []string{"cloud", "cloud_ref.name"} {
+		if name := query.Get(key); name != "" && s.refName(cloudRef) != name {
+			return false
+		}
+	}
+	return true
+}
+
+// render returns a copy of obj as sent by a controller, include_name appends
+// the name of the referenced object to every ref: <url>#<name>
+func (s *Server) render(obj map[string]interface{}, includeName bool) map[string]interface{} {
+	var out map[string]interface{}
+	_ = fromMap(obj, &out)
+	if includeName {
+		s.addRefNames(out)
+	}
+	return out
+}
+
+func (s *Server) addRefNames(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && isRefKey(key) {
+				if name := s.refName(ref); name != "" {
+					v[key] = ref + "#" + name
+				}
+				continue
+			}
+			if refs, ok := value.([]interface{}); ok && isRefKey(key) {
+				for i := range refs {
+					if ref, ok := refs[i].(string); ok {
+						if name := s.refName(ref); name != "" {
+							refs[i] = ref + "#" + name
+						}
+					}
+				}
+				continue
+			}
+			s.addRefNames(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			s.addRefNames(value)
+		}
+	}
+}
+
+// stripRefNames drops the names appended to refs by include_name, so that
+// objects read with include_name can be sent back as they are
+func stripRefNames(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && isRefKey(key) {
+				v[key] = strings.SplitN(ref, "#", 2)[0]
+				continue
+			}
+			if refs, ok := value.([]interface{}); ok && isRefKey(key) {
+				for i := range refs {
+					if ref, ok := refs[i].(string); ok {
+						refs[i] = strings.SplitN(ref, "#", 2)[0]
+					}
+				}
+				continue
+			}
+			stripRefNames(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			stripRefNames(value)
+		}
+	}
+}
+
+func (s *Server) ref(kind, uuid string) string {
+	return s.URL + "/api/" + kind + "/" + uuid
+}
+
+// refName returns the name of the object a ref points to, or "" if it
+// doesn't exist
+func (s *Server) refName(ref string) string {
+	u, err := url.Parse(strings.SplitN(ref, "#", 2)[0])
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "api" {
+		return ""
+	}
+	if i := s.indexOf(parts[1], parts[2]); i >= 0 {
+		name, _ := s.objects[parts[1]][i]["name"].(string)
+		return name
+	}
+	return ""
+}
+
+func (s *Server) indexOf(kind, uuid string) int {
+	for i, obj := range s.objects[kind] {
+		if obj["uuid"] == uuid {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) indexOfName(kind, name string) int {
+	for i, obj := range s.objects[kind] {
+		if obj["name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// referrers returns the objects which refer to the object by one of their
+// top-level refs but the tenant one, the controller refuses to delete a
+// referred object
+func (s *Server) referrers(uuid string) []string {
+	var res []string
+	for _, kind := range Kinds {
+		for _, obj := range s.objects[kind] {
+			for key, value := range obj {
+				if ref, ok := value.(string); ok && key != "url" && key != "tenant_ref" && isRefKey(key) && refUUID(ref) == uuid {
+					name, _ := obj["name"].(string)
+					res = append(res, kind+" "+name)
+				}
+			}
+		}
+	}
+	return res
+}
+
+func refUUID(ref string) string {
+	return aviclient.GetUUIDFromRef(strings.SplitN(ref, "#", 2)[0])
+}
+
+func isRefKey(key string) bool {
+	return key == "url" || strings.HasSuffix(key, "_ref") || strings.HasSuffix(key, "_refs")
+}
+
+func isKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// alreadyExists mimics the controller's uniqueness errors, the one of users
+// is matched by aviclient.IsAviUserAlreadyExistsError
+func alreadyExists(kind string) string {
+	if kind == "user" {
+		return "User with this Username already exists."
+	}
+	return fmt.Sprintf("%s with this Name already exists.", kind)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	return m, fromMap(obj, &m)
+}
+
+func fromMap(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -12,6 +12,7 @@ import (
 
 	akoov1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
 	networkv1alpha1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1alpha1"
+	akoov1beta1 "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/api/v1beta1"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig"
 	adccluster "github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/akodeploymentconfig/cluster"
 	"github.com/vmware-tanzu/load-balancer-operator-for-kubernetes/controllers/cluster"
@@ -29,6 +30,10 @@ var AddAllToSchemeFunc builder.AddToSchemeFunc = func(scheme *runtime.Scheme) (e
 	if err != nil {
 		return err
 	}
+	err = akoov1beta1.AddToScheme(scheme)
+	if err != nil {
+		return err
+	}
 	err = akov1beta1.AddToScheme(scheme)
 	if err != nil {
 		return err
@@ -46,14 +51,15 @@ var AddAllToSchemeFunc builder.AddToSchemeFunc = func(scheme *runtime.Scheme) (e
 
 var AddAKODeploymentConfigAndClusterControllerToMgrFunc builder.AddToManagerFunc = func(mgr ctrlmgr.Manager) error {
 	rec := &akodeploymentconfig.AKODeploymentConfigReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("AKODeploymentConfig"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("AKODeploymentConfig"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(akoov1alpha1.AKODeploymentConfigControllerName),
 	}
 	builder.FakeAvi = aviclient.NewFakeAviClient()
 	rec.SetAviClient(builder.FakeAvi)
 
-	adcClusterReconciler := adccluster.NewReconciler(rec.Client, rec.Log, rec.Scheme)
+	adcClusterReconciler := adccluster.NewReconciler(rec.Client, rec.Log, rec.Scheme, rec.Recorder)
 	adcClusterReconciler.GetRemoteClient = adccluster.GetFakeRemoteClient
 	rec.ClusterReconciler = adcClusterReconciler
 
@@ -63,9 +69,10 @@ var AddAKODeploymentConfigAndClusterControllerToMgrFunc builder.AddToManagerFunc
 
 	// involve the cluster controller as well for the resetting skip-default-adc label test
 	if err := (&cluster.ClusterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Cluster"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Cluster"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(akoov1alpha1.ClusterControllerName),
 	}).SetupWithManager(mgr); err != nil {
 		return err
 	}
@@ -0,0 +1,188 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// AkoRolePermissionMap maps the resources of the ako-essential-role to their
+// access type
+var AkoRolePermissionMap = map[string]string{
+	"PERMISSION_VIRTUALSERVICE":                "WRITE_ACCESS",
+	"PERMISSION_POOL":                          "WRITE_ACCESS",
+	"PERMISSION_POOLGROUP":                     "WRITE_ACCESS",
+	"PERMISSION_HTTPPOLICYSET":                 "WRITE_ACCESS",
+	"PERMISSION_NETWORKSECURITYPOLICY":         "WRITE_ACCESS",
+	"PERMISSION_AUTOSCALE":                     "WRITE_ACCESS",
+	"PERMISSION_DNSPOLICY":                     "WRITE_ACCESS",
+	"PERMISSION_NETWORKPROFILE":                "WRITE_ACCESS",
+	"PERMISSION_APPLICATIONPROFILE":            "WRITE_ACCESS",
+	"PERMISSION_APPLICATIONPERSISTENCEPROFILE": "WRITE_ACCESS",
+	"PERMISSION_HEALTHMONITOR":                 "WRITE_ACCESS",
+	"PERMISSION_ANALYTICSPROFILE":              "WRITE_ACCESS",
+	"PERMISSION_IPAMDNSPROVIDERPROFILE":        "WRITE_ACCESS",
+	"PERMISSION_CUSTOMIPAMDNSPROFILE":          "WRITE_ACCESS",
+	"PERMISSION_TRAFFICCLONEPROFILE":           "WRITE_ACCESS",
+	"PERMISSION_VSDATASCRIPTSET":               "WRITE_ACCESS",
+	"PERMISSION_PKIPROFILE":                    "WRITE_ACCESS",
+	"PERMISSION_SSLKEYANDCERTIFICATE":          "WRITE_ACCESS",
+	"PERMISSION_SERVICEENGINEGROUP":            "WRITE_ACCESS",
+	"PERMISSION_NETWORK":                       "WRITE_ACCESS",
+	"PERMISSION_VRFCONTEXT":                    "WRITE_ACCESS",
+	"PERMISSION_L4POLICYSET":                   "WRITE_ACCESS",
+
+	"PERMISSION_IPADDRGROUP":                  "READ_ACCESS",
+	"PERMISSION_STRINGGROUP":                  "READ_ACCESS",
+	"PERMISSION_PROTOCOLPARSER":               "READ_ACCESS",
+	"PERMISSION_SSLPROFILE":                   "READ_ACCESS",
+	"PERMISSION_AUTHPROFILE":                  "READ_ACCESS",
+	"PERMISSION_PINGACCESSAGENT":              "READ_ACCESS",
+	"PERMISSION_CERTIFICATEMANAGEMENTPROFILE": "READ_ACCESS",
+	"PERMISSION_HARDWARESECURITYMODULEGROUP":  "READ_ACCESS",
+	"PERMISSION_SSOPOLICY":                    "READ_ACCESS",
+	"PERMISSION_WAFPROFILE":                   "READ_ACCESS",
+	"PERMISSION_WAFPOLICY":                    "READ_ACCESS",
+	"PERMISSION_CLOUD":                        "READ_ACCESS",
+	"PERMISSION_SYSTEMCONFIGURATION":          "READ_ACCESS",
+	"PERMISSION_CONTROLLER":                   "READ_ACCESS",
+	"PERMISSION_TENANT":                       "READ_ACCESS",
+
+	"PERMISSION_NATPOLICY":         "NO_ACCESS",
+	"PERMISSION_WAFPOLICYPSMGROUP": "NO_ACCESS",
+	"PERMISSION_ERRORPAGEPROFILE":  "NO_ACCESS",
+	"PERMISSION_ERRORPAGEBODY":     "NO_ACCESS",
+	"PERMISSION_ALERTCONFIG":       "NO_ACCESS",
+	"PERMISSION_ALERT":             "NO_ACCESS",
+	"PERMISSION_ACTIONGROUPCONFIG": "NO_ACCESS",
+	"PERMISSION_ALERTSYSLOGCONFIG": "NO_ACCESS",
+	"PERMISSION_ALERTEMAILCONFIG":  "NO_ACCESS",
+	"PERMISSION_SNMPTRAPPROFILE":   "NO_ACCESS",
+	"PERMISSION_TRAFFIC_CAPTURE":   "NO_ACCESS",
+	"PERMISSION_SERVICEENGINE":     "NO_ACCESS",
+	"PERMISSION_USER_CREDENTIAL":   "NO_ACCESS",
+	"PERMISSION_REBOOT":            "NO_ACCESS",
+	"PERMISSION_UPGRADE":           "NO_ACCESS",
+	"PERMISSION_TECHSUPPORT":       "NO_ACCESS",
+	"PERMISSION_INTERNAL":          "NO_ACCESS",
+	"PERMISSION_CONTROLLERSITE":    "NO_ACCESS",
+	"PERMISSION_IMAGE":             "NO_ACCESS",
+	"PERMISSION_USER":              "NO_ACCESS",
+	"PERMISSION_ROLE":              "NO_ACCESS",
+	"PERMISSION_GSLB":              "NO_ACCESS",
+	"PERMISSION_GSLBSERVICE":       "NO_ACCESS",
+	"PERMISSION_GSLBGEODBPROFILE":  "NO_ACCESS",
+}
+
+// akoOptionalPermissions are the permissions of AkoRolePermissionMap a role
+// profile can remove, AKO only needs them for L7
+var akoOptionalPermissions = sets.New[string](
+	"PERMISSION_PKIPROFILE",
+	"PERMISSION_HTTPPOLICYSET",
+	"PERMISSION_SSOPOLICY",
+	"PERMISSION_AUTHPROFILE",
+	"PERMISSION_PINGACCESSAGENT",
+	"PERMISSION_WAFPROFILE",
+	"PERMISSION_WAFPOLICY",
+)
+
+// permissionAccessLevels orders the access types of the permissions
+var permissionAccessLevels = map[string]int{
+	"NO_ACCESS":    0,
+	"READ_ACCESS":  1,
+	"WRITE_ACCESS": 2,
+}
+
+// RolePermission is the access type of an Avi role to a resource
+type RolePermission struct {
+	Resource string
+	Type     string
+}
+
+// RoleProfile describes how the permissions of an Avi role differ from
+// AkoRolePermissionMap
+type RoleProfile struct {
+	Name string
+	// Permissions are added to the default ones or downgrade the optional
+	// ones
+	Permissions []RolePermission
+	// RemovedPermissions are optional default permissions
+	RemovedPermissions []string
+}
+
+// PermissionMap returns the permissions of the Avi role of the profile, i.e.
+// AkoRolePermissionMap with the changes of the profile. Removed permissions
+// are kept with NO_ACCESS so that drift correction takes them away from an
+// existing role too.
+func (p RoleProfile) PermissionMap() (map[string]string, error) {
+	permissions := make(map[string]string, len(AkoRolePermissionMap))
+	for resource, permissionType := range AkoRolePermissionMap {
+		permissions[resource] = permissionType
+	}
+	for _, permission := range p.Permissions {
+		if !strings.HasPrefix(permission.Resource, "PERMISSION_") {
+			return nil, fmt.Errorf("role profile %s has unknown permission %s", p.Name, permission.Resource)
+		}
+		if _, ok := permissionAccessLevels[permission.Type]; !ok {
+			return nil, fmt.Errorf("role profile %s has unknown access type %s for %s", p.Name, permission.Type, permission.Resource)
+		}
+		if defaultType, ok := AkoRolePermissionMap[permission.Resource]; ok {
+			level, defaultLevel := permissionAccessLevels[permission.Type], permissionAccessLevels[defaultType]
+			if level > defaultLevel {
+				return nil, fmt.Errorf("role profile %s can't upgrade %s from %s to %s",
+					p.Name, permission.Resource, defaultType, permission.Type)
+			}
+			if level < defaultLevel && !akoOptionalPermissions.Has(permission.Resource) {
+				return nil, fmt.Errorf("role profile %s can't downgrade %s from %s to %s, AKO requires it",
+					p.Name, permission.Resource, defaultType, permission.Type)
+			}
+		}
+		permissions[permission.Resource] = permission.Type
+	}
+	for _, resource := range p.RemovedPermissions {
+		if _, ok := AkoRolePermissionMap[resource]; !ok {
+			return nil, fmt.Errorf("role profile %s can't remove unknown permission %s", p.Name, resource)
+		}
+		if !akoOptionalPermissions.Has(resource) {
+			return nil, fmt.Errorf("role profile %s can't remove %s, AKO requires it", p.Name, resource)
+		}
+		permissions[resource] = "NO_ACCESS"
+	}
+	return permissions, nil
+}
+
+// Validate returns an error when the profile can't be turned into an Avi
+// role AKO works with
+func (p RoleProfile) Validate() error {
+	_, err := p.PermissionMap()
+	return err
+}
+
+// Hash returns a short hash of the changes the profile makes to the
+// default permissions, profiles making the same changes have the same hash
+func (p RoleProfile) Hash() string {
+	changes := map[string]string{}
+	for _, permission := range p.Permissions {
+		changes[permission.Resource] = permission.Type
+	}
+	for _, resource := range p.RemovedPermissions {
+		changes[resource] = "NO_ACCESS"
+	}
+	resources := make([]string, 0, len(changes))
+	for resource := range changes {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	h := sha256.New()
+	for _, resource := range resources {
+		fmt.Fprintf(h, "%s=%s\n", resource, changes[resource])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
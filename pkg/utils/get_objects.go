@@ -7,6 +7,17 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// AviUserName returns the name of the Avi user of the cluster
+func AviUserName(cluster *clusterv1.Cluster) string {
+	return cluster.Name + "-" + cluster.Namespace + "-ako-user"
+}
+
+// AviAlternateUserName returns the name of the Avi user the password
+// rotations of the cluster alternate with the AviUserName one
+func AviAlternateUserName(cluster *clusterv1.Cluster) string {
+	return AviUserName(cluster) + "-alt"
+}
+
 func AVIUserSecretName(cluster *clusterv1.Cluster) string {
 	return cluster.Name + "-avi-credentials"
 }
@@ -18,3 +29,9 @@ func AKOAddonSecretName(cluster *clusterv1.Cluster) string {
 func AKOAddonSecretNameForClusterClass(cluster *clusterv1.Cluster) string {
 	return cluster.Name + "-load-balancer-and-ingress-service-data-values"
 }
+
+// AKOClusterResourceSetName returns the name of the ClusterResourceSet and of
+// the Secret delivering AKO to the cluster
+func AKOClusterResourceSetName(cluster *clusterv1.Cluster) string {
+	return cluster.Name + "-load-balancer-and-ingress-service-resource-set"
+}
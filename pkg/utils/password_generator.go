@@ -4,8 +4,10 @@
 package utils
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
 )
 
 const (
@@ -15,40 +17,180 @@ const (
 	lowercases = "abcdefghijklmnopqrstuvwxyz"
 )
 
-// GenereatePassword generate a random password
-func GenereatePassword(length int, mustHaveLowercase, mustHaveUppercase, mustHaveSpecial, mustHaveNumeric bool) string {
-	rand.Seed(time.Now().UnixNano())
-	all := uppercases + lowercases + numerics + specials
-	buf := make([]byte, length)
-	i := 0
+// The character classes a password can be made of
+const (
+	CharacterClassLowercase = "Lowercase"
+	CharacterClassUppercase = "Uppercase"
+	CharacterClassDigit     = "Digit"
+	CharacterClassSpecial   = "Special"
+)
+
+const (
+	// DefaultPasswordLength is the length of the passwords when the policy
+	// doesn't tell
+	DefaultPasswordLength = 16
+	// MaxPasswordLength is the longest password which can be generated
+	MaxPasswordLength = 128
+)
+
+var characterClasses = map[string]string{
+	CharacterClassLowercase: lowercases,
+	CharacterClassUppercase: uppercases,
+	CharacterClassDigit:     numerics,
+	CharacterClassSpecial:   specials,
+}
+
+// CharacterClasses lists the character classes in the order they're added
+// to a password
+var CharacterClasses = []string{
+	CharacterClassLowercase,
+	CharacterClassUppercase,
+	CharacterClassDigit,
+	CharacterClassSpecial,
+}
+
+// PasswordPolicy describes the generated passwords. The zero value generates
+// passwords of DefaultPasswordLength made of all the character classes.
+type PasswordPolicy struct {
+	// Length of the passwords
+	Length int
+	// CharacterClasses the passwords are made of, with at least one
+	// character of each
+	CharacterClasses []string
+	// ExcludedCharacters are never used
+	ExcludedCharacters string
+}
+
+// Merge returns the policy with the fields which aren't set taken from
+// defaults
+func (p PasswordPolicy) Merge(defaults PasswordPolicy) PasswordPolicy {
+	if p.Length == 0 {
+		p.Length = defaults.Length
+	}
+	if len(p.CharacterClasses) == 0 {
+		p.CharacterClasses = defaults.CharacterClasses
+	}
+	if p.ExcludedCharacters == "" {
+		p.ExcludedCharacters = defaults.ExcludedCharacters
+	}
+	return p
+}
 
-	if mustHaveLowercase {
-		buf[i] = lowercases[rand.Intn(len(lowercases))]
-		i++
+// alphabets returns the characters left in each class of the policy
+func (p PasswordPolicy) alphabets() ([]string, error) {
+	classes := p.CharacterClasses
+	if len(classes) == 0 {
+		classes = CharacterClasses
 	}
+	seen := map[string]bool{}
+	var alphabets []string
+	for _, class := range classes {
+		chars, ok := characterClasses[class]
+		if !ok {
+			return nil, fmt.Errorf("unknown character class %q, must be one of %s", class, strings.Join(CharacterClasses, ", "))
+		}
+		if seen[class] {
+			continue
+		}
+		seen[class] = true
+		chars = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.ExcludedCharacters, r) {
+				return -1
+			}
+			return r
+		}, chars)
+		if chars == "" {
+			return nil, fmt.Errorf("all the characters of the %s class are excluded", class)
+		}
+		alphabets = append(alphabets, chars)
+	}
+	return alphabets, nil
+}
 
-	if mustHaveUppercase {
-		buf[i] = uppercases[rand.Intn(len(uppercases))]
-		i++
+func (p PasswordPolicy) length() int {
+	if p.Length == 0 {
+		return DefaultPasswordLength
 	}
+	return p.Length
+}
 
-	if mustHaveSpecial {
-		buf[i] = specials[rand.Intn(len(specials))]
-		i++
+// Validate checks passwords can be generated with the policy
+func (p PasswordPolicy) Validate() error {
+	alphabets, err := p.alphabets()
+	if err != nil {
+		return err
 	}
+	if length := p.length(); length < len(alphabets) || length > MaxPasswordLength {
+		return fmt.Errorf("the length of the passwords must be between %d and %d", len(alphabets), MaxPasswordLength)
+	}
+	return nil
+}
 
-	if mustHaveNumeric {
-		buf[i] = numerics[rand.Intn(len(numerics))]
-		i++
+// CheckComplexity checks the passwords of the policy meet the password
+// complexity settings of an Avi Controller: their minimum length and, when
+// the strength check is enabled, three character classes at least
+func (p PasswordPolicy) CheckComplexity(minLength int, strengthCheck bool) error {
+	alphabets, err := p.alphabets()
+	if err != nil {
+		return err
 	}
+	if p.length() < minLength {
+		return fmt.Errorf("the passwords are %d characters long, the Avi Controller requires %d", p.length(), minLength)
+	}
+	if strengthCheck && len(alphabets) < 3 {
+		return fmt.Errorf("the passwords are made of %d character classes, the password strength check of the Avi Controller requires 3", len(alphabets))
+	}
+	return nil
+}
 
-	for ; i < length; i++ {
-		buf[i] = all[rand.Intn(len(all))]
+// GeneratePassword generates a random password following the policy with
+// crypto/rand
+func GeneratePassword(p PasswordPolicy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	alphabets, _ := p.alphabets()
+	buf := make([]byte, p.length())
+	i := 0
+	for ; i < len(alphabets); i++ {
+		c, err := randomChar(alphabets[i])
+		if err != nil {
+			return "", err
+		}
+		buf[i] = c
+	}
+	all := strings.Join(alphabets, "")
+	for ; i < len(buf); i++ {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = c
 	}
 
-	rand.Shuffle(len(buf), func(i, j int) {
+	// Fisher-Yates so the characters of each class aren't always first
+	for i := len(buf) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
 		buf[i], buf[j] = buf[j], buf[i]
-	})
+	}
+	return string(buf), nil
+}
 
-	return string(buf)
+func randomChar(alphabet string) (byte, error) {
+	i, err := randomInt(len(alphabet))
+	if err != nil {
+		return 0, err
+	}
+	return alphabet[i], nil
+}
+
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
 }
@@ -12,24 +12,72 @@ import (
 )
 
 var _ = ginkgo.Describe("Test password generate", func() {
+	generate := func(policy utils.PasswordPolicy) string {
+		pwd, err := utils.GeneratePassword(policy)
+		Expect(err).NotTo(HaveOccurred())
+		return pwd
+	}
+
 	ginkgo.It("should contain lowercase", func() {
-		pwd := utils.GenereatePassword(5, true, false, false, false)
-		Expect(strings.ContainsAny(pwd, "abcdefghijklmnopqrstuvwxyz"))
+		pwd := generate(utils.PasswordPolicy{Length: 5, CharacterClasses: []string{utils.CharacterClassLowercase}})
+		Expect(strings.Trim(pwd, "abcdefghijklmnopqrstuvwxyz")).To(BeEmpty())
 		Expect(len(pwd)).To(Equal(5))
 	})
 	ginkgo.It("should contain uppercase", func() {
-		pwd := utils.GenereatePassword(5, false, true, false, false)
-		Expect(strings.ContainsAny(pwd, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+		pwd := generate(utils.PasswordPolicy{Length: 5, CharacterClasses: []string{utils.CharacterClassUppercase}})
+		Expect(strings.Trim(pwd, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")).To(BeEmpty())
 		Expect(len(pwd)).To(Equal(5))
 	})
 	ginkgo.It("should contain specials", func() {
-		pwd := utils.GenereatePassword(5, false, false, true, false)
-		Expect(strings.ContainsAny(pwd, "~=+%^*/()[]{}/!@#$?|"))
+		pwd := generate(utils.PasswordPolicy{Length: 5, CharacterClasses: []string{utils.CharacterClassSpecial}})
+		Expect(strings.Trim(pwd, "~=+%^*/()[]{}/!@#$?|")).To(BeEmpty())
 		Expect(len(pwd)).To(Equal(5))
 	})
 	ginkgo.It("should contain digits", func() {
-		pwd := utils.GenereatePassword(5, false, false, false, true)
-		Expect(strings.ContainsAny(pwd, "0123456789"))
+		pwd := generate(utils.PasswordPolicy{Length: 5, CharacterClasses: []string{utils.CharacterClassDigit}})
+		Expect(strings.Trim(pwd, "0123456789")).To(BeEmpty())
 		Expect(len(pwd)).To(Equal(5))
 	})
+	ginkgo.It("should contain every class by default", func() {
+		for i := 0; i < 20; i++ {
+			pwd := generate(utils.PasswordPolicy{})
+			Expect(pwd).To(HaveLen(utils.DefaultPasswordLength))
+			Expect(strings.ContainsAny(pwd, "abcdefghijklmnopqrstuvwxyz")).To(BeTrue())
+			Expect(strings.ContainsAny(pwd, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")).To(BeTrue())
+			Expect(strings.ContainsAny(pwd, "0123456789")).To(BeTrue())
+			Expect(strings.ContainsAny(pwd, "~=+%^*/()[]{}/!@#$?|")).To(BeTrue())
+		}
+	})
+	ginkgo.It("should not contain the excluded characters", func() {
+		for i := 0; i < 20; i++ {
+			pwd := generate(utils.PasswordPolicy{Length: 64, ExcludedCharacters: "0Oo1lI|/"})
+			Expect(strings.ContainsAny(pwd, "0Oo1lI|/")).To(BeFalse())
+		}
+	})
+	ginkgo.It("should not repeat itself", func() {
+		Expect(generate(utils.PasswordPolicy{})).NotTo(Equal(generate(utils.PasswordPolicy{})))
+	})
+	ginkgo.It("should refuse policies which can't be met", func() {
+		_, err := utils.GeneratePassword(utils.PasswordPolicy{Length: 3})
+		Expect(err).To(MatchError(ContainSubstring("between 4 and 128")))
+		_, err = utils.GeneratePassword(utils.PasswordPolicy{CharacterClasses: []string{"Emoji"}})
+		Expect(err).To(MatchError(ContainSubstring("unknown character class")))
+		_, err = utils.GeneratePassword(utils.PasswordPolicy{
+			CharacterClasses:   []string{utils.CharacterClassDigit},
+			ExcludedCharacters: "0123456789",
+		})
+		Expect(err).To(MatchError(ContainSubstring("all the characters of the Digit class are excluded")))
+	})
+	ginkgo.It("should check the complexity settings of the Avi Controller", func() {
+		policy := utils.PasswordPolicy{Length: 8}
+		Expect(policy.CheckComplexity(8, true)).To(Succeed())
+		Expect(policy.CheckComplexity(12, false)).To(MatchError(ContainSubstring("requires 12")))
+		policy.CharacterClasses = []string{utils.CharacterClassLowercase, utils.CharacterClassDigit}
+		Expect(policy.CheckComplexity(8, false)).To(Succeed())
+		Expect(policy.CheckComplexity(8, true)).To(MatchError(ContainSubstring("strength check")))
+	})
+	ginkgo.It("should merge the defaults", func() {
+		defaults := utils.PasswordPolicy{Length: 20, ExcludedCharacters: "|"}
+		Expect(utils.PasswordPolicy{Length: 12}.Merge(defaults)).To(Equal(utils.PasswordPolicy{Length: 12, ExcludedCharacters: "|"}))
+	})
 })